@@ -0,0 +1,287 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+// Package arrowconv converts entity columns to and from Apache Arrow arrays and records.
+//
+// Fixed width scalar and vector columns share memory with arrow arrays in both directions,
+// float vectors are mapped to FixedSizeList<float32> and binary vectors to FixedSizeBinary.
+// VarChar columns are mapped to String arrays and JSON columns to Binary arrays
+// with field metadata marking the milvus data type.
+//
+// arrowconv is a standalone module, so the arrow dependency is only pulled in by its users.
+package arrowconv
+
+import (
+	"fmt"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+// Field metadata keys set by Field.
+const (
+	MetaFieldType = "milvus.type"    // milvus field type name
+	MetaDynamic   = "milvus.dynamic" // "true" for dynamic field column
+)
+
+// Field returns the arrow field for column.
+func Field(column entity.Column) (arrow.Field, error) {
+	dt, err := dataType(column)
+	if err != nil {
+		return arrow.Field{}, err
+	}
+	keys := []string{MetaFieldType}
+	values := []string{column.Type().Name()}
+	if jc, ok := column.(*entity.ColumnJSONBytes); ok && jc.IsDynamic() {
+		keys = append(keys, MetaDynamic)
+		values = append(values, "true")
+	}
+	_, nullable := column.(*entity.ColumnDynamic)
+	return arrow.Field{
+		Name:     column.Name(),
+		Type:     dt,
+		Nullable: nullable,
+		Metadata: arrow.NewMetadata(keys, values),
+	}, nil
+}
+
+func dataType(column entity.Column) (arrow.DataType, error) {
+	switch c := column.(type) {
+	case *entity.ColumnBool:
+		return arrow.FixedWidthTypes.Boolean, nil
+	case *entity.ColumnInt8:
+		return arrow.PrimitiveTypes.Int8, nil
+	case *entity.ColumnInt16:
+		return arrow.PrimitiveTypes.Int16, nil
+	case *entity.ColumnInt32:
+		return arrow.PrimitiveTypes.Int32, nil
+	case *entity.ColumnInt64:
+		return arrow.PrimitiveTypes.Int64, nil
+	case *entity.ColumnFloat:
+		return arrow.PrimitiveTypes.Float32, nil
+	case *entity.ColumnDouble:
+		return arrow.PrimitiveTypes.Float64, nil
+	case *entity.ColumnVarChar, *entity.ColumnString:
+		return arrow.BinaryTypes.String, nil
+	case *entity.ColumnJSONBytes, *entity.ColumnDynamic:
+		return arrow.BinaryTypes.Binary, nil
+	case *entity.ColumnFloatVector:
+		return arrow.FixedSizeListOf(int32(c.Dim()), arrow.PrimitiveTypes.Float32), nil
+	case *entity.ColumnBinaryVector:
+		return &arrow.FixedSizeBinaryType{ByteWidth: c.Dim() / 8}, nil
+	default:
+		return nil, fmt.Errorf("column %s of type %T not supported", column.Name(), column)
+	}
+}
+
+// ToArray converts column into arrow array, the caller shall release the array.
+// Fixed width scalar and vector values are shared with the column without copy.
+// Values missing in ColumnDynamic are null.
+func ToArray(mem memory.Allocator, column entity.Column) (arrow.Array, error) {
+	if column == nil {
+		return nil, errors.New("column cannot be nil")
+	}
+	switch c := column.(type) {
+	case *entity.ColumnBool:
+		b := array.NewBooleanBuilder(mem)
+		defer b.Release()
+		b.AppendValues(c.Data(), nil)
+		return b.NewArray(), nil
+	case *entity.ColumnInt8:
+		return primitiveArray(arrow.PrimitiveTypes.Int8, len(c.Data()), arrow.Int8Traits.CastToBytes(c.Data())), nil
+	case *entity.ColumnInt16:
+		return primitiveArray(arrow.PrimitiveTypes.Int16, len(c.Data()), arrow.Int16Traits.CastToBytes(c.Data())), nil
+	case *entity.ColumnInt32:
+		return primitiveArray(arrow.PrimitiveTypes.Int32, len(c.Data()), arrow.Int32Traits.CastToBytes(c.Data())), nil
+	case *entity.ColumnInt64:
+		return primitiveArray(arrow.PrimitiveTypes.Int64, len(c.Data()), arrow.Int64Traits.CastToBytes(c.Data())), nil
+	case *entity.ColumnFloat:
+		return primitiveArray(arrow.PrimitiveTypes.Float32, len(c.Data()), arrow.Float32Traits.CastToBytes(c.Data())), nil
+	case *entity.ColumnDouble:
+		return primitiveArray(arrow.PrimitiveTypes.Float64, len(c.Data()), arrow.Float64Traits.CastToBytes(c.Data())), nil
+	case *entity.ColumnVarChar:
+		b := array.NewStringBuilder(mem)
+		defer b.Release()
+		b.AppendValues(c.Data(), nil)
+		return b.NewArray(), nil
+	case *entity.ColumnString:
+		b := array.NewStringBuilder(mem)
+		defer b.Release()
+		b.AppendValues(c.Data(), nil)
+		return b.NewArray(), nil
+	case *entity.ColumnJSONBytes:
+		b := array.NewBinaryBuilder(mem, arrow.BinaryTypes.Binary)
+		defer b.Release()
+		b.AppendValues(c.Data(), nil)
+		return b.NewArray(), nil
+	case *entity.ColumnDynamic:
+		b := array.NewBinaryBuilder(mem, arrow.BinaryTypes.Binary)
+		defer b.Release()
+		for i := 0; i < c.Len(); i++ {
+			raw, err := c.Get(i)
+			if err != nil {
+				b.AppendNull()
+				continue
+			}
+			b.Append([]byte(raw.(string)))
+		}
+		return b.NewArray(), nil
+	case *entity.ColumnFloatVector:
+		flat := c.FlatData()
+		values := primitiveArray(arrow.PrimitiveTypes.Float32, len(flat), arrow.Float32Traits.CastToBytes(flat))
+		defer values.Release()
+		data := array.NewData(arrow.FixedSizeListOf(int32(c.Dim()), arrow.PrimitiveTypes.Float32), c.Len(),
+			[]*memory.Buffer{nil}, []arrow.ArrayData{values.Data()}, 0, 0)
+		defer data.Release()
+		return array.NewFixedSizeListData(data), nil
+	case *entity.ColumnBinaryVector:
+		data := array.NewData(&arrow.FixedSizeBinaryType{ByteWidth: c.Dim() / 8}, c.Len(),
+			[]*memory.Buffer{nil, memory.NewBufferBytes(c.FlatData())}, nil, 0, 0)
+		defer data.Release()
+		return array.MakeFromData(data), nil
+	default:
+		return nil, fmt.Errorf("column %s of type %T not supported", column.Name(), column)
+	}
+}
+
+// primitiveArray wraps bytes as fixed width array without copy.
+func primitiveArray(dt arrow.DataType, n int, bs []byte) arrow.Array {
+	data := array.NewData(dt, n, []*memory.Buffer{nil, memory.NewBufferBytes(bs)}, nil, 0, 0)
+	defer data.Release()
+	return array.MakeFromData(data)
+}
+
+// FromArray converts arrow array into column with provided name.
+// Fixed width scalar and vector values share memory with the array, which must be kept unreleased
+// while the column is in use. Null values are not supported.
+// String arrays are converted into VarChar columns and Binary arrays into JSON columns.
+func FromArray(name string, arr arrow.Array) (entity.Column, error) {
+	if arr == nil {
+		return nil, errors.New("array cannot be nil")
+	}
+	if arr.NullN() > 0 {
+		return nil, fmt.Errorf("array %s contains %d null values", name, arr.NullN())
+	}
+	switch a := arr.(type) {
+	case *array.Boolean:
+		values := make([]bool, a.Len())
+		for i := range values {
+			values[i] = a.Value(i)
+		}
+		return entity.NewColumnBool(name, values), nil
+	case *array.Int8:
+		return entity.NewColumnInt8(name, a.Int8Values()), nil
+	case *array.Int16:
+		return entity.NewColumnInt16(name, a.Int16Values()), nil
+	case *array.Int32:
+		return entity.NewColumnInt32(name, a.Int32Values()), nil
+	case *array.Int64:
+		return entity.NewColumnInt64(name, a.Int64Values()), nil
+	case *array.Float32:
+		return entity.NewColumnFloat(name, a.Float32Values()), nil
+	case *array.Float64:
+		return entity.NewColumnDouble(name, a.Float64Values()), nil
+	case *array.String:
+		values := make([]string, a.Len())
+		for i := range values {
+			values[i] = a.Value(i)
+		}
+		return entity.NewColumnVarChar(name, values), nil
+	case *array.Binary:
+		values := make([][]byte, a.Len())
+		for i := range values {
+			values[i] = a.Value(i)
+		}
+		return entity.NewColumnJSONBytes(name, values), nil
+	case *array.FixedSizeList:
+		dt := a.DataType().(*arrow.FixedSizeListType)
+		child, ok := a.ListValues().(*array.Float32)
+		if !ok {
+			return nil, fmt.Errorf("array %s of type %s not supported, expected fixed size list of float32", name, dt)
+		}
+		dim := int(dt.Len())
+		start := a.Data().Offset() * dim
+		flat := child.Float32Values()[start : start+a.Len()*dim]
+		return entity.NewColumnFloatVectorFlat(name, dim, flat), nil
+	case *array.FixedSizeBinary:
+		width := a.DataType().(*arrow.FixedSizeBinaryType).ByteWidth
+		var flat []byte
+		if a.Len() > 0 {
+			start := a.Data().Offset() * width
+			flat = a.Data().Buffers()[1].Bytes()[start : start+a.Len()*width]
+		}
+		return entity.NewColumnBinaryVectorFlat(name, width*8, flat), nil
+	default:
+		return nil, fmt.Errorf("array %s of type %s not supported", name, arr.DataType())
+	}
+}
+
+// ToRecord converts columns into arrow record, the caller shall release the record.
+// All columns must have the same length.
+func ToRecord(mem memory.Allocator, columns []entity.Column) (arrow.Record, error) {
+	fields := make([]arrow.Field, 0, len(columns))
+	arrs := make([]arrow.Array, 0, len(columns))
+	defer func() {
+		for _, arr := range arrs {
+			arr.Release()
+		}
+	}()
+	rows := -1
+	for _, column := range columns {
+		if column == nil {
+			return nil, errors.New("column cannot be nil")
+		}
+		if rows >= 0 && column.Len() != rows {
+			return nil, fmt.Errorf("column %s length %d not match %d", column.Name(), column.Len(), rows)
+		}
+		rows = column.Len()
+		field, err := Field(column)
+		if err != nil {
+			return nil, err
+		}
+		arr, err := ToArray(mem, column)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+		arrs = append(arrs, arr)
+	}
+	if rows < 0 {
+		rows = 0
+	}
+	return array.NewRecord(arrow.NewSchema(fields, nil), arrs, int64(rows)), nil
+}
+
+// FromRecord converts arrow record into columns, columns are named by record field names.
+// Binary fields marked dynamic in field metadata are converted into dynamic field json columns.
+func FromRecord(rec arrow.Record) ([]entity.Column, error) {
+	if rec == nil {
+		return nil, errors.New("record cannot be nil")
+	}
+	columns := make([]entity.Column, 0, rec.NumCols())
+	for i, field := range rec.Schema().Fields() {
+		column, err := FromArray(field.Name, rec.Column(i))
+		if err != nil {
+			return nil, err
+		}
+		if jc, ok := column.(*entity.ColumnJSONBytes); ok {
+			if idx := field.Metadata.FindKey(MetaDynamic); idx >= 0 && field.Metadata.Values()[idx] == "true" {
+				jc.WithIsDynamic(true)
+			}
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
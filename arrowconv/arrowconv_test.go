@@ -0,0 +1,152 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package arrowconv
+
+import (
+	"testing"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+func TestRecordRoundTrip(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	columns := []entity.Column{
+		entity.NewColumnBool("bool", []bool{true, false}),
+		entity.NewColumnInt8("int8", []int8{1, 2}),
+		entity.NewColumnInt16("int16", []int16{1, 2}),
+		entity.NewColumnInt32("int32", []int32{1, 2}),
+		entity.NewColumnInt64("int64", []int64{1, 2}),
+		entity.NewColumnFloat("float", []float32{1.5, 2.5}),
+		entity.NewColumnDouble("double", []float64{1.5, 2.5}),
+		entity.NewColumnVarChar("varchar", []string{"a", "b"}),
+		entity.NewColumnJSONBytes("json", [][]byte{[]byte(`{"a":1}`), []byte(`{}`)}),
+		entity.NewColumnJSONBytes("$meta", [][]byte{[]byte(`{"b":1}`), []byte(`{}`)}).WithIsDynamic(true),
+		entity.NewColumnFloatVector("float_vector", 2, [][]float32{{1, 2}, {3, 4}}),
+		entity.NewColumnBinaryVector("binary_vector", 16, [][]byte{{1, 2}, {3, 4}}),
+	}
+
+	rec, err := ToRecord(mem, columns)
+	require.NoError(t, err)
+	defer rec.Release()
+	assert.EqualValues(t, 2, rec.NumRows())
+	assert.EqualValues(t, len(columns), rec.NumCols())
+	assert.True(t, arrow.TypeEqual(arrow.FixedSizeListOf(2, arrow.PrimitiveTypes.Float32), rec.Column(10).DataType()))
+	assert.True(t, arrow.TypeEqual(&arrow.FixedSizeBinaryType{ByteWidth: 2}, rec.Column(11).DataType()))
+
+	result, err := FromRecord(rec)
+	require.NoError(t, err)
+	require.Len(t, result, len(columns))
+	for i, column := range columns {
+		assert.Equal(t, column.Name(), result[i].Name())
+		assert.Equal(t, column.Type(), result[i].Type())
+		assert.Equal(t, column.FieldData(), result[i].FieldData())
+	}
+	assert.True(t, result[9].(*entity.ColumnJSONBytes).IsDynamic())
+	assert.False(t, result[8].(*entity.ColumnJSONBytes).IsDynamic())
+}
+
+func TestZeroCopy(t *testing.T) {
+	mem := memory.NewGoAllocator()
+	column := entity.NewColumnInt64("id", []int64{1, 2, 3})
+
+	arr, err := ToArray(mem, column)
+	require.NoError(t, err)
+	defer arr.Release()
+	column.Data()[0] = 10
+	assert.EqualValues(t, 10, arr.(*array.Int64).Value(0))
+
+	back, err := FromArray("id", arr)
+	require.NoError(t, err)
+	arr.(*array.Int64).Int64Values()[1] = 20
+	assert.Equal(t, []int64{10, 20, 3}, back.(*entity.ColumnInt64).Data())
+
+	vectors := entity.NewColumnFloatVectorFlat("vector", 2, []float32{1, 2, 3, 4})
+	arr, err = ToArray(mem, vectors)
+	require.NoError(t, err)
+	defer arr.Release()
+	vectors.FlatData()[3] = 5
+	assert.EqualValues(t, 5, arr.(*array.FixedSizeList).ListValues().(*array.Float32).Value(3))
+	back, err = FromArray("vector", arr)
+	require.NoError(t, err)
+	assert.Equal(t, &vectors.FlatData()[0], &back.(*entity.ColumnFloatVector).FlatData()[0])
+}
+
+func TestDynamicColumn(t *testing.T) {
+	mem := memory.NewGoAllocator()
+	meta := entity.NewColumnJSONBytes("$meta", [][]byte{[]byte(`{"a":1}`), []byte(`{}`)}).WithIsDynamic(true)
+
+	arr, err := ToArray(mem, entity.NewColumnDynamic(meta, "a"))
+	require.NoError(t, err)
+	defer arr.Release()
+	assert.Equal(t, []byte("1"), arr.(*array.Binary).Value(0))
+	assert.True(t, arr.IsNull(1))
+
+	// null values are not supported in columns
+	_, err = FromArray("a", arr)
+	assert.Error(t, err)
+}
+
+func TestSliced(t *testing.T) {
+	mem := memory.NewGoAllocator()
+	arr, err := ToArray(mem, entity.NewColumnFloatVector("vector", 2, [][]float32{{1, 2}, {3, 4}, {5, 6}}))
+	require.NoError(t, err)
+	defer arr.Release()
+
+	sliced := array.NewSlice(arr, 1, 3)
+	defer sliced.Release()
+	column, err := FromArray("vector", sliced)
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{3, 4}, {5, 6}}, column.(*entity.ColumnFloatVector).Data())
+
+	arr, err = ToArray(mem, entity.NewColumnBinaryVector("vector", 16, [][]byte{{1, 2}, {3, 4}, {5, 6}}))
+	require.NoError(t, err)
+	defer arr.Release()
+	sliced = array.NewSlice(arr, 1, 2)
+	defer sliced.Release()
+	column, err = FromArray("vector", sliced)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{{3, 4}}, column.(*entity.ColumnBinaryVector).Data())
+}
+
+func TestInvalid(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	_, err := ToArray(mem, nil)
+	assert.Error(t, err)
+
+	_, err = ToRecord(mem, []entity.Column{
+		entity.NewColumnInt64("id", []int64{1}),
+		entity.NewColumnInt64("other", []int64{1, 2}),
+	})
+	assert.Error(t, err)
+
+	_, err = FromArray("nil", nil)
+	assert.Error(t, err)
+	b := array.NewUint64Builder(mem)
+	defer b.Release()
+	b.Append(1)
+	arr := b.NewArray()
+	defer arr.Release()
+	_, err = FromArray("uint64", arr)
+	assert.Error(t, err)
+
+	_, err = FromRecord(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package arrowconv
+
+import (
+	"context"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+// Insert inserts record rows into collection, record fields are mapped to collection fields by name.
+func Insert(ctx context.Context, c client.Client, collName string, partitionName string, rec arrow.Record) (entity.Column, error) {
+	columns, err := FromRecord(rec)
+	if err != nil {
+		return nil, err
+	}
+	return c.Insert(ctx, collName, partitionName, columns...)
+}
+
+// Query performs query and returns the result as arrow record, the caller shall release the record.
+func Query(ctx context.Context, mem memory.Allocator, c client.Client, collName string, partitionNames []string,
+	expr string, outputFields []string, opts ...client.SearchQueryOptionFunc) (arrow.Record, error) {
+	rs, err := c.Query(ctx, collName, partitionNames, expr, outputFields, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return ToRecord(mem, rs)
+}
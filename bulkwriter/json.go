@@ -0,0 +1,99 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package bulkwriter
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+// writeJSON writes buffered rows into `<seq>.json` as `{"rows": [...]}`.
+func (w *Writer) writeJSON() ([]string, error) {
+	path := filepath.Join(w.dir, fmt.Sprintf("%d.json", w.seq))
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	bw := bufio.NewWriter(f)
+	names := w.outputNames()
+	keys := make([][]byte, 0, len(names))
+	for _, name := range names {
+		key, _ := json.Marshal(name)
+		keys = append(keys, key)
+	}
+
+	bw.WriteString(`{"rows":[`)
+	for i := 0; i < w.bufferRows; i++ {
+		if i > 0 {
+			bw.WriteByte(',')
+		}
+		bw.WriteByte('{')
+		for j, name := range names {
+			value, err := jsonValue(w.buffer[name], i)
+			if err != nil {
+				return nil, err
+			}
+			if j > 0 {
+				bw.WriteByte(',')
+			}
+			bw.Write(keys[j])
+			bw.WriteByte(':')
+			bw.Write(value)
+		}
+		bw.WriteByte('}')
+	}
+	bw.WriteString("]}")
+	if err := bw.Flush(); err != nil {
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	return []string{path}, nil
+}
+
+// jsonValue returns the json encoded value of column at idx,
+// json values are embedded as is and binary vectors are encoded as arrays of bytes.
+func jsonValue(column entity.Column, idx int) ([]byte, error) {
+	switch c := column.(type) {
+	case *entity.ColumnJSONBytes:
+		return c.ValueByIdx(idx)
+	case *entity.ColumnBinaryVector:
+		v, err := c.ValueByIdx(idx)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]int, 0, len(v))
+		for _, b := range v {
+			values = append(values, int(b))
+		}
+		return json.Marshal(values)
+	case *entity.ColumnFloatVector:
+		v, err := c.ValueByIdx(idx)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(v)
+	}
+	v, err := column.Get(idx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
@@ -0,0 +1,154 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package bulkwriter
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+var npyMagic = []byte("\x93NUMPY")
+
+// writeNumpy writes each buffered column into `<seq>/<field>.npy`.
+func (w *Writer) writeNumpy() ([]string, error) {
+	dir := filepath.Join(w.dir, fmt.Sprintf("%d", w.seq))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	names := w.outputNames()
+	files := make([]string, 0, len(names))
+	for _, name := range names {
+		path := filepath.Join(dir, name+".npy")
+		if err := writeNpyFile(path, w.buffer[name]); err != nil {
+			return nil, err
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+func writeNpyFile(path string, column entity.Column) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	bw := bufio.NewWriter(f)
+	if err := writeNpy(bw, column); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// writeNpy writes column as npy format version 1.0 array.
+func writeNpy(w io.Writer, column entity.Column) error {
+	n := column.Len()
+	var descr, shape string
+	var data interface{}
+	switch c := column.(type) {
+	case *entity.ColumnBool:
+		descr, data = "|b1", c.Data()
+	case *entity.ColumnInt8:
+		descr, data = "|i1", c.Data()
+	case *entity.ColumnInt16:
+		descr, data = "<i2", c.Data()
+	case *entity.ColumnInt32:
+		descr, data = "<i4", c.Data()
+	case *entity.ColumnInt64:
+		descr, data = "<i8", c.Data()
+	case *entity.ColumnFloat:
+		descr, data = "<f4", c.Data()
+	case *entity.ColumnDouble:
+		descr, data = "<f8", c.Data()
+	case *entity.ColumnFloatVector:
+		descr, data = "<f4", c.FlatData()
+		shape = fmt.Sprintf("(%d, %d)", n, c.Dim())
+	case *entity.ColumnBinaryVector:
+		descr, data = "|u1", c.FlatData()
+		shape = fmt.Sprintf("(%d, %d)", n, c.Dim()/8)
+	case *entity.ColumnVarChar:
+		return writeNpyStrings(w, c.Data())
+	case *entity.ColumnString:
+		return writeNpyStrings(w, c.Data())
+	case *entity.ColumnJSONBytes:
+		values := make([]string, 0, n)
+		for _, v := range c.Data() {
+			values = append(values, string(v))
+		}
+		return writeNpyStrings(w, values)
+	default:
+		return fmt.Errorf("unsupported column %s of type %s", column.Name(), column.Type().Name())
+	}
+	if shape == "" {
+		shape = fmt.Sprintf("(%d,)", n)
+	}
+	if err := writeNpyHeader(w, descr, shape); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, data)
+}
+
+// writeNpyStrings writes strings as fixed length unicode array, each character is stored as UCS4.
+func writeNpyStrings(w io.Writer, values []string) error {
+	width := maxRuneCount(values)
+	if err := writeNpyHeader(w, fmt.Sprintf("<U%d", width), fmt.Sprintf("(%d,)", len(values))); err != nil {
+		return err
+	}
+	buf := make([]uint32, width)
+	for _, v := range values {
+		i := 0
+		for _, r := range v {
+			buf[i] = uint32(r)
+			i++
+		}
+		for ; i < width; i++ {
+			buf[i] = 0
+		}
+		if err := binary.Write(w, binary.LittleEndian, buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeNpyHeader writes magic, version and header dict padded to 64 bytes alignment.
+func writeNpyHeader(w io.Writer, descr, shape string) error {
+	header := fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': %s, }", descr, shape)
+	// magic(6) + version(2) + header length(2) + header + '\n'
+	total := len(npyMagic) + 4 + len(header) + 1
+	if pad := (64 - total%64) % 64; pad > 0 {
+		header += strings.Repeat(" ", pad)
+	}
+	header += "\n"
+
+	if _, err := w.Write(npyMagic); err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte{1, 0}); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint16(len(header))); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, header)
+	return err
+}
@@ -0,0 +1,452 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+// Package bulkwriter writes data into local files which could be imported by BulkInsert.
+//
+// Rows or columns are validated against collection schema, buffered and written into
+// row-based JSON files or column-based NumPy files, split by buffered size. Each file group
+// returned by Files shall be uploaded to the object storage of Milvus and passed to one BulkInsert call,
+// which client.ImportFiles does.
+package bulkwriter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+// DynamicFieldName is the column name of dynamic field in written files.
+const DynamicFieldName = "$meta"
+
+// FileType is the format of written files.
+type FileType int
+
+const (
+	// JSONRows writes one `{"rows": [...]}` json file per file group.
+	JSONRows FileType = iota
+	// Numpy writes one directory per file group, containing a `<field>.npy` file for each field.
+	Numpy
+)
+
+const defaultChunkSize = 128 * 1024 * 1024
+
+// Option is option for Writer.
+type Option func(opt *writerOpt)
+
+type writerOpt struct {
+	fileType  FileType
+	chunkSize int64
+}
+
+// WithFileType sets the format of written files, JSONRows by default.
+func WithFileType(fileType FileType) Option {
+	return func(opt *writerOpt) {
+		opt.fileType = fileType
+	}
+}
+
+// WithChunkSize sets the max estimated data size of each file group, 128MB by default.
+func WithChunkSize(size int64) Option {
+	return func(opt *writerOpt) {
+		opt.chunkSize = size
+	}
+}
+
+// Writer buffers data and writes them into local bulk insert files.
+// Writer is not safe for concurrent use.
+type Writer struct {
+	schema *entity.Schema
+	dir    string
+	opt    writerOpt
+
+	fields  []*entity.Field // fields to write, excluding auto id primary key and dynamic field
+	dims    map[string]int
+	dynamic bool
+
+	parts      map[string][]entity.Column // appended parts of each column, merged when flushing
+	buffer     map[string]entity.Column   // merged columns being written
+	bufferRows int
+	bufferSize int64
+	rowCount   int64
+	seq        int
+	files      [][]string
+}
+
+// NewWriter creates a Writer writing files into dir, which is created if not exists.
+func NewWriter(sch *entity.Schema, dir string, opts ...Option) (*Writer, error) {
+	if sch == nil {
+		return nil, errors.New("schema cannot be nil")
+	}
+	opt := writerOpt{fileType: JSONRows, chunkSize: defaultChunkSize}
+	for _, o := range opts {
+		o(&opt)
+	}
+	if opt.fileType != JSONRows && opt.fileType != Numpy {
+		return nil, fmt.Errorf("unsupported file type %d", opt.fileType)
+	}
+	if opt.chunkSize <= 0 {
+		return nil, fmt.Errorf("invalid chunk size %d", opt.chunkSize)
+	}
+
+	w := &Writer{
+		schema:  sch,
+		dir:     dir,
+		opt:     opt,
+		dims:    make(map[string]int),
+		dynamic: sch.EnableDynamicField,
+	}
+	for _, field := range sch.Fields {
+		if field.IsDynamic || (field.PrimaryKey && field.AutoID) {
+			continue
+		}
+		switch field.DataType {
+		case entity.FieldTypeFloatVector, entity.FieldTypeBinaryVector:
+			dim, err := strconv.Atoi(field.TypeParams[entity.TypeParamDim])
+			if err != nil || dim <= 0 {
+				return nil, fmt.Errorf("vector field %s with invalid dim %q", field.Name, field.TypeParams[entity.TypeParamDim])
+			}
+			w.dims[field.Name] = dim
+		case entity.FieldTypeVarChar, entity.FieldTypeString:
+			if maxLength, ok := field.TypeParams[entity.TypeParamMaxLength]; ok {
+				if _, err := strconv.Atoi(maxLength); err != nil {
+					return nil, fmt.Errorf("varchar field %s with invalid max length %q", field.Name, maxLength)
+				}
+			}
+		}
+		w.fields = append(w.fields, field)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	w.resetBuffer()
+	return w, nil
+}
+
+func (w *Writer) resetBuffer() {
+	w.parts = make(map[string][]entity.Column)
+	w.buffer = make(map[string]entity.Column)
+	w.bufferRows = 0
+	w.bufferSize = 0
+}
+
+// AppendRows appends rows, which are structs or map[string]interface{} as InsertRows accepts.
+// Keys not in schema are put into dynamic field when it is enabled.
+func (w *Writer) AppendRows(rows ...interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	sch := &entity.Schema{
+		CollectionName:     w.schema.CollectionName,
+		EnableDynamicField: w.dynamic,
+	}
+	for _, field := range w.schema.Fields {
+		if !field.IsDynamic {
+			sch.Fields = append(sch.Fields, field)
+		}
+	}
+	columns, err := entity.AnyToColumns(rows, sch)
+	if err != nil {
+		return err
+	}
+	for i, column := range columns {
+		if c, ok := column.(*entity.ColumnJSONBytes); ok && c.IsDynamic() && c.Name() == "" {
+			columns[i] = entity.NewColumnJSONBytes(DynamicFieldName, c.Data()).WithIsDynamic(true)
+		}
+	}
+	return w.AppendColumns(columns...)
+}
+
+// AppendColumns validates columns against schema and appends them.
+// Columns of all fields except auto id primary key shall be provided, dynamic field column is optional.
+// Buffered data is written into a new file group when adding more rows would exceed the chunk size.
+func (w *Writer) AppendColumns(columns ...entity.Column) error {
+	nameColumns, rows, err := w.validate(columns)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return nil
+	}
+	if w.dynamic {
+		if _, ok := nameColumns[DynamicFieldName]; !ok {
+			empty := make([][]byte, rows)
+			for i := range empty {
+				empty[i] = []byte("{}")
+			}
+			nameColumns[DynamicFieldName] = entity.NewColumnJSONBytes(DynamicFieldName, empty).WithIsDynamic(true)
+		}
+	}
+
+	var total int64
+	for _, column := range nameColumns {
+		total += columnSize(column)
+	}
+	rowSize := total / int64(rows)
+	if rowSize == 0 {
+		rowSize = 1
+	}
+
+	for offset := 0; offset < rows; {
+		n := int((w.opt.chunkSize - w.bufferSize) / rowSize)
+		if n < 1 {
+			if w.bufferRows > 0 {
+				if err := w.Flush(); err != nil {
+					return err
+				}
+				continue
+			}
+			// a single row larger than chunk size takes a file group
+			n = 1
+		}
+		if offset+n > rows {
+			n = rows - offset
+		}
+		for name, column := range nameColumns {
+			w.parts[name] = append(w.parts[name], column.Slice(offset, offset+n).Clone())
+		}
+		w.bufferRows += n
+		w.bufferSize += rowSize * int64(n)
+		offset += n
+	}
+	return nil
+}
+
+// validate checks columns against schema, returns columns by name and row count.
+func (w *Writer) validate(columns []entity.Column) (map[string]entity.Column, int, error) {
+	nameColumns := make(map[string]entity.Column, len(columns))
+	rows := -1
+	for _, column := range columns {
+		if column == nil {
+			return nil, 0, errors.New("column cannot be nil")
+		}
+		if _, ok := nameColumns[column.Name()]; ok {
+			return nil, 0, fmt.Errorf("duplicated column %s", column.Name())
+		}
+		if rows >= 0 && column.Len() != rows {
+			return nil, 0, errors.New("column size not match")
+		}
+		rows = column.Len()
+		nameColumns[column.Name()] = column
+	}
+
+	fieldNames := make(map[string]struct{}, len(w.fields))
+	for _, field := range w.fields {
+		fieldNames[field.Name] = struct{}{}
+		column, ok := nameColumns[field.Name]
+		if !ok {
+			return nil, 0, fmt.Errorf("column of field %s not provided", field.Name)
+		}
+		if err := w.validateColumn(field, column); err != nil {
+			return nil, 0, err
+		}
+	}
+	for name, column := range nameColumns {
+		if _, ok := fieldNames[name]; ok {
+			continue
+		}
+		if name != DynamicFieldName || !w.dynamic {
+			return nil, 0, fmt.Errorf("column %s not in schema", name)
+		}
+		c, ok := column.(*entity.ColumnJSONBytes)
+		if !ok {
+			return nil, 0, fmt.Errorf("dynamic field column shall be json, got %s", column.Type().Name())
+		}
+		for i, v := range c.Data() {
+			var m map[string]json.RawMessage
+			if err := json.Unmarshal(v, &m); err != nil {
+				return nil, 0, fmt.Errorf("dynamic field value of row %d is not json object: %w", i, err)
+			}
+		}
+	}
+	if rows < 0 {
+		rows = 0
+	}
+	return nameColumns, rows, nil
+}
+
+func (w *Writer) validateColumn(field *entity.Field, column entity.Column) error {
+	if column.Type() != field.DataType {
+		return fmt.Errorf("column %s type %s does not match field type %s", column.Name(), column.Type().Name(), field.DataType.Name())
+	}
+	switch c := column.(type) {
+	case *entity.ColumnFloatVector:
+		if c.Dim() != w.dims[field.Name] {
+			return fmt.Errorf("column %s dim %d does not match field dim %d", c.Name(), c.Dim(), w.dims[field.Name])
+		}
+	case *entity.ColumnBinaryVector:
+		if c.Dim() != w.dims[field.Name] {
+			return fmt.Errorf("column %s dim %d does not match field dim %d", c.Name(), c.Dim(), w.dims[field.Name])
+		}
+	case *entity.ColumnVarChar:
+		if err := checkMaxLength(field, c.Data()); err != nil {
+			return err
+		}
+	case *entity.ColumnString:
+		if err := checkMaxLength(field, c.Data()); err != nil {
+			return err
+		}
+	case *entity.ColumnJSONBytes:
+		for i, v := range c.Data() {
+			if !json.Valid(v) {
+				return fmt.Errorf("value of json field %s at row %d is not valid json", field.Name, i)
+			}
+		}
+	}
+	return nil
+}
+
+func checkMaxLength(field *entity.Field, values []string) error {
+	maxLength, ok := field.TypeParams[entity.TypeParamMaxLength]
+	if !ok {
+		return nil
+	}
+	limit, _ := strconv.Atoi(maxLength)
+	for i, v := range values {
+		if len(v) > limit {
+			return fmt.Errorf("value of field %s at row %d exceeds max length %d", field.Name, i, limit)
+		}
+	}
+	return nil
+}
+
+// Flush writes buffered data into a new file group.
+func (w *Writer) Flush() error {
+	if w.bufferRows == 0 {
+		return nil
+	}
+	for name, parts := range w.parts {
+		merged, err := mergeColumns(parts)
+		if err != nil {
+			return err
+		}
+		w.parts[name] = []entity.Column{merged}
+		w.buffer[name] = merged
+	}
+	w.seq++
+	var files []string
+	var err error
+	switch w.opt.fileType {
+	case JSONRows:
+		files, err = w.writeJSON()
+	case Numpy:
+		files, err = w.writeNumpy()
+	}
+	if err != nil {
+		return err
+	}
+	w.files = append(w.files, files)
+	w.rowCount += int64(w.bufferRows)
+	w.resetBuffer()
+	return nil
+}
+
+// mergeColumns merges parts of one column into the first part, which is owned by the writer,
+// so each value is copied once however many parts were appended.
+func mergeColumns(parts []entity.Column) (entity.Column, error) {
+	merged := parts[0]
+	for _, part := range parts[1:] {
+		for i := 0; i < part.Len(); i++ {
+			v, err := part.Get(i)
+			if err != nil {
+				return nil, err
+			}
+			if err := merged.AppendValue(v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return merged, nil
+}
+
+// Close flushes the remaining buffered data.
+func (w *Writer) Close() error {
+	return w.Flush()
+}
+
+// Files returns the written file groups, each of which shall be imported by one BulkInsert call.
+func (w *Writer) Files() [][]string {
+	result := make([][]string, 0, len(w.files))
+	for _, files := range w.files {
+		result = append(result, append([]string(nil), files...))
+	}
+	return result
+}
+
+// RowCount returns the count of rows written into files.
+func (w *Writer) RowCount() int64 {
+	return w.rowCount
+}
+
+// outputNames returns the names of columns to write, in schema order.
+func (w *Writer) outputNames() []string {
+	names := make([]string, 0, len(w.fields)+1)
+	for _, field := range w.fields {
+		names = append(names, field.Name)
+	}
+	if _, ok := w.buffer[DynamicFieldName]; ok {
+		names = append(names, DynamicFieldName)
+	}
+	return names
+}
+
+// columnSize estimates the data size of column in bytes.
+func columnSize(column entity.Column) int64 {
+	switch c := column.(type) {
+	case *entity.ColumnBool, *entity.ColumnInt8:
+		return int64(c.Len())
+	case *entity.ColumnInt16:
+		return int64(c.Len()) * 2
+	case *entity.ColumnInt32, *entity.ColumnFloat:
+		return int64(c.Len()) * 4
+	case *entity.ColumnInt64, *entity.ColumnDouble:
+		return int64(c.Len()) * 8
+	case *entity.ColumnVarChar:
+		return stringsSize(c.Data())
+	case *entity.ColumnString:
+		return stringsSize(c.Data())
+	case *entity.ColumnJSONBytes:
+		var size int64
+		for _, v := range c.Data() {
+			size += int64(len(v))
+		}
+		return size
+	case *entity.ColumnFloatVector:
+		return int64(len(c.FlatData())) * 4
+	case *entity.ColumnBinaryVector:
+		return int64(len(c.FlatData()))
+	}
+	return 0
+}
+
+func stringsSize(values []string) int64 {
+	var size int64
+	for _, v := range values {
+		size += int64(len(v))
+	}
+	return size
+}
+
+// maxRuneCount returns the max count of runes in values, at least 1.
+func maxRuneCount(values []string) int {
+	result := 1
+	for _, v := range values {
+		if n := utf8.RuneCountInString(v); n > result {
+			result = n
+		}
+	}
+	return result
+}
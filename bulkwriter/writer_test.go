@@ -0,0 +1,225 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package bulkwriter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+type WriterSuite struct {
+	suite.Suite
+	sch *entity.Schema
+}
+
+func (s *WriterSuite) SetupTest() {
+	s.sch = entity.NewSchema().WithName("test").WithDynamicFieldEnabled(true).
+		WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true)).
+		WithField(entity.NewField().WithName("title").WithDataType(entity.FieldTypeVarChar).WithMaxLength(8)).
+		WithField(entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithDim(2))
+}
+
+func (s *WriterSuite) columns(ids []int64, titles []string) []entity.Column {
+	vectors := make([][]float32, 0, len(ids))
+	for _, id := range ids {
+		vectors = append(vectors, []float32{float32(id), 0.5})
+	}
+	return []entity.Column{
+		entity.NewColumnInt64("id", ids),
+		entity.NewColumnVarChar("title", titles),
+		entity.NewColumnFloatVector("vector", 2, vectors),
+	}
+}
+
+func (s *WriterSuite) TestJSONRows() {
+	dir := s.T().TempDir()
+	w, err := NewWriter(s.sch, dir)
+	s.Require().NoError(err)
+
+	type row struct {
+		ID     int64     `milvus:"name:id"`
+		Title  string    `milvus:"name:title"`
+		Vector []float32 `milvus:"name:vector"`
+		Color  string    `milvus:"name:color"`
+	}
+	s.Require().NoError(w.AppendRows(row{ID: 1, Title: "a", Vector: []float32{1, 2}, Color: "red"}))
+	s.Require().NoError(w.AppendColumns(s.columns([]int64{2}, []string{"b"})...))
+	s.Require().NoError(w.Close())
+
+	files := w.Files()
+	s.Require().Len(files, 1)
+	s.Equal([][]string{{filepath.Join(dir, "1.json")}}, files)
+	s.EqualValues(2, w.RowCount())
+
+	bs, err := os.ReadFile(files[0][0])
+	s.Require().NoError(err)
+	var content struct {
+		Rows []map[string]interface{} `json:"rows"`
+	}
+	s.Require().NoError(json.Unmarshal(bs, &content))
+	s.Require().Len(content.Rows, 2)
+	s.EqualValues(1, content.Rows[0]["id"])
+	s.Equal("a", content.Rows[0]["title"])
+	s.Equal([]interface{}{1.0, 2.0}, content.Rows[0]["vector"])
+	s.Equal(map[string]interface{}{"color": "red"}, content.Rows[0][DynamicFieldName])
+	s.Equal(map[string]interface{}{}, content.Rows[1][DynamicFieldName])
+}
+
+func (s *WriterSuite) TestChunks() {
+	dir := s.T().TempDir()
+	// each row is estimated as 8 + 1 + 8 + 2 bytes
+	w, err := NewWriter(s.sch, dir, WithChunkSize(40))
+	s.Require().NoError(err)
+	s.Require().NoError(w.AppendColumns(s.columns([]int64{1, 2, 3, 4, 5}, []string{"a", "b", "c", "d", "e"})...))
+	s.Require().NoError(w.Close())
+
+	files := w.Files()
+	s.Require().Len(files, 3)
+	s.EqualValues(5, w.RowCount())
+	var total int
+	for _, group := range files {
+		bs, err := os.ReadFile(group[0])
+		s.Require().NoError(err)
+		var content struct {
+			Rows []json.RawMessage `json:"rows"`
+		}
+		s.Require().NoError(json.Unmarshal(bs, &content))
+		total += len(content.Rows)
+	}
+	s.Equal(5, total)
+}
+
+func (s *WriterSuite) TestAppendParts() {
+	dir := s.T().TempDir()
+	w, err := NewWriter(s.sch, dir)
+	s.Require().NoError(err)
+	for i := int64(0); i < 100; i++ {
+		ids := []int64{i}
+		s.Require().NoError(w.AppendColumns(s.columns(ids, []string{"a"})...))
+		// appended data is owned by writer
+		ids[0] = -1
+	}
+	s.Require().NoError(w.Close())
+
+	files := w.Files()
+	s.Require().Len(files, 1)
+	bs, err := os.ReadFile(files[0][0])
+	s.Require().NoError(err)
+	var content struct {
+		Rows []struct {
+			ID     int64     `json:"id"`
+			Vector []float32 `json:"vector"`
+		} `json:"rows"`
+	}
+	s.Require().NoError(json.Unmarshal(bs, &content))
+	s.Require().Len(content.Rows, 100)
+	for i, row := range content.Rows {
+		s.EqualValues(i, row.ID)
+		s.Equal([]float32{float32(i), 0.5}, row.Vector)
+	}
+}
+
+func (s *WriterSuite) TestNumpy() {
+	dir := s.T().TempDir()
+	w, err := NewWriter(s.sch, dir, WithFileType(Numpy))
+	s.Require().NoError(err)
+	columns := append(s.columns([]int64{1, 2}, []string{"a", "héllo"}),
+		entity.NewColumnJSONBytes(DynamicFieldName, [][]byte{[]byte(`{"k":1}`), []byte(`{}`)}).WithIsDynamic(true))
+	s.Require().NoError(w.AppendColumns(columns...))
+	s.Require().NoError(w.Close())
+
+	files := w.Files()
+	s.Require().Len(files, 1)
+	s.Equal([]string{
+		filepath.Join(dir, "1", "id.npy"),
+		filepath.Join(dir, "1", "title.npy"),
+		filepath.Join(dir, "1", "vector.npy"),
+		filepath.Join(dir, "1", "$meta.npy"),
+	}, files[0])
+
+	header, data := s.readNpy(files[0][0])
+	s.Contains(header, "'descr': '<i8'")
+	s.Contains(header, "'shape': (2,)")
+	ids := make([]int64, 2)
+	s.Require().NoError(binary.Read(bytes.NewReader(data), binary.LittleEndian, ids))
+	s.Equal([]int64{1, 2}, ids)
+
+	header, data = s.readNpy(files[0][1])
+	s.Contains(header, "'descr': '<U5'")
+	s.Len(data, 2*5*4)
+	s.EqualValues('é', binary.LittleEndian.Uint32(data[5*4+4:]))
+
+	header, data = s.readNpy(files[0][2])
+	s.Contains(header, "'descr': '<f4'")
+	s.Contains(header, "'shape': (2, 2)")
+	s.Len(data, 4*4)
+
+	header, _ = s.readNpy(files[0][3])
+	s.Contains(header, "'descr': '<U7'")
+}
+
+// readNpy returns the header dict and data of npy file.
+func (s *WriterSuite) readNpy(path string) (string, []byte) {
+	bs, err := os.ReadFile(path)
+	s.Require().NoError(err)
+	s.Require().True(bytes.HasPrefix(bs, npyMagic))
+	headerLen := int(binary.LittleEndian.Uint16(bs[8:10]))
+	s.Equal(0, (10+headerLen)%64)
+	return string(bs[10 : 10+headerLen]), bs[10+headerLen:]
+}
+
+func (s *WriterSuite) TestValidate() {
+	w, err := NewWriter(s.sch, s.T().TempDir())
+	s.Require().NoError(err)
+
+	columns := s.columns([]int64{1}, []string{"a"})
+	s.Error(w.AppendColumns(columns[:2]...), "missing field")
+	s.Error(w.AppendColumns(append(columns, entity.NewColumnInt64("other", []int64{1}))...), "unknown column")
+	s.Error(w.AppendColumns(s.columns([]int64{1}, []string{"too long title"})...), "exceeds max length")
+	s.Error(w.AppendColumns(columns[0], columns[1], entity.NewColumnFloatVector("vector", 3, [][]float32{{1, 2, 3}})), "dim mismatch")
+	s.Error(w.AppendColumns(columns[0], entity.NewColumnInt64("title", []int64{1}), columns[2]), "type mismatch")
+	s.Error(w.AppendColumns(columns[0], columns[1], entity.NewColumnFloatVector("vector", 2, [][]float32{{1, 2}, {3, 4}})), "size mismatch")
+	s.Error(w.AppendColumns(append(columns, entity.NewColumnJSONBytes(DynamicFieldName, [][]byte{[]byte(`[1]`)}))...), "dynamic not object")
+	s.Error(w.AppendColumns(columns[0], columns[0], columns[1], columns[2]), "duplicated")
+	s.NoError(w.Close())
+	s.Empty(w.Files())
+
+	// auto id primary key shall not be provided
+	autoID := entity.NewSchema().WithName("auto").
+		WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true).WithIsAutoID(true)).
+		WithField(entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithDim(2))
+	w, err = NewWriter(autoID, s.T().TempDir())
+	s.Require().NoError(err)
+	s.Error(w.AppendColumns(columns[0], columns[2]))
+	s.NoError(w.AppendColumns(columns[2]))
+
+	_, err = NewWriter(nil, s.T().TempDir())
+	s.Error(err)
+	_, err = NewWriter(s.sch, s.T().TempDir(), WithChunkSize(0))
+	s.Error(err)
+	_, err = NewWriter(s.sch, s.T().TempDir(), WithFileType(FileType(10)))
+	s.Error(err)
+	_, err = NewWriter(entity.NewSchema().WithField(entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector)), s.T().TempDir())
+	s.Error(err)
+}
+
+func TestWriter(t *testing.T) {
+	suite.Run(t, new(WriterSuite))
+}
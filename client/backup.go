@@ -0,0 +1,448 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/milvus-io/milvus-sdk-go/v2/expr"
+	"github.com/milvus-io/milvus-sdk-go/v2/internal/utils/tso"
+)
+
+const (
+	backupVersion         = 1
+	backupManifestFile    = "manifest.json"
+	backupDataDir         = "data"
+	defaultBackupFileRows = 100000
+	dynamicFieldName      = "$meta"
+	defaultPartitionName  = "_default"
+)
+
+// BackupManifest describes a collection backup, which is stored as manifest.json in the backup directory.
+type BackupManifest struct {
+	Version          int                     `json:"version"`
+	Collection       string                  `json:"collection"`
+	Schema           json.RawMessage         `json:"schema"` // collection schema proto in json
+	ShardNum         int32                   `json:"shard_num"`
+	ConsistencyLevel entity.ConsistencyLevel `json:"consistency_level"`
+	Partitions       []*BackupPartition      `json:"partitions"`
+	Indexes          []BackupIndex           `json:"indexes"`
+	Aliases          []string                `json:"aliases"`
+	Timestamp        uint64                  `json:"timestamp"`    // guarantee timestamp of data queries
+	SegmentIDs       []int64                 `json:"segment_ids"`  // flushed segments at backup
+	SegmentRows      int64                   `json:"segment_rows"` // row count of flushed segments, including deleted rows
+	Completed        bool                    `json:"completed"`
+}
+
+// BackupPartition is the data files of a partition, in primary key order.
+// Name is empty for collections with partition key, whose partitions are managed by Milvus.
+type BackupPartition struct {
+	Name      string       `json:"name"`
+	Files     []BackupFile `json:"files"`
+	Completed bool         `json:"completed"`
+}
+
+// BackupFile is a data file storing an InsertRequest proto with rows of the partition.
+type BackupFile struct {
+	Path   string          `json:"path"` // relative to backup directory
+	Rows   int             `json:"rows"`
+	SHA256 string          `json:"sha256"`
+	LastPK json.RawMessage `json:"last_pk"` // primary key of the last row
+}
+
+// BackupIndex is an index of the collection.
+type BackupIndex struct {
+	Field  string            `json:"field"`
+	Name   string            `json:"name"`
+	Params map[string]string `json:"params"`
+}
+
+// RowCount returns the count of rows in data files.
+func (m *BackupManifest) RowCount() int64 {
+	var count int64
+	for _, p := range m.Partitions {
+		for _, f := range p.Files {
+			count += int64(f.Rows)
+		}
+	}
+	return count
+}
+
+// CollectionSchema returns the schema of backup collection.
+func (m *BackupManifest) CollectionSchema() (*entity.Schema, error) {
+	pb := &schemapb.CollectionSchema{}
+	if err := jsonpb.Unmarshal(bytes.NewReader(m.Schema), pb); err != nil {
+		return nil, fmt.Errorf("invalid schema in backup manifest: %w", err)
+	}
+	return entity.NewSchema().ReadProto(pb), nil
+}
+
+// BackupOption is option for Backup.
+type BackupOption func(opt *backupOpt)
+
+type backupOpt struct {
+	fileRows int
+}
+
+// WithBackupFileRows sets the max row count of each data file, 100000 by default.
+func WithBackupFileRows(rows int) BackupOption {
+	return func(opt *backupOpt) {
+		opt.fileRows = rows
+	}
+}
+
+// Backup writes schema, partitions, indexes, aliases and all entities of collection into dir.
+// The collection is flushed and data is queried at a pinned timestamp after the flush.
+// The manifest is saved after each data file, calling Backup with the same dir resumes an interrupted backup.
+func (c *GrpcClient) Backup(ctx context.Context, collName string, dir string, opts ...BackupOption) (*BackupManifest, error) {
+	if c.Service == nil {
+		return nil, ErrClientNotReady
+	}
+	opt := backupOpt{fileRows: defaultBackupFileRows}
+	for _, o := range opts {
+		o(&opt)
+	}
+	if opt.fileRows <= 0 {
+		return nil, errors.New("backup file rows must be positive")
+	}
+
+	manifest, err := readBackupManifest(dir)
+	switch {
+	case err != nil:
+		return nil, err
+	case manifest == nil:
+		if manifest, err = c.backupMeta(ctx, collName); err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+		if err := writeJSONFile(filepath.Join(dir, backupManifestFile), manifest); err != nil {
+			return nil, err
+		}
+	case manifest.Collection != collName:
+		return nil, fmt.Errorf("directory %s contains backup of collection %s", dir, manifest.Collection)
+	case manifest.Completed:
+		return nil, fmt.Errorf("backup of collection %s in %s is already completed", collName, dir)
+	}
+
+	sch, err := manifest.CollectionSchema()
+	if err != nil {
+		return nil, err
+	}
+	for i, p := range manifest.Partitions {
+		if p.Completed {
+			continue
+		}
+		if err := c.backupPartition(ctx, dir, manifest, i, sch, opt); err != nil {
+			return nil, fmt.Errorf("failed to backup partition %s: %w", p.Name, err)
+		}
+	}
+	manifest.Completed = true
+	if err := writeJSONFile(filepath.Join(dir, backupManifestFile), manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// backupMeta collects collection meta and flushes the collection to get the consistency point.
+func (c *GrpcClient) backupMeta(ctx context.Context, collName string) (*BackupManifest, error) {
+	coll, err := c.DescribeCollection(ctx, collName)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := (&jsonpb.Marshaler{}).MarshalToString(coll.Schema.ProtoMessage())
+	if err != nil {
+		return nil, err
+	}
+	manifest := &BackupManifest{
+		Version:          backupVersion,
+		Collection:       collName,
+		Schema:           json.RawMessage(schema),
+		ShardNum:         coll.ShardNum,
+		ConsistencyLevel: coll.ConsistencyLevel,
+		Aliases:          coll.Aliases,
+	}
+
+	if manifest.Indexes, err = c.backupIndexes(ctx, collName); err != nil {
+		return nil, err
+	}
+
+	if hasPartitionKey(coll.Schema) {
+		manifest.Partitions = []*BackupPartition{{}}
+	} else {
+		partitions, err := c.ShowPartitions(ctx, collName)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range partitions {
+			manifest.Partitions = append(manifest.Partitions, &BackupPartition{Name: p.Name})
+		}
+	}
+
+	_, flushed, sealTime, err := c.FlushV2(ctx, collName, false)
+	if err != nil {
+		return nil, err
+	}
+	// data flushed is visible to queries with any timestamp allocated after flush
+	manifest.Timestamp, err = c.allocTimestamp(ctx)
+	if status.Code(err) == codes.Unimplemented && sealTime > 0 {
+		// servers without AllocTimestamp, the seal time of flush is taken from server clock as well
+		manifest.Timestamp, err = tso.ComposeTSByTime(time.Unix(sealTime, 0), 0), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	manifest.SegmentIDs = flushed
+	segments, err := c.GetPersistentSegmentInfo(ctx, collName)
+	if err != nil {
+		return nil, err
+	}
+	for _, segment := range segments {
+		manifest.SegmentRows += segment.NumRows
+	}
+	return manifest, nil
+}
+
+func (c *GrpcClient) backupIndexes(ctx context.Context, collName string) ([]BackupIndex, error) {
+	resp, err := c.Service.DescribeIndex(ctx, &milvuspb.DescribeIndexRequest{CollectionName: collName})
+	if err != nil {
+		return nil, err
+	}
+	if resp.GetStatus().GetErrorCode() == commonpb.ErrorCode_IndexNotExist {
+		return nil, nil
+	}
+	if err := handleRespStatus(resp.GetStatus()); err != nil {
+		return nil, err
+	}
+	indexes := make([]BackupIndex, 0, len(resp.GetIndexDescriptions()))
+	for _, desc := range resp.GetIndexDescriptions() {
+		indexes = append(indexes, BackupIndex{
+			Field:  desc.GetFieldName(),
+			Name:   desc.GetIndexName(),
+			Params: entity.KvPairsMap(desc.GetParams()),
+		})
+	}
+	return indexes, nil
+}
+
+// backupPartition queries rows of partition after the last backup file and writes them into data files.
+func (c *GrpcClient) backupPartition(ctx context.Context, dir string, manifest *BackupManifest, idx int, sch *entity.Schema, opt backupOpt) error {
+	part := manifest.Partitions[idx]
+	pk := getPKField(sch)
+	if pk == nil {
+		return errors.New("collection has no primary key")
+	}
+	outputFields := make([]string, 0, len(sch.Fields)+1)
+	for _, field := range sch.Fields {
+		if !field.IsDynamic {
+			outputFields = append(outputFields, field.Name)
+		}
+	}
+	if sch.EnableDynamicField {
+		outputFields = append(outputFields, dynamicFieldName)
+	}
+
+	iteratorOpts := []IteratorOption{WithIteratorGuaranteeTimestamp(manifest.Timestamp)}
+	if part.Name != "" {
+		iteratorOpts = append(iteratorOpts, WithIteratorPartitions(part.Name))
+	}
+	var filter string
+	if n := len(part.Files); n > 0 {
+		last, err := decodePK(pk, part.Files[n-1].LastPK)
+		if err != nil {
+			return err
+		}
+		if filter, err = expr.Field(pk.Name).Gt(last).Build(); err != nil {
+			return err
+		}
+	}
+	it, err := c.QueryIterator(ctx, manifest.Collection, filter, outputFields, iteratorOpts...)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	var buffer ResultSet
+	flush := func(rows int) error {
+		file, err := writeBackupFile(dir, idx, len(part.Files), manifest.Collection, part.Name, pk.Name, buffer.slice(0, rows))
+		if err != nil {
+			return err
+		}
+		part.Files = append(part.Files, file)
+		buffer = buffer.slice(rows, buffer.Len())
+		return writeJSONFile(filepath.Join(dir, backupManifestFile), manifest)
+	}
+	for {
+		rs, err := it.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if buffer, err = buffer.concat(rs); err != nil {
+			return err
+		}
+		for buffer.Len() >= opt.fileRows {
+			if err := flush(opt.fileRows); err != nil {
+				return err
+			}
+		}
+	}
+	if buffer.Len() > 0 {
+		if err := flush(buffer.Len()); err != nil {
+			return err
+		}
+	}
+	part.Completed = true
+	return writeJSONFile(filepath.Join(dir, backupManifestFile), manifest)
+}
+
+// writeBackupFile writes rows as InsertRequest proto into data/<partition idx>/<file idx>.pb.
+func writeBackupFile(dir string, partIdx, fileIdx int, collName, partName, pkName string, rs ResultSet) (BackupFile, error) {
+	n := rs.Len()
+	fieldsData := make([]*schemapb.FieldData, 0, len(rs))
+	for _, column := range rs {
+		fieldsData = append(fieldsData, column.FieldData())
+	}
+	bs, err := proto.Marshal(&milvuspb.InsertRequest{
+		CollectionName: collName,
+		PartitionName:  partName,
+		FieldsData:     fieldsData,
+		NumRows:        uint32(n),
+	})
+	if err != nil {
+		return BackupFile{}, err
+	}
+	last, err := rs.GetColumn(pkName).Get(n - 1)
+	if err != nil {
+		return BackupFile{}, err
+	}
+	lastPK, err := json.Marshal(last)
+	if err != nil {
+		return BackupFile{}, err
+	}
+
+	rel := filepath.Join(backupDataDir, fmt.Sprint(partIdx), fmt.Sprintf("%d.pb", fileIdx))
+	if err := writeFileAtomic(filepath.Join(dir, rel), bs); err != nil {
+		return BackupFile{}, err
+	}
+	sum := sha256.Sum256(bs)
+	return BackupFile{
+		Path:   filepath.ToSlash(rel),
+		Rows:   n,
+		SHA256: hex.EncodeToString(sum[:]),
+		LastPK: lastPK,
+	}, nil
+}
+
+// readBackupFile verifies the checksum of data file and returns its columns.
+func readBackupFile(dir string, file BackupFile) ([]entity.Column, error) {
+	bs, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file.Path)))
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(bs)
+	if hex.EncodeToString(sum[:]) != file.SHA256 {
+		return nil, fmt.Errorf("checksum of backup file %s mismatch", file.Path)
+	}
+	req := &milvuspb.InsertRequest{}
+	if err := proto.Unmarshal(bs, req); err != nil {
+		return nil, fmt.Errorf("invalid backup file %s: %w", file.Path, err)
+	}
+	columns := make([]entity.Column, 0, len(req.GetFieldsData()))
+	for _, fd := range req.GetFieldsData() {
+		column, err := entity.FieldDataColumn(fd, 0, -1)
+		if err != nil {
+			return nil, err
+		}
+		if column.Len() != file.Rows {
+			return nil, fmt.Errorf("backup file %s has %d rows of %s, expected %d", file.Path, column.Len(), column.Name(), file.Rows)
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// decodePK decodes json encoded primary key of pk field.
+func decodePK(pk *entity.Field, raw json.RawMessage) (interface{}, error) {
+	switch pk.DataType {
+	case entity.FieldTypeInt64:
+		var v int64
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case entity.FieldTypeVarChar:
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	}
+	return nil, fmt.Errorf("unsupported primary key type %s", pk.DataType.Name())
+}
+
+func readBackupManifest(dir string) (*BackupManifest, error) {
+	bs, err := os.ReadFile(filepath.Join(dir, backupManifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	manifest := &BackupManifest{}
+	if err := json.Unmarshal(bs, manifest); err != nil {
+		return nil, fmt.Errorf("invalid backup manifest: %w", err)
+	}
+	if manifest.Version != backupVersion {
+		return nil, fmt.Errorf("unsupported backup version %d", manifest.Version)
+	}
+	return manifest, nil
+}
+
+func writeJSONFile(path string, v interface{}) error {
+	bs, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, bs)
+}
+
+// writeFileAtomic writes data into a temporary file and renames it to path,
+// so that an interrupted write never leaves a partial file.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,468 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/milvus-io/milvus-sdk-go/v2/expr"
+	"github.com/milvus-io/milvus-sdk-go/v2/objectstore"
+)
+
+type BackupSuite struct {
+	MockSuiteBase
+	sch *entity.Schema
+	// partition name to primary keys of source collection
+	data map[string][]int64
+}
+
+func (s *BackupSuite) SetupSuite() {
+	s.MockSuiteBase.SetupSuite()
+
+	s.sch = entity.NewSchema().WithName(testCollectionName).WithDynamicFieldEnabled(true).
+		WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true)).
+		WithField(entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithDim(2)).
+		WithField(entity.NewField().WithName(dynamicFieldName).WithDataType(entity.FieldTypeJSON).WithIsDynamic(true))
+	s.data = map[string][]int64{
+		defaultPartitionName: {1, 2, 3, 4, 5},
+		"p1":                 {6, 7, 8, 9, 10, 11, 12},
+	}
+}
+
+// setupSource mocks the source collection, query fails once at the failAt-th call if positive.
+func (s *BackupSuite) setupSource(failAt int) {
+	s.mock.EXPECT().DescribeCollection(mock.Anything, mock.AnythingOfType("*milvuspb.DescribeCollectionRequest")).
+		Return(&milvuspb.DescribeCollectionResponse{
+			Status:           getSuccessStatus(),
+			Schema:           s.sch.ProtoMessage(),
+			ShardsNum:        2,
+			Aliases:          []string{"alias1"},
+			ConsistencyLevel: commonpb.ConsistencyLevel_Bounded,
+		}, nil)
+	s.setupHasCollection(testCollectionName)
+	s.mock.EXPECT().DescribeIndex(mock.Anything, mock.AnythingOfType("*milvuspb.DescribeIndexRequest")).
+		Return(&milvuspb.DescribeIndexResponse{
+			Status: getSuccessStatus(),
+			IndexDescriptions: []*milvuspb.IndexDescription{{
+				IndexName: "vec_idx",
+				FieldName: "vector",
+				Params: entity.MapKvPairs(map[string]string{
+					"index_type": "HNSW", "metric_type": "L2", "params": `{"M":8,"efConstruction":64}`,
+				}),
+			}},
+		}, nil)
+	s.mock.EXPECT().ShowPartitions(mock.Anything, mock.AnythingOfType("*milvuspb.ShowPartitionsRequest")).
+		Return(&milvuspb.ShowPartitionsResponse{
+			Status:         getSuccessStatus(),
+			PartitionNames: []string{defaultPartitionName, "p1"},
+			PartitionIDs:   []int64{1, 2},
+		}, nil)
+	s.mock.EXPECT().Flush(mock.Anything, mock.AnythingOfType("*milvuspb.FlushRequest")).
+		Return(&milvuspb.FlushResponse{
+			Status:          getSuccessStatus(),
+			FlushCollSegIDs: map[string]*schemapb.LongArray{testCollectionName: {Data: []int64{100}}},
+		}, nil)
+	s.setupAllocTimestamp(1000)
+	s.mock.EXPECT().GetPersistentSegmentInfo(mock.Anything, mock.AnythingOfType("*milvuspb.GetPersistentSegmentInfoRequest")).
+		Return(&milvuspb.GetPersistentSegmentInfoResponse{
+			Status: getSuccessStatus(),
+			Infos:  []*milvuspb.PersistentSegmentInfo{{SegmentID: 100, NumRows: 12}},
+		}, nil)
+
+	calls := 0
+	s.mock.EXPECT().Query(mock.Anything, mock.AnythingOfType("*milvuspb.QueryRequest")).
+		Call.Return(func(_ context.Context, req *milvuspb.QueryRequest) *milvuspb.QueryResults {
+		calls++
+		if calls == failAt {
+			return &milvuspb.QueryResults{Status: &commonpb.Status{ErrorCode: commonpb.ErrorCode_UnexpectedError}}
+		}
+		s.Require().Len(req.GetPartitionNames(), 1)
+		s.EqualValues(1000, req.GetGuaranteeTimestamp())
+		s.Equal([]string{"id", "vector", dynamicFieldName}, req.GetOutputFields())
+		var limit int
+		for _, kv := range req.GetQueryParams() {
+			if kv.GetKey() == limitKey {
+				limit, _ = strconv.Atoi(kv.GetValue())
+			}
+		}
+		var node expr.Node
+		if req.GetExpr() != "" {
+			var err error
+			node, err = expr.Parse(req.GetExpr())
+			s.Require().NoError(err)
+		}
+		var ids []int64
+		var vectors []float32
+		var metas [][]byte
+		for _, id := range s.data[req.GetPartitionNames()[0]] {
+			if len(ids) >= limit {
+				break
+			}
+			if node != nil {
+				ok, err := expr.Evaluate(node, map[string]interface{}{"id": id})
+				s.Require().NoError(err)
+				if !ok {
+					continue
+				}
+			}
+			ids = append(ids, id)
+			vectors = append(vectors, float32(id), 0)
+			metas = append(metas, []byte(fmt.Sprintf(`{"n":%d}`, id)))
+		}
+		return &milvuspb.QueryResults{
+			Status: getSuccessStatus(),
+			FieldsData: []*schemapb.FieldData{
+				s.getInt64FieldData("id", ids),
+				s.getFloatVectorFieldData("vector", 2, vectors),
+				s.getJSONBytesFieldData(dynamicFieldName, metas, true),
+			},
+		}
+	}, nil).Maybe()
+}
+
+// restoreTarget records requests to create and write the restored collection.
+type restoreTarget struct {
+	created    *entity.Schema
+	partitions []string
+	upserted   map[string][]int64 // partition name to primary keys
+	dynamic    int                // count of upserted dynamic field values
+	indexes    []*milvuspb.CreateIndexRequest
+	aliases    []string
+	failAlias  string         // alias failed to create once
+	schema     *entity.Schema // described schema of the target, suite schema if nil
+}
+
+// setupTarget mocks the target collection, which exists after created.
+func (s *BackupSuite) setupTarget(target *restoreTarget) {
+	target.upserted = make(map[string][]int64)
+	s.mock.EXPECT().HasCollection(mock.Anything, mock.AnythingOfType("*milvuspb.HasCollectionRequest")).
+		Call.Return(func(_ context.Context, req *milvuspb.HasCollectionRequest) *milvuspb.BoolResponse {
+		return &milvuspb.BoolResponse{Status: getSuccessStatus(), Value: target.created != nil && target.created.CollectionName == req.GetCollectionName()}
+	}, nil)
+	s.mock.EXPECT().CreateCollection(mock.Anything, mock.AnythingOfType("*milvuspb.CreateCollectionRequest")).
+		Call.Return(func(_ context.Context, req *milvuspb.CreateCollectionRequest) *commonpb.Status {
+		pb := &schemapb.CollectionSchema{}
+		s.Require().NoError(proto.Unmarshal(req.GetSchema(), pb))
+		target.created = entity.NewSchema().ReadProto(pb)
+		s.EqualValues(2, req.GetShardsNum())
+		s.Equal(commonpb.ConsistencyLevel_Bounded, req.GetConsistencyLevel())
+		return getSuccessStatus()
+	}, nil)
+	sch := target.schema
+	if sch == nil {
+		sch = s.sch
+	}
+	s.mock.EXPECT().DescribeCollection(mock.Anything, mock.AnythingOfType("*milvuspb.DescribeCollectionRequest")).
+		Return(&milvuspb.DescribeCollectionResponse{Status: getSuccessStatus(), Schema: sch.ProtoMessage()}, nil)
+	s.mock.EXPECT().HasPartition(mock.Anything, mock.AnythingOfType("*milvuspb.HasPartitionRequest")).
+		Call.Return(func(_ context.Context, req *milvuspb.HasPartitionRequest) *milvuspb.BoolResponse {
+		has := req.GetPartitionName() == defaultPartitionName
+		for _, p := range target.partitions {
+			has = has || p == req.GetPartitionName()
+		}
+		return &milvuspb.BoolResponse{Status: getSuccessStatus(), Value: has}
+	}, nil)
+	s.mock.EXPECT().CreatePartition(mock.Anything, mock.AnythingOfType("*milvuspb.CreatePartitionRequest")).
+		Call.Return(func(_ context.Context, req *milvuspb.CreatePartitionRequest) *commonpb.Status {
+		target.partitions = append(target.partitions, req.GetPartitionName())
+		return getSuccessStatus()
+	}, nil)
+	s.mock.EXPECT().Upsert(mock.Anything, mock.AnythingOfType("*milvuspb.UpsertRequest")).
+		Call.Return(func(_ context.Context, req *milvuspb.UpsertRequest) *milvuspb.MutationResult {
+		var ids []int64
+		for _, fd := range req.GetFieldsData() {
+			switch {
+			case fd.GetFieldName() == "id":
+				ids = fd.GetScalars().GetLongData().GetData()
+			case fd.GetIsDynamic():
+				target.dynamic += len(fd.GetScalars().GetJsonData().GetData())
+			}
+		}
+		target.upserted[req.GetPartitionName()] = append(target.upserted[req.GetPartitionName()], ids...)
+		return &milvuspb.MutationResult{
+			Status: getSuccessStatus(),
+			IDs:    &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids}}},
+		}
+	}, nil)
+	s.mock.EXPECT().Flush(mock.Anything, mock.AnythingOfType("*milvuspb.FlushRequest")).
+		Return(&milvuspb.FlushResponse{Status: getSuccessStatus()}, nil)
+	s.mock.EXPECT().CreateIndex(mock.Anything, mock.AnythingOfType("*milvuspb.CreateIndexRequest")).
+		Call.Return(func(_ context.Context, req *milvuspb.CreateIndexRequest) *commonpb.Status {
+		target.indexes = append(target.indexes, req)
+		return getSuccessStatus()
+	}, nil)
+	s.mock.EXPECT().CreateAlias(mock.Anything, mock.AnythingOfType("*milvuspb.CreateAliasRequest")).
+		Call.Return(func(_ context.Context, req *milvuspb.CreateAliasRequest) *commonpb.Status {
+		if req.GetAlias() == target.failAlias {
+			target.failAlias = ""
+			return &commonpb.Status{ErrorCode: commonpb.ErrorCode_UnexpectedError}
+		}
+		target.aliases = append(target.aliases, req.GetAlias())
+		return getSuccessStatus()
+	}, nil)
+}
+
+// updateManifest updates the backup manifest in dir, the returned function reverts it.
+func (s *BackupSuite) updateManifest(dir string, update func(manifest *BackupManifest)) func() {
+	path := filepath.Join(dir, backupManifestFile)
+	origin, err := os.ReadFile(path)
+	s.Require().NoError(err)
+	manifest, err := readBackupManifest(dir)
+	s.Require().NoError(err)
+	update(manifest)
+	s.Require().NoError(writeJSONFile(path, manifest))
+	return func() {
+		s.Require().NoError(os.WriteFile(path, origin, 0644))
+	}
+}
+
+func (s *BackupSuite) TestBackupRestore() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dir := s.T().TempDir()
+
+	s.Run("backup_resume", func() {
+		defer s.resetMock()
+		// the query of partition p1 fails
+		s.setupSource(2)
+
+		_, err := s.client.Backup(ctx, testCollectionName, dir, WithBackupFileRows(2))
+		s.Error(err)
+		manifest, err := readBackupManifest(dir)
+		s.Require().NoError(err)
+		s.Require().NotNil(manifest)
+		s.False(manifest.Completed)
+		s.True(manifest.Partitions[0].Completed)
+		s.Len(manifest.Partitions[0].Files, 3)
+		s.Empty(manifest.Partitions[1].Files)
+
+		manifest, err = s.client.Backup(ctx, testCollectionName, dir, WithBackupFileRows(2))
+		s.Require().NoError(err)
+		s.True(manifest.Completed)
+
+		// interrupted in the middle of partition, rows after the last file are queried
+		manifest.Completed = false
+		manifest.Partitions[1].Completed = false
+		manifest.Partitions[1].Files = manifest.Partitions[1].Files[:2]
+		s.Require().NoError(writeJSONFile(filepath.Join(dir, backupManifestFile), manifest))
+		manifest, err = s.client.Backup(ctx, testCollectionName, dir, WithBackupFileRows(2))
+		s.Require().NoError(err)
+		s.True(manifest.Completed)
+		s.EqualValues(12, manifest.RowCount())
+		s.EqualValues(12, manifest.SegmentRows)
+		s.Equal([]int64{100}, manifest.SegmentIDs)
+		s.EqualValues(1000, manifest.Timestamp)
+		s.Equal([]string{"alias1"}, manifest.Aliases)
+		s.Require().Len(manifest.Indexes, 1)
+		s.Equal("vector", manifest.Indexes[0].Field)
+		s.Equal("HNSW", manifest.Indexes[0].Params["index_type"])
+		s.Require().Len(manifest.Partitions, 2)
+		s.Len(manifest.Partitions[0].Files, 3)
+		s.Len(manifest.Partitions[1].Files, 4)
+		s.Equal(`12`, string(manifest.Partitions[1].Files[3].LastPK))
+
+		sch, err := manifest.CollectionSchema()
+		s.Require().NoError(err)
+		s.Equal(testCollectionName, sch.CollectionName)
+		s.True(sch.EnableDynamicField)
+
+		_, err = s.client.Backup(ctx, testCollectionName, dir)
+		s.Error(err, "backup completed")
+		_, err = s.client.Backup(ctx, "other", dir)
+		s.Error(err, "backup of other collection")
+	})
+
+	s.Run("restore", func() {
+		defer s.resetMock()
+		target := &restoreTarget{}
+		s.setupTarget(target)
+
+		err := s.client.Restore(ctx, dir, WithRestoreCollectionName("restored"))
+		s.Require().NoError(err)
+
+		s.Require().NotNil(target.created)
+		s.Equal("restored", target.created.CollectionName)
+		s.True(target.created.EnableDynamicField)
+		s.Len(target.created.Fields, 2)
+		s.Equal([]string{"p1"}, target.partitions)
+		s.Equal(s.data, target.upserted)
+		s.Equal(12, target.dynamic)
+		s.Require().Len(target.indexes, 1)
+		s.Equal("vec_idx", target.indexes[0].GetIndexName())
+		s.Equal("vector", target.indexes[0].GetFieldName())
+		s.Equal(`{"M":8,"efConstruction":64}`, entity.KvPairsMap(target.indexes[0].GetExtraParams())["params"])
+		s.Equal([]string{"alias1"}, target.aliases)
+
+		_, err = os.Stat(filepath.Join(dir, "restore_restored.json"))
+		s.True(errors.Is(err, os.ErrNotExist))
+
+		err = s.client.Restore(ctx, dir, WithRestoreCollectionName("restored"))
+		s.Error(err, "collection exists")
+	})
+
+	s.Run("restore_alias_resume", func() {
+		defer s.resetMock()
+		defer s.updateManifest(dir, func(manifest *BackupManifest) {
+			manifest.Aliases = []string{"alias1", "alias2"}
+		})()
+		target := &restoreTarget{failAlias: "alias2"}
+		s.setupTarget(target)
+
+		err := s.client.Restore(ctx, dir, WithRestoreCollectionName("aliased"))
+		s.Error(err)
+		state, err := readRestoreState(filepath.Join(dir, "restore_aliased.json"))
+		s.Require().NoError(err)
+		s.Equal(map[string]bool{"alias1": true}, state.Aliases)
+
+		// created aliases and restored data are skipped
+		err = s.client.Restore(ctx, dir, WithRestoreCollectionName("aliased"))
+		s.Require().NoError(err)
+		s.Equal([]string{"alias1", "alias2"}, target.aliases)
+		s.Equal(s.data, target.upserted)
+		s.Len(target.indexes, 1)
+	})
+
+	s.Run("restore_import_resume", func() {
+		defer s.resetMock()
+		target := &restoreTarget{}
+		s.setupTarget(target)
+		var imported [][]string
+		s.mock.EXPECT().Import(mock.Anything, mock.AnythingOfType("*milvuspb.ImportRequest")).
+			Call.Return(func(_ context.Context, req *milvuspb.ImportRequest) *milvuspb.ImportResponse {
+			imported = append(imported, req.GetFiles())
+			return &milvuspb.ImportResponse{Status: getSuccessStatus(), Tasks: []int64{int64(len(imported))}}
+		}, nil)
+		// task 2 fails, which imports the second file of default partition
+		s.mock.EXPECT().GetImportState(mock.Anything, mock.AnythingOfType("*milvuspb.GetImportStateRequest")).
+			Call.Return(func(_ context.Context, req *milvuspb.GetImportStateRequest) *milvuspb.GetImportStateResponse {
+			if req.GetTask() == 2 {
+				return importState(req.GetTask(), commonpb.ImportState_ImportFailed, "0")
+			}
+			return importState(req.GetTask(), commonpb.ImportState_ImportCompleted, "100")
+		}, nil)
+		store := objectstore.NewMemory()
+
+		err := s.client.Restore(ctx, dir, WithRestoreCollectionName("imported"), WithRestoreObjectStore(store), WithRestoreSkipAliases())
+		s.Error(err)
+		s.Len(imported, 3)
+		state, err := readRestoreState(filepath.Join(dir, "restore_imported.json"))
+		s.Require().NoError(err)
+		s.Empty(state.Done)
+		s.Len(state.Tasks, 2)
+
+		// only the file group of the failed task is imported again
+		err = s.client.Restore(ctx, dir, WithRestoreCollectionName("imported"), WithRestoreObjectStore(store), WithRestoreSkipAliases())
+		s.Require().NoError(err)
+		s.Len(imported, 8)
+		s.Empty(target.upserted)
+		_, err = os.Stat(filepath.Join(dir, "restore_imported.json"))
+		s.True(errors.Is(err, os.ErrNotExist))
+	})
+
+	s.Run("restore_auto_id_interrupted", func() {
+		defer s.updateManifest(dir, func(manifest *BackupManifest) {
+			sch, err := manifest.CollectionSchema()
+			s.Require().NoError(err)
+			sch.Fields[0].AutoID = true
+			schema, err := (&jsonpb.Marshaler{}).MarshalToString(sch.ProtoMessage())
+			s.Require().NoError(err)
+			manifest.Schema = json.RawMessage(schema)
+		})()
+		statePath := filepath.Join(dir, "restore_auto_id.json")
+		defer os.Remove(statePath)
+		s.Require().NoError(writeJSONFile(statePath, &restoreState{Created: true, Loading: "data/0_0.bin"}))
+
+		err := s.client.Restore(ctx, dir, WithRestoreCollectionName("auto_id"))
+		s.Error(err)
+		s.Contains(err.Error(), "interrupted")
+	})
+
+	s.Run("restore_partition_key", func() {
+		defer s.resetMock()
+		// backup of partition key collection has one partition without name
+		defer s.updateManifest(dir, func(manifest *BackupManifest) {
+			sch, err := manifest.CollectionSchema()
+			s.Require().NoError(err)
+			sch.Fields[0].IsPartitionKey = true
+			schema, err := (&jsonpb.Marshaler{}).MarshalToString(sch.ProtoMessage())
+			s.Require().NoError(err)
+			manifest.Schema = json.RawMessage(schema)
+			partition := &BackupPartition{Completed: true}
+			for _, p := range manifest.Partitions {
+				partition.Files = append(partition.Files, p.Files...)
+			}
+			manifest.Partitions = []*BackupPartition{partition}
+		})()
+		target := &restoreTarget{schema: entity.NewSchema().WithName(testCollectionName).WithDynamicFieldEnabled(true).
+			WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true).WithIsPartitionKey(true)).
+			WithField(entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithDim(2)),
+		}
+		s.setupTarget(target)
+
+		err := s.client.Restore(ctx, dir, WithRestoreCollectionName("partition_key"), WithRestoreSkipAliases())
+		s.Require().NoError(err)
+		s.True(target.created.Fields[0].IsPartitionKey)
+		s.Empty(target.partitions)
+		// partition names are not specified in upsert requests
+		s.Require().Len(target.upserted, 1)
+		s.ElementsMatch(append(append([]int64{}, s.data[defaultPartitionName]...), s.data["p1"]...), target.upserted[""])
+	})
+
+	s.Run("restore_corrupted", func() {
+		defer s.resetMock()
+		target := &restoreTarget{}
+		s.setupTarget(target)
+
+		manifest, err := readBackupManifest(dir)
+		s.Require().NoError(err)
+		path := filepath.Join(dir, filepath.FromSlash(manifest.Partitions[1].Files[1].Path))
+		bs, err := os.ReadFile(path)
+		s.Require().NoError(err)
+		bs[len(bs)-1]++
+		s.Require().NoError(os.WriteFile(path, bs, 0644))
+
+		err = s.client.Restore(ctx, dir, WithRestoreCollectionName("corrupted"), WithRestoreSkipAliases())
+		s.Error(err)
+		s.Contains(err.Error(), "checksum")
+		s.Len(target.upserted["p1"], 2)
+		// progress is kept for resuming
+		state, err := readRestoreState(filepath.Join(dir, "restore_corrupted.json"))
+		s.Require().NoError(err)
+		s.True(state.Created)
+		s.Len(state.Done, 4)
+	})
+
+	s.Run("restore_missing", func() {
+		err := s.client.Restore(ctx, s.T().TempDir())
+		s.Error(err)
+	})
+}
+
+func TestBackup(t *testing.T) {
+	suite.Run(t, new(BackupSuite))
+}
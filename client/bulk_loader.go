@@ -0,0 +1,353 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+const (
+	defaultBulkBatchBytes  = 16 * 1024 * 1024
+	defaultBulkParallelism = 4
+	defaultBulkMaxRetry    = 3
+	defaultBulkBackoff     = 100 * time.Millisecond
+)
+
+// BulkBatch is the row range [Offset, Offset+Rows) of a batch sent by BulkLoader.
+type BulkBatch struct {
+	Offset int
+	Rows   int
+}
+
+// BulkProgress is the progress reported by BulkLoader after each successful batch.
+type BulkProgress struct {
+	TotalRows   int
+	LoadedRows  int
+	Batches     int
+	DoneBatches int
+}
+
+// BulkBatchIDs is a loaded batch with the id column returned for it.
+type BulkBatchIDs struct {
+	BulkBatch
+	IDs entity.Column
+}
+
+// BulkLoadError is returned by BulkLoader when some batches still fail after retries.
+// Batches not listed in Failed are loaded, callers could resend the failed ranges only.
+type BulkLoadError struct {
+	Failed []BulkBatch
+	Loaded []BulkBatchIDs // batches loaded successfully with their ids, in row order
+	Err    error          // the last error of failed batches
+}
+
+func (e *BulkLoadError) Error() string {
+	ranges := make([]string, 0, len(e.Failed))
+	for _, b := range e.Failed {
+		ranges = append(ranges, fmt.Sprintf("[%d, %d)", b.Offset, b.Offset+b.Rows))
+	}
+	return fmt.Sprintf("bulk load failed for rows %s: %v", strings.Join(ranges, ", "), e.Err)
+}
+
+func (e *BulkLoadError) Unwrap() error {
+	return e.Err
+}
+
+// BulkLoaderOption is option for BulkLoader.
+type BulkLoaderOption func(opt *bulkLoaderOpt)
+
+type bulkLoaderOpt struct {
+	partition   string
+	batchRows   int
+	batchBytes  int64
+	parallelism int
+	maxRetry    int
+	backoff     time.Duration
+	progress    func(BulkProgress)
+}
+
+// WithBulkPartition sets the partition to load data into.
+func WithBulkPartition(partitionName string) BulkLoaderOption {
+	return func(opt *bulkLoaderOpt) {
+		opt.partition = partitionName
+	}
+}
+
+// WithBulkBatchRows limits the row count of each batch.
+func WithBulkBatchRows(rows int) BulkLoaderOption {
+	return func(opt *bulkLoaderOpt) {
+		opt.batchRows = rows
+	}
+}
+
+// WithBulkBatchBytes limits the estimated size of each batch, 16MB by default.
+func WithBulkBatchBytes(size int64) BulkLoaderOption {
+	return func(opt *bulkLoaderOpt) {
+		opt.batchBytes = size
+	}
+}
+
+// WithBulkParallelism sets the max count of batches sent concurrently, 4 by default.
+func WithBulkParallelism(n int) BulkLoaderOption {
+	return func(opt *bulkLoaderOpt) {
+		opt.parallelism = n
+	}
+}
+
+// WithBulkRetry sets the max retry times of a failed batch and the backoff between attempts,
+// which is doubled after each attempt. Batches are retried 3 times by default.
+// Only transient gRPC errors (Unavailable, ResourceExhausted and Aborted) are retried.
+func WithBulkRetry(maxRetry int, backoff time.Duration) BulkLoaderOption {
+	return func(opt *bulkLoaderOpt) {
+		opt.maxRetry = maxRetry
+		opt.backoff = backoff
+	}
+}
+
+// WithBulkProgress sets the callback called after each successful batch, calls are serialized.
+func WithBulkProgress(fn func(BulkProgress)) BulkLoaderOption {
+	return func(opt *bulkLoaderOpt) {
+		opt.progress = fn
+	}
+}
+
+// BulkLoader splits large column data into batches and inserts or upserts them with bounded parallelism,
+// so that each request stays within gRPC message size and server request limits.
+type BulkLoader struct {
+	client   Client
+	collName string
+	opt      bulkLoaderOpt
+
+	mut    sync.Mutex
+	schema *entity.Schema
+}
+
+// NewBulkLoader creates a BulkLoader for the collection.
+func NewBulkLoader(c Client, collName string, opts ...BulkLoaderOption) *BulkLoader {
+	opt := bulkLoaderOpt{
+		batchBytes:  defaultBulkBatchBytes,
+		parallelism: defaultBulkParallelism,
+		maxRetry:    defaultBulkMaxRetry,
+		backoff:     defaultBulkBackoff,
+	}
+	for _, o := range opts {
+		o(&opt)
+	}
+	if opt.parallelism < 1 {
+		opt.parallelism = 1
+	}
+	if opt.maxRetry < 0 {
+		opt.maxRetry = 0
+	}
+	return &BulkLoader{client: c, collName: collName, opt: opt}
+}
+
+// Insert inserts columns in batches and returns the merged id column in row order.
+func (l *BulkLoader) Insert(ctx context.Context, columns ...entity.Column) (entity.Column, error) {
+	return l.load(ctx, l.client.Insert, columns)
+}
+
+// Upsert upserts columns in batches and returns the merged id column in row order.
+func (l *BulkLoader) Upsert(ctx context.Context, columns ...entity.Column) (entity.Column, error) {
+	return l.load(ctx, l.client.Upsert, columns)
+}
+
+type loadFunc func(ctx context.Context, collName string, partitionName string, columns ...entity.Column) (entity.Column, error)
+
+func (l *BulkLoader) load(ctx context.Context, fn loadFunc, columns []entity.Column) (entity.Column, error) {
+	if l.client == nil {
+		return nil, ErrClientNotReady
+	}
+	if len(columns) == 0 {
+		return nil, errors.New("no column provided")
+	}
+	rowCount := columns[0].Len()
+	for _, column := range columns {
+		if column.Len() != rowCount {
+			return nil, errors.New("column size not match")
+		}
+	}
+	batchRows, err := l.batchRows(ctx, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	var batches []BulkBatch
+	for offset := 0; offset < rowCount; offset += batchRows {
+		rows := batchRows
+		if offset+rows > rowCount {
+			rows = rowCount - offset
+		}
+		batches = append(batches, BulkBatch{Offset: offset, Rows: rows})
+	}
+
+	ids := make([]entity.Column, len(batches))
+	errs := make([]error, len(batches))
+	progress := BulkProgress{TotalRows: rowCount, Batches: len(batches)}
+	var progressMut sync.Mutex
+
+	sem := make(chan struct{}, l.opt.parallelism)
+	var wg sync.WaitGroup
+	for i, batch := range batches {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(i int, batch BulkBatch) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			batchColumns := make([]entity.Column, 0, len(columns))
+			for _, column := range columns {
+				batchColumns = append(batchColumns, column.Slice(batch.Offset, batch.Offset+batch.Rows))
+			}
+			ids[i], errs[i] = l.send(ctx, fn, batchColumns)
+			if errs[i] != nil || l.opt.progress == nil {
+				return
+			}
+			progressMut.Lock()
+			defer progressMut.Unlock()
+			progress.LoadedRows += batch.Rows
+			progress.DoneBatches++
+			l.opt.progress(progress)
+		}(i, batch)
+	}
+	wg.Wait()
+
+	loadErr := &BulkLoadError{}
+	for i, err := range errs {
+		if err != nil {
+			loadErr.Failed = append(loadErr.Failed, batches[i])
+			loadErr.Err = err
+			continue
+		}
+		loadErr.Loaded = append(loadErr.Loaded, BulkBatchIDs{BulkBatch: batches[i], IDs: ids[i]})
+	}
+	if len(loadErr.Failed) > 0 {
+		return nil, loadErr
+	}
+
+	var result entity.Column
+	for _, id := range ids {
+		if id == nil {
+			continue
+		}
+		if result == nil {
+			result = id.Clone()
+			continue
+		}
+		if result, err = result.Concat(id); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// send sends one batch, retrying with exponential backoff on transient failure.
+func (l *BulkLoader) send(ctx context.Context, fn loadFunc, columns []entity.Column) (entity.Column, error) {
+	backoff := l.opt.backoff
+	for attempt := 0; ; attempt++ {
+		ids, err := fn(ctx, l.collName, l.opt.partition, columns...)
+		if err == nil || attempt >= l.opt.maxRetry || !isTransientError(err) {
+			return ids, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// isTransientError returns whether err is a gRPC error which may succeed when retried.
+func isTransientError(err error) bool {
+	var se interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &se) {
+		return false
+	}
+	switch se.GRPCStatus().Code() {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
+
+// batchRows returns the row count of each batch, limited by both row count and estimated size.
+func (l *BulkLoader) batchRows(ctx context.Context, columns []entity.Column) (int, error) {
+	rows := columns[0].Len()
+	if l.opt.batchRows > 0 && l.opt.batchRows < rows {
+		rows = l.opt.batchRows
+	}
+	if l.opt.batchBytes <= 0 {
+		return maxInt(rows, 1), nil
+	}
+	sch, err := l.getSchema(ctx)
+	if err != nil {
+		return 0, err
+	}
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		names = append(names, column.Name())
+	}
+	rowSize := estRowSize(sch, names)
+	// json values are not covered by schema estimation, use the average size of data instead
+	for _, column := range columns {
+		if c, ok := column.(*entity.ColumnJSONBytes); ok && c.Len() > 0 {
+			var total int
+			for _, v := range c.Data() {
+				total += len(v)
+			}
+			rowSize += int64(total / c.Len())
+		}
+	}
+	if rowSize > 0 && l.opt.batchBytes/rowSize < int64(rows) {
+		rows = int(l.opt.batchBytes / rowSize)
+	}
+	return maxInt(rows, 1), nil
+}
+
+func (l *BulkLoader) getSchema(ctx context.Context) (*entity.Schema, error) {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+	if l.schema != nil {
+		return l.schema, nil
+	}
+	coll, err := l.client.DescribeCollection(ctx, l.collName)
+	if err != nil {
+		return nil, err
+	}
+	l.schema = coll.Schema
+	return l.schema, nil
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,209 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+type BulkLoaderSuite struct {
+	MockSuiteBase
+	sch *entity.Schema
+}
+
+func (s *BulkLoaderSuite) SetupSuite() {
+	s.MockSuiteBase.SetupSuite()
+
+	s.sch = entity.NewSchema().WithName(testCollectionName).
+		WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true)).
+		WithField(entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithDim(2))
+}
+
+func (s *BulkLoaderSuite) columns(n int) []entity.Column {
+	ids := make([]int64, 0, n)
+	vectors := make([][]float32, 0, n)
+	for i := 0; i < n; i++ {
+		ids = append(ids, int64(i))
+		vectors = append(vectors, []float32{float32(i), float32(i)})
+	}
+	return []entity.Column{
+		entity.NewColumnInt64("id", ids),
+		entity.NewColumnFloatVector("vector", 2, vectors),
+	}
+}
+
+// setupInsert mocks Insert returning ids of request, the first attempt of batches starting with failID fails
+// with transient error.
+func (s *BulkLoaderSuite) setupInsert(failID int64, rows *[]int) {
+	var mut sync.Mutex
+	failed := false
+	s.mock.EXPECT().Insert(mock.Anything, mock.AnythingOfType("*milvuspb.InsertRequest")).
+		Call.Return(func(_ context.Context, req *milvuspb.InsertRequest) (*milvuspb.MutationResult, error) {
+		mut.Lock()
+		defer mut.Unlock()
+		var ids []int64
+		for _, fd := range req.GetFieldsData() {
+			if fd.GetFieldName() == "id" {
+				ids = fd.GetScalars().GetLongData().GetData()
+			}
+		}
+		if ids[0] == failID && !failed {
+			failed = true
+			return nil, status.Error(codes.Aborted, "mock")
+		}
+		*rows = append(*rows, int(req.GetNumRows()))
+		return &milvuspb.MutationResult{
+			Status: getSuccessStatus(),
+			IDs:    &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids}}},
+		}, nil
+	})
+}
+
+func (s *BulkLoaderSuite) TestInsert() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.Run("batch_by_bytes", func() {
+		defer s.resetMock()
+		s.setupHasCollection(testCollectionName)
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		var rows []int
+		s.setupInsert(4, &rows)
+
+		var progress []BulkProgress
+		// estimated row size is 16 bytes, 4 rows per batch
+		loader := NewBulkLoader(s.client, testCollectionName,
+			WithBulkBatchBytes(64),
+			WithBulkRetry(2, time.Millisecond),
+			WithBulkProgress(func(p BulkProgress) { progress = append(progress, p) }))
+		ids, err := loader.Insert(ctx, s.columns(10)...)
+		s.Require().NoError(err)
+		s.Equal([]int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, ids.(*entity.ColumnInt64).Data())
+		s.ElementsMatch([]int{4, 4, 2}, rows)
+		s.Require().Len(progress, 3)
+		s.Equal(BulkProgress{TotalRows: 10, LoadedRows: 10, Batches: 3, DoneBatches: 3}, progress[2])
+	})
+
+	s.Run("batch_by_rows", func() {
+		defer s.resetMock()
+		s.setupHasCollection(testCollectionName)
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		var rows []int
+		s.setupInsert(-1, &rows)
+
+		loader := NewBulkLoader(s.client, testCollectionName, WithBulkBatchRows(3), WithBulkParallelism(1))
+		ids, err := loader.Insert(ctx, s.columns(7)...)
+		s.Require().NoError(err)
+		s.Equal(7, ids.Len())
+		s.Equal([]int{3, 3, 1}, rows)
+	})
+
+	s.Run("retry_exhausted", func() {
+		defer s.resetMock()
+		s.setupHasCollection(testCollectionName)
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		var rows []int
+		s.setupInsert(3, &rows)
+
+		loader := NewBulkLoader(s.client, testCollectionName, WithBulkBatchRows(3), WithBulkRetry(0, 0))
+		_, err := loader.Insert(ctx, s.columns(7)...)
+		s.Error(err)
+		var loadErr *BulkLoadError
+		s.Require().True(errors.As(err, &loadErr))
+		s.Equal([]BulkBatch{{Offset: 3, Rows: 3}}, loadErr.Failed)
+		s.ElementsMatch([]int{3, 1}, rows)
+		// ids of loaded batches are kept by batch
+		s.Require().Len(loadErr.Loaded, 2)
+		s.Equal(BulkBatch{Offset: 0, Rows: 3}, loadErr.Loaded[0].BulkBatch)
+		s.Equal([]int64{0, 1, 2}, loadErr.Loaded[0].IDs.(*entity.ColumnInt64).Data())
+		s.Equal(BulkBatch{Offset: 6, Rows: 1}, loadErr.Loaded[1].BulkBatch)
+		s.Equal([]int64{6}, loadErr.Loaded[1].IDs.(*entity.ColumnInt64).Data())
+	})
+
+	s.Run("non_transient_not_retried", func() {
+		defer s.resetMock()
+		s.setupHasCollection(testCollectionName)
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.mock.EXPECT().Insert(mock.Anything, mock.AnythingOfType("*milvuspb.InsertRequest")).
+			Return(&milvuspb.MutationResult{Status: &commonpb.Status{ErrorCode: commonpb.ErrorCode_UnexpectedError}}, nil)
+
+		loader := NewBulkLoader(s.client, testCollectionName, WithBulkRetry(3, time.Millisecond))
+		_, err := loader.Insert(ctx, s.columns(2)...)
+		var loadErr *BulkLoadError
+		s.Require().True(errors.As(err, &loadErr))
+		s.Empty(loadErr.Loaded)
+		s.mock.AssertNumberOfCalls(s.T(), "Insert", 1)
+	})
+
+	s.Run("invalid_columns", func() {
+		defer s.resetMock()
+		loader := NewBulkLoader(s.client, testCollectionName)
+		_, err := loader.Insert(ctx)
+		s.Error(err)
+		columns := s.columns(2)
+		_, err = loader.Insert(ctx, columns[0], columns[1].Slice(0, 1))
+		s.Error(err)
+	})
+
+	s.Run("describe_fail", func() {
+		defer s.resetMock()
+		s.setupDescribeCollectionError(commonpb.ErrorCode_UnexpectedError, errors.New("mock"))
+		loader := NewBulkLoader(s.client, testCollectionName)
+		_, err := loader.Insert(ctx, s.columns(2)...)
+		s.Error(err)
+	})
+}
+
+func (s *BulkLoaderSuite) TestUpsert() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer s.resetMock()
+
+	s.setupHasCollection(testCollectionName)
+	s.setupDescribeCollection(testCollectionName, s.sch)
+	s.mock.EXPECT().Upsert(mock.Anything, mock.AnythingOfType("*milvuspb.UpsertRequest")).
+		Call.Return(func(_ context.Context, req *milvuspb.UpsertRequest) *milvuspb.MutationResult {
+		var ids []int64
+		for _, fd := range req.GetFieldsData() {
+			if fd.GetFieldName() == "id" {
+				ids = fd.GetScalars().GetLongData().GetData()
+			}
+		}
+		return &milvuspb.MutationResult{
+			Status: getSuccessStatus(),
+			IDs:    &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids}}},
+		}
+	}, nil)
+
+	loader := NewBulkLoader(s.client, testCollectionName, WithBulkBatchRows(2))
+	ids, err := loader.Upsert(ctx, s.columns(5)...)
+	s.Require().NoError(err)
+	s.Equal([]int64{0, 1, 2, 3, 4}, ids.(*entity.ColumnInt64).Data())
+}
+
+func TestBulkLoader(t *testing.T) {
+	suite.Run(t, new(BulkLoaderSuite))
+}
@@ -137,12 +137,32 @@ type Client interface {
 	// Search search with bool expression
 	Search(ctx context.Context, collName string, partitions []string,
 		expr string, outputFields []string, vectors []entity.Vector, vectorField string, metricType entity.MetricType, topK int, sp entity.SearchParam, opts ...SearchQueryOptionFunc) ([]SearchResult, error)
+	// HybridSearch searches several vector fields and fuses the results with ranker.
+	HybridSearch(ctx context.Context, collName string, partitions []string, expr string, outputFields []string,
+		searches []AnnSearch, ranker Ranker, topK int, opts ...SearchQueryOptionFunc) ([]SearchResult, error)
+	// MultiSearch searches several collections in parallel and merges the results into global topK hits.
+	MultiSearch(ctx context.Context, sources []SearchSource, outputFields []string, vectors []entity.Vector,
+		vectorField string, metricType entity.MetricType, topK int, sp entity.SearchParam, opts ...SearchQueryOptionFunc) ([]MultiSearchResult, error)
+	// SearchWith searches with the request built by NewSearchRequest.
+	SearchWith(ctx context.Context, req *SearchRequest) ([]SearchResult, error)
 	// QueryByPks query record by specified primary key(s).
 	QueryByPks(ctx context.Context, collectionName string, partitionNames []string, ids entity.Column, outputFields []string, opts ...SearchQueryOptionFunc) (ResultSet, error)
 	// Query performs query records with boolean expression.
 	Query(ctx context.Context, collectionName string, partitionNames []string, expr string, outputFields []string, opts ...SearchQueryOptionFunc) (ResultSet, error)
+	// QueryWith queries with the request built by NewQueryRequest.
+	QueryWith(ctx context.Context, req *QueryRequest) (ResultSet, error)
 	// Get grabs the inserted entities using the primary key from the Collection.
 	Get(ctx context.Context, collectionName string, ids entity.Column, opts ...GetOption) (ResultSet, error)
+	// QueryIterator returns an iterator paging through query results by primary key.
+	QueryIterator(ctx context.Context, collName string, expr string, outputFields []string, opts ...IteratorOption) (*QueryIterator, error)
+	// SearchIterator returns an iterator paging through search results of a vector by distance bands.
+	SearchIterator(ctx context.Context, collName string, expr string, outputFields []string,
+		vector entity.Vector, vectorField string, metricType entity.MetricType, sp entity.SearchParam, opts ...IteratorOption) (*SearchIterator, error)
+
+	// Backup writes collection meta and entities into local directory.
+	Backup(ctx context.Context, collName string, dir string, opts ...BackupOption) (*BackupManifest, error)
+	// Restore replays collection backup in local directory.
+	Restore(ctx context.Context, dir string, opts ...RestoreOption) error
 
 	// CalcDistance calculate the distance between vectors specified by ids or provided
 	CalcDistance(ctx context.Context, collName string, partitions []string,
@@ -157,6 +177,10 @@ type Client interface {
 	InsertByRows(ctx context.Context, collName string, paritionName string, rows []entity.Row) (entity.Column, error)
 	// InsertRows insert with row base data.
 	InsertRows(ctx context.Context, collName string, partitionName string, rows []interface{}) (entity.Column, error)
+	// UpsertRows upsert with row base data.
+	UpsertRows(ctx context.Context, collName string, partitionName string, rows []interface{}) (entity.Column, error)
+	// DeleteRows deletes entities of rows by primary key.
+	DeleteRows(ctx context.Context, collName string, partitionName string, rows []interface{}) error
 
 	// ManualCompaction triggers a compaction on provided collection
 	ManualCompaction(ctx context.Context, collName string, toleranceDuration time.Duration) (int64, error)
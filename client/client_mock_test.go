@@ -156,6 +156,14 @@ func (s *MockSuiteBase) setupDescribeCollectionError(errorCode commonpb.ErrorCod
 		}, err)
 }
 
+func (s *MockSuiteBase) setupAllocTimestamp(ts uint64) {
+	s.mock.EXPECT().AllocTimestamp(mock.Anything, mock.AnythingOfType("*milvuspb.AllocTimestampRequest")).
+		Return(&milvuspb.AllocTimestampResponse{
+			Status:    &commonpb.Status{ErrorCode: commonpb.ErrorCode_Success},
+			Timestamp: ts,
+		}, nil)
+}
+
 func (s *MockSuiteBase) getInt64FieldData(name string, data []int64) *schemapb.FieldData {
 	return &schemapb.FieldData{
 		Type:      schemapb.DataType_Int64,
@@ -266,6 +266,7 @@ func (c *GrpcClient) DescribeCollection(ctx context.Context, collName string) (*
 		VirtualChannels:  resp.GetVirtualChannelNames(),
 		ConsistencyLevel: entity.ConsistencyLevel(resp.ConsistencyLevel),
 		ShardNum:         resp.GetShardsNum(),
+		Aliases:          resp.GetAliases(),
 	}
 	collection.Name = collection.Schema.CollectionName
 	colInfo := collInfo{
@@ -24,7 +24,11 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus-sdk-go/v2/distance"
 	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/milvus-io/milvus-sdk-go/v2/expr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -56,6 +60,30 @@ func (c *GrpcClient) Search(ctx context.Context, collName string, partitions []s
 	if err != nil {
 		return nil, err
 	}
+	return c.searchWithOption(ctx, schema, collName, partitions, expr, outputFields, vectors, vectorField, metricType, topK, sp, option)
+}
+
+// searchWithOption searches with the option made from SearchQueryOptionFuncs,
+// dispatching to group by and rerank searches by option.
+func (c *GrpcClient) searchWithOption(ctx context.Context, schema *entity.Schema, collName string, partitions []string,
+	expr string, outputFields []string, vectors []entity.Vector, vectorField string, metricType entity.MetricType, topK int, sp entity.SearchParam, option *SearchQueryOption) ([]SearchResult, error) {
+	if option.GroupByField != "" && len(option.Rerankers) > 0 {
+		return nil, errors.New("group by cannot be used with rerankers")
+	}
+	return searchChunks(ctx, vectors, option, func(vectors []entity.Vector) ([]SearchResult, error) {
+		switch {
+		case option.GroupByField != "":
+			return c.searchGroupBy(ctx, schema, collName, partitions, expr, outputFields, vectors, vectorField, metricType, topK, sp, option)
+		case len(option.Rerankers) > 0:
+			return c.searchRerank(ctx, schema, collName, partitions, expr, outputFields, vectors, vectorField, metricType, topK, sp, option)
+		default:
+			return c.search(ctx, schema, collName, partitions, expr, outputFields, vectors, vectorField, metricType, topK, sp, option)
+		}
+	})
+}
+
+func (c *GrpcClient) search(ctx context.Context, schema *entity.Schema, collName string, partitions []string,
+	expr string, outputFields []string, vectors []entity.Vector, vectorField string, metricType entity.MetricType, topK int, sp entity.SearchParam, option *SearchQueryOption) ([]SearchResult, error) {
 	// 2. Request milvus Service
 	req, err := prepareSearchRequest(collName, partitions, expr, outputFields, vectors, vectorField, metricType, topK, sp, option)
 	if err != nil {
@@ -131,23 +159,27 @@ func (c *GrpcClient) parseSearchResult(_ *entity.Schema, outputFields []string,
 	return columns, nil
 }
 
+// PKs2Expr returns the `pk in [...]` expression of ids, the column name is used as field name unless empty.
+// Empty string is returned when ids is empty or not int64/varchar column, use QueryByPks or DeleteByPks to get the error.
 func PKs2Expr(backName string, ids entity.Column) string {
-	var expr string
+	result, _ := pks2Expr(backName, ids)
+	return result
+}
+
+func pks2Expr(backName string, ids entity.Column) (string, error) {
 	var pkName = ids.Name()
 	if ids.Name() == "" {
 		pkName = backName
 	}
+	// string keys are quoted and escaped by expression builder
 	switch ids.Type() {
 	case entity.FieldTypeInt64:
-		expr = fmt.Sprintf("%s in %s", pkName, strings.Join(strings.Fields(fmt.Sprint(ids.FieldData().GetScalars().GetLongData().GetData())), ","))
+		return expr.Field(pkName).In(ids.FieldData().GetScalars().GetLongData().GetData()).Build()
 	case entity.FieldTypeVarChar:
-		data := ids.FieldData().GetScalars().GetData().(*schemapb.ScalarField_StringData).StringData.Data
-		for i := range data {
-			data[i] = fmt.Sprintf("\"%s\"", data[i])
-		}
-		expr = fmt.Sprintf("%s in %s", pkName, strings.Join(strings.Fields(fmt.Sprint(data)), ","))
+		return expr.Field(pkName).In(ids.FieldData().GetScalars().GetStringData().GetData()).Build()
+	default:
+		return "", fmt.Errorf("ids column type %s is not supported as primary key", ids.Type().Name())
 	}
-	return expr
 }
 
 // Get grabs the inserted entities using the primary key from the Collection.
@@ -187,7 +219,10 @@ func (c *GrpcClient) QueryByPks(ctx context.Context, collectionName string, part
 		return nil, errors.New("only int64 and varchar column can be primary key for now")
 	}
 
-	expr := PKs2Expr("", ids)
+	expr, err := pks2Expr("", ids)
+	if err != nil {
+		return nil, err
+	}
 
 	return c.Query(ctx, collectionName, partitionNames, expr, outputFields, opts...)
 }
@@ -214,7 +249,12 @@ func (c *GrpcClient) Query(ctx context.Context, collectionName string, partition
 	if err != nil {
 		return nil, err
 	}
+	return c.queryWithOption(ctx, sch, collectionName, partitionNames, expr, outputFields, option)
+}
 
+// queryWithOption queries with the option made from SearchQueryOptionFuncs.
+func (c *GrpcClient) queryWithOption(ctx context.Context, sch *entity.Schema, collectionName string, partitionNames []string,
+	expr string, outputFields []string, option *SearchQueryOption) (ResultSet, error) {
 	req := &milvuspb.QueryRequest{
 		DbName:             "", // reserved field
 		CollectionName:     collectionName,
@@ -273,6 +313,17 @@ func getPKField(schema *entity.Schema) *entity.Field {
 	return nil
 }
 
+// hasPartitionKey returns whether schema has a partition key field,
+// requests of such collection shall not specify partition names.
+func hasPartitionKey(schema *entity.Schema) bool {
+	for _, f := range schema.Fields {
+		if f.IsPartitionKey {
+			return true
+		}
+	}
+	return false
+}
+
 func getVectorField(schema *entity.Schema) *entity.Field {
 	for _, f := range schema.Fields {
 		if f.DataType == entity.FieldTypeFloatVector || f.DataType == entity.FieldTypeBinaryVector {
@@ -297,7 +348,7 @@ func prepareSearchRequest(collName string, partitions []string,
 		"topk":           fmt.Sprintf("%d", topK),
 		"params":         string(bs),
 		"metric_type":    string(metricType),
-		"round_decimal":  "-1",
+		"round_decimal":  strconv.Itoa(opt.RoundDecimal),
 		ignoreGrowingKey: strconv.FormatBool(opt.IgnoreGrowing),
 		offsetKey:        fmt.Sprintf("%d", opt.Offset),
 	})
@@ -415,8 +466,15 @@ func (c *GrpcClient) CalcDistance(ctx context.Context, collName string, partitio
 
 	resp, err := c.Service.CalcDistance(ctx, req)
 	if err != nil {
+		// CalcDistance rpc is removed from newer milvus, compute in client instead
+		if status.Code(err) == codes.Unimplemented {
+			return c.calcDistanceLocal(ctx, collName, partitions, metricType, opLeft, opRight)
+		}
 		return nil, err
 	}
+	if calcDistanceUnsupported(resp.GetStatus()) {
+		return c.calcDistanceLocal(ctx, collName, partitions, metricType, opLeft, opRight)
+	}
 	if err := handleRespStatus(resp.GetStatus()); err != nil {
 		return nil, err
 	}
@@ -431,6 +489,126 @@ func (c *GrpcClient) CalcDistance(ctx context.Context, collName string, partitio
 	return nil, errors.New("distance field not supported")
 }
 
+// statusCodeServiceUnavailable is the status code milvus proxy reports for deprecated CalcDistance.
+const statusCodeServiceUnavailable = 2
+
+// calcDistanceDeprecatedReason is the reason milvus proxy reports for deprecated CalcDistance,
+// as "service unavailable[reason=CalcDistance deprecated]".
+const calcDistanceDeprecatedReason = "CalcDistance deprecated"
+
+// calcDistanceUnsupported returns whether the server reports CalcDistance as deprecated,
+// other failures with the same status code are not.
+func calcDistanceUnsupported(status *commonpb.Status) bool {
+	return status.GetErrorCode() != commonpb.ErrorCode_Success &&
+		status.GetCode() == statusCodeServiceUnavailable &&
+		strings.Contains(status.GetReason(), calcDistanceDeprecatedReason)
+}
+
+// calcDistanceLocal computes distance with `distance` package, vectors of id columns are fetched with query.
+func (c *GrpcClient) calcDistanceLocal(ctx context.Context, collName string, partitions []string,
+	metricType entity.MetricType, opLeft, opRight entity.Column) (entity.Column, error) {
+	left, err := c.resolveVectors(ctx, collName, partitions, opLeft)
+	if err != nil {
+		return nil, err
+	}
+	right, err := c.resolveVectors(ctx, collName, partitions, opRight)
+	if err != nil {
+		return nil, err
+	}
+	return distance.Columns(metricType, left, right)
+}
+
+// resolveVectors returns the vector column for CalcDistance operator.
+// For id column, the column name is the vector field name, vectors are queried by ids and kept in ids order.
+func (c *GrpcClient) resolveVectors(ctx context.Context, collName string, partitions []string, column entity.Column) (entity.Column, error) {
+	switch column.(type) {
+	case *entity.ColumnFloatVector, *entity.ColumnBinaryVector:
+		return column, nil
+	case *entity.ColumnInt64, *entity.ColumnString, *entity.ColumnVarChar:
+	default:
+		return nil, fmt.Errorf("column type %s is not supported for calc distance", column.Type().Name())
+	}
+
+	// collection schema is only needed to query vectors by ids
+	coll, err := c.DescribeCollection(ctx, collName)
+	if err != nil {
+		return nil, err
+	}
+	pkName := coll.Schema.PKFieldName()
+	var ids entity.Column
+	switch column := column.(type) {
+	case *entity.ColumnInt64:
+		ids = entity.NewColumnInt64(pkName, column.Data())
+	case *entity.ColumnString:
+		ids = entity.NewColumnVarChar(pkName, append([]string(nil), column.Data()...))
+	case *entity.ColumnVarChar:
+		ids = entity.NewColumnVarChar(pkName, append([]string(nil), column.Data()...))
+	}
+
+	fieldName := column.Name()
+	rs, err := c.QueryByPks(ctx, collName, partitions, ids, []string{pkName, fieldName})
+	if err != nil {
+		return nil, err
+	}
+	pkColumn, vectorColumn := rs.GetColumn(pkName), rs.GetColumn(fieldName)
+	if pkColumn == nil || vectorColumn == nil {
+		return nil, fmt.Errorf("query result does not contain field %s or %s", pkName, fieldName)
+	}
+	positions := make(map[interface{}]int, pkColumn.Len())
+	for i := 0; i < pkColumn.Len(); i++ {
+		pk, err := pkColumn.Get(i)
+		if err != nil {
+			return nil, err
+		}
+		positions[pk] = i
+	}
+
+	switch vectorColumn := vectorColumn.(type) {
+	case *entity.ColumnFloatVector:
+		data := make([][]float32, 0, column.Len())
+		for i := 0; i < column.Len(); i++ {
+			pos, err := idPosition(positions, column, i)
+			if err != nil {
+				return nil, err
+			}
+			vector, err := vectorColumn.ValueByIdx(pos)
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, vector)
+		}
+		return entity.NewColumnFloatVector(fieldName, vectorColumn.Dim(), data), nil
+	case *entity.ColumnBinaryVector:
+		data := make([][]byte, 0, column.Len())
+		for i := 0; i < column.Len(); i++ {
+			pos, err := idPosition(positions, column, i)
+			if err != nil {
+				return nil, err
+			}
+			vector, err := vectorColumn.ValueByIdx(pos)
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, vector)
+		}
+		return entity.NewColumnBinaryVector(fieldName, vectorColumn.Dim(), data), nil
+	default:
+		return nil, fmt.Errorf("field %s is not vector field", fieldName)
+	}
+}
+
+func idPosition(positions map[interface{}]int, ids entity.Column, idx int) (int, error) {
+	id, err := ids.Get(idx)
+	if err != nil {
+		return 0, err
+	}
+	pos, ok := positions[id]
+	if !ok {
+		return 0, fmt.Errorf("entity with id %v not found", id)
+	}
+	return pos, nil
+}
+
 func columnToVectorsArray(collName string, partitions []string, column entity.Column) *milvuspb.VectorsArray {
 	result := &milvuspb.VectorsArray{}
 	switch column.Type() {
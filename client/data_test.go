@@ -28,6 +28,8 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestGrpcClientFlush(t *testing.T) {
@@ -1023,6 +1025,151 @@ func TestGrpcCalcDistanceWithIDs(t *testing.T) {
 	})
 }
 
+type CalcDistanceSuite struct {
+	MockSuiteBase
+	sch *entity.Schema
+}
+
+func (s *CalcDistanceSuite) SetupSuite() {
+	s.MockSuiteBase.SetupSuite()
+
+	s.sch = entity.NewSchema().WithName(testCollectionName).
+		WithField(entity.NewField().WithName("ID").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true)).
+		WithField(entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithDim(2))
+}
+
+func (s *CalcDistanceSuite) TestLocalFallback() {
+	c := s.client
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.Run("unimplemented_with_vectors", func() {
+		defer s.resetMock()
+		s.setupHasCollection(testCollectionName)
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.mock.EXPECT().CalcDistance(mock.Anything, mock.AnythingOfType("*milvuspb.CalcDistanceRequest")).
+			Return(nil, status.Error(codes.Unimplemented, "removed"))
+
+		r, err := c.CalcDistance(ctx, testCollectionName, nil, entity.L2,
+			entity.NewColumnFloatVector("vector", 2, [][]float32{{0, 0}, {1, 1}}),
+			entity.NewColumnFloatVector("vector", 2, [][]float32{{1, 0}}))
+		s.Require().NoError(err)
+		column, ok := r.(*entity.ColumnFloat)
+		s.Require().True(ok)
+		s.Equal([]float32{1, 1}, column.Data())
+	})
+
+	s.Run("deprecated_status_with_ids", func() {
+		defer s.resetMock()
+		s.setupHasCollection(testCollectionName)
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.mock.EXPECT().CalcDistance(mock.Anything, mock.AnythingOfType("*milvuspb.CalcDistanceRequest")).
+			Return(&milvuspb.CalcDistanceResults{Status: &commonpb.Status{
+				ErrorCode: commonpb.ErrorCode_UnexpectedError,
+				Code:      statusCodeServiceUnavailable,
+				Reason:    "service unavailable[reason=CalcDistance deprecated]",
+			}}, nil)
+		s.mock.EXPECT().Query(mock.Anything, mock.AnythingOfType("*milvuspb.QueryRequest")).
+			Run(func(_ context.Context, req *milvuspb.QueryRequest) {
+				s.Equal("ID in [2,1]", req.GetExpr())
+				s.ElementsMatch([]string{"ID", "vector"}, req.GetOutputFields())
+			}).
+			Return(&milvuspb.QueryResults{
+				Status: getSuccessStatus(),
+				FieldsData: []*schemapb.FieldData{
+					s.getInt64FieldData("ID", []int64{1, 2}),
+					s.getFloatVectorFieldData("vector", 2, []float32{1, 0, 0, 1}),
+				},
+			}, nil)
+
+		r, err := c.CalcDistance(ctx, testCollectionName, nil, entity.IP,
+			entity.NewColumnInt64("vector", []int64{2, 1}),
+			entity.NewColumnFloatVector("vector", 2, [][]float32{{0, 1}}))
+		s.Require().NoError(err)
+		column, ok := r.(*entity.ColumnFloat)
+		s.Require().True(ok)
+		// ids order is kept, vector of id 2 is {0, 1}
+		s.Equal([]float32{1, 0}, column.Data())
+	})
+
+	s.Run("id_not_found", func() {
+		defer s.resetMock()
+		s.setupHasCollection(testCollectionName)
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.mock.EXPECT().CalcDistance(mock.Anything, mock.AnythingOfType("*milvuspb.CalcDistanceRequest")).
+			Return(nil, status.Error(codes.Unimplemented, "removed"))
+		s.mock.EXPECT().Query(mock.Anything, mock.AnythingOfType("*milvuspb.QueryRequest")).
+			Return(&milvuspb.QueryResults{
+				Status: getSuccessStatus(),
+				FieldsData: []*schemapb.FieldData{
+					s.getInt64FieldData("ID", []int64{1}),
+					s.getFloatVectorFieldData("vector", 2, []float32{1, 0}),
+				},
+			}, nil)
+
+		_, err := c.CalcDistance(ctx, testCollectionName, nil, entity.IP,
+			entity.NewColumnInt64("vector", []int64{1, 3}),
+			entity.NewColumnFloatVector("vector", 2, [][]float32{{0, 1}}))
+		s.Error(err)
+	})
+
+	s.Run("status_fail_not_fallback", func() {
+		defer s.resetMock()
+		s.setupHasCollection(testCollectionName)
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.mock.EXPECT().CalcDistance(mock.Anything, mock.AnythingOfType("*milvuspb.CalcDistanceRequest")).
+			Return(&milvuspb.CalcDistanceResults{Status: &commonpb.Status{ErrorCode: commonpb.ErrorCode_UnexpectedError, Reason: "mock"}}, nil)
+
+		_, err := c.CalcDistance(ctx, testCollectionName, nil, entity.L2,
+			entity.NewColumnFloatVector("vector", 2, [][]float32{{0, 0}}),
+			entity.NewColumnFloatVector("vector", 2, [][]float32{{1, 0}}))
+		s.Error(err)
+	})
+
+	s.Run("service_unavailable_not_fallback", func() {
+		defer s.resetMock()
+		s.setupHasCollection(testCollectionName)
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.mock.EXPECT().CalcDistance(mock.Anything, mock.AnythingOfType("*milvuspb.CalcDistanceRequest")).
+			Return(&milvuspb.CalcDistanceResults{Status: &commonpb.Status{
+				ErrorCode: commonpb.ErrorCode_UnexpectedError,
+				Code:      statusCodeServiceUnavailable,
+				Reason:    "service unavailable[reason=node not ready]",
+			}}, nil)
+
+		_, err := c.CalcDistance(ctx, testCollectionName, nil, entity.L2,
+			entity.NewColumnFloatVector("vector", 2, [][]float32{{0, 0}}),
+			entity.NewColumnFloatVector("vector", 2, [][]float32{{1, 0}}))
+		s.Error(err)
+	})
+
+	s.Run("vectors_without_describe", func() {
+		defer s.resetMock()
+		// no DescribeCollection expectation, calling it fails the test
+		vectors := entity.NewColumnFloatVector("vector", 2, [][]float32{{0, 0}})
+		column, err := c.(*GrpcClient).resolveVectors(ctx, testCollectionName, nil, vectors)
+		s.NoError(err)
+		s.Equal(vectors, column)
+	})
+
+	s.Run("other_error_not_fallback", func() {
+		defer s.resetMock()
+		s.setupHasCollection(testCollectionName)
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.mock.EXPECT().CalcDistance(mock.Anything, mock.AnythingOfType("*milvuspb.CalcDistanceRequest")).
+			Return(nil, errors.New("mock error"))
+
+		_, err := c.CalcDistance(ctx, testCollectionName, nil, entity.L2,
+			entity.NewColumnFloatVector("vector", 2, [][]float32{{0, 0}}),
+			entity.NewColumnFloatVector("vector", 2, [][]float32{{1, 0}}))
+		s.Error(err)
+	})
+}
+
+func TestCalcDistance(t *testing.T) {
+	suite.Run(t, new(CalcDistanceSuite))
+}
+
 func TestIsCollectionPrimaryKey(t *testing.T) {
 	t.Run("nil cases", func(t *testing.T) {
 		assert.False(t, isCollectionPrimaryKey(nil, nil))
@@ -1178,3 +1325,19 @@ func TestVector2PlaceHolder(t *testing.T) {
 		}
 	})
 }
+
+func TestPKs2Expr(t *testing.T) {
+	assert.Equal(t, "id in [1,2]", PKs2Expr("", entity.NewColumnInt64("id", []int64{1, 2})))
+	assert.Equal(t, "pk in [1]", PKs2Expr("pk", entity.NewColumnInt64("", []int64{1})))
+
+	ids := entity.NewColumnVarChar("key", []string{`a"b`, `c\d`})
+	assert.Equal(t, `key in ["a\"b","c\\d"]`, PKs2Expr("", ids))
+	// column data shall not be modified
+	assert.Equal(t, []string{`a"b`, `c\d`}, ids.Data())
+
+	_, err := pks2Expr("", entity.NewColumnInt64("id", nil))
+	assert.Error(t, err)
+	_, err = pks2Expr("", entity.NewColumnString("id", []string{"a"}))
+	assert.Error(t, err)
+	assert.Equal(t, "", PKs2Expr("", entity.NewColumnInt64("id", nil)))
+}
@@ -0,0 +1,140 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+// maxSearchTopK is the max topK server accepts.
+const maxSearchTopK = 16384
+
+// searchGroupBy searches groups of hits by the group by field, doubling topK of the queries without enough groups
+// until enough groups found or all hits fetched. The group by field is fetched for grouping,
+// but returned only when it is in outputFields.
+func (c *GrpcClient) searchGroupBy(ctx context.Context, schema *entity.Schema, collName string, partitions []string,
+	expr string, outputFields []string, vectors []entity.Vector, vectorField string, metricType entity.MetricType, topK int, sp entity.SearchParam, option *SearchQueryOption) ([]SearchResult, error) {
+	groupSize := option.GroupSize
+	if groupSize <= 0 {
+		return nil, errors.New("group size must be positive")
+	}
+	offset := int(option.Offset)
+	groups := offset + topK
+	fields := outputFields
+	requested := contains(outputFields, option.GroupByField)
+	if !requested {
+		fields = append(append([]string{}, outputFields...), option.GroupByField)
+	}
+	opt := *option
+	opt.Offset = 0
+
+	results := make([]SearchResult, len(vectors))
+	pending := make([]int, len(vectors))
+	for i := range pending {
+		pending[i] = i
+	}
+	fetch := groups * groupSize
+	for len(pending) > 0 {
+		if fetch > maxSearchTopK {
+			fetch = maxSearchTopK
+		}
+		queries := make([]entity.Vector, 0, len(pending))
+		for _, q := range pending {
+			queries = append(queries, vectors[q])
+		}
+		rs, err := c.search(ctx, schema, collName, partitions, expr, fields, queries, vectorField, metricType, fetch, sp, &opt)
+		if err != nil {
+			return nil, err
+		}
+		if len(rs) != len(queries) {
+			return nil, fmt.Errorf("expected %d results, got %d", len(queries), len(rs))
+		}
+		var next []int
+		for i, q := range pending {
+			if rs[i].Err != nil {
+				results[q] = rs[i]
+				continue
+			}
+			result, count, err := groupSearchResult(rs[i], option.GroupByField, offset, topK, groupSize)
+			if err != nil {
+				results[q] = SearchResult{Err: err}
+				continue
+			}
+			// fewer hits than topK means all hits are fetched
+			if count < groups && rs[i].ResultCount >= fetch && fetch < maxSearchTopK {
+				next = append(next, q)
+				continue
+			}
+			if !requested {
+				result.Fields = withoutColumn(result.Fields, option.GroupByField)
+			}
+			results[q] = result
+		}
+		pending = next
+		fetch *= 2
+	}
+	return results, nil
+}
+
+// groupSearchResult returns hits of groups in [offset, offset+topK), with the count of groups found.
+func groupSearchResult(result SearchResult, field string, offset, topK, groupSize int) (SearchResult, int, error) {
+	column := result.Fields.GetColumn(field)
+	if column == nil {
+		return SearchResult{}, 0, fmt.Errorf("group by field %s not found in search result", field)
+	}
+	// hits are ordered from the best, so are groups by first appearance
+	var keys []interface{}
+	members := make(map[interface{}][]int)
+	for i := 0; i < result.ResultCount; i++ {
+		key, err := sortKey(column, i)
+		if err != nil {
+			return SearchResult{}, 0, err
+		}
+		if _, ok := members[key]; !ok {
+			keys = append(keys, key)
+		}
+		if len(members[key]) < groupSize {
+			members[key] = append(members[key], i)
+		}
+	}
+	var indices []int
+	for i := offset; i < len(keys) && i < offset+topK; i++ {
+		indices = append(indices, members[keys[i]]...)
+	}
+	grouped, err := reorderSearchResult(result, indices, result.Scores)
+	return grouped, len(keys), err
+}
+
+// withoutColumn returns the result set without the column of name.
+func withoutColumn(rs ResultSet, name string) ResultSet {
+	result := make(ResultSet, 0, len(rs))
+	for _, column := range rs {
+		if column.Name() != name {
+			result = append(result, column)
+		}
+	}
+	return result
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+type GroupBySuite struct {
+	MockSuiteBase
+	sch *entity.Schema
+}
+
+func (s *GroupBySuite) SetupSuite() {
+	s.MockSuiteBase.SetupSuite()
+
+	s.sch = entity.NewSchema().WithName(testCollectionName).
+		WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true)).
+		WithField(entity.NewField().WithName("product").WithDataType(entity.FieldTypeInt64)).
+		WithField(entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithDim(2))
+}
+
+// setupSearch mocks search returning up to topk hits of ids 0-11 for each query, in the products of products.
+// The topk requested are sent to topks.
+func (s *GroupBySuite) setupSearch(topks chan<- int) {
+	products := []int64{1, 1, 1, 1, 2, 2, 2, 2, 3, 3, 4, 5}
+	s.mock.EXPECT().Search(mock.Anything, mock.AnythingOfType("*milvuspb.SearchRequest")).
+		Call.Return(func(_ context.Context, req *milvuspb.SearchRequest) *milvuspb.SearchResults {
+		topk, err := strconv.Atoi(entity.KvPairsMap(req.GetSearchParams())["topk"])
+		s.Require().NoError(err)
+		topks <- topk
+		if topk > len(products) {
+			topk = len(products)
+		}
+		var ids, values, counts []int64
+		var scores []float32
+		for q := int64(0); q < req.GetNq(); q++ {
+			for i := 0; i < topk; i++ {
+				ids = append(ids, int64(i))
+				values = append(values, products[i])
+				scores = append(scores, float32(100-i))
+			}
+			counts = append(counts, int64(topk))
+		}
+		var fields []*schemapb.FieldData
+		for _, field := range req.GetOutputFields() {
+			switch field {
+			case "id":
+				fields = append(fields, s.getInt64FieldData("id", ids))
+			case "product":
+				fields = append(fields, s.getInt64FieldData("product", values))
+			}
+		}
+		return &milvuspb.SearchResults{
+			Status: getSuccessStatus(),
+			Results: &schemapb.SearchResultData{
+				NumQueries: req.GetNq(),
+				FieldsData: fields,
+				Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids}}},
+				Scores:     scores,
+				Topks:      counts,
+			},
+		}
+	}, nil)
+}
+
+func (s *GroupBySuite) TestSearchGroupBy() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sp, err := entity.NewIndexFlatSearchParam()
+	s.Require().NoError(err)
+	vectors := []entity.Vector{entity.FloatVector([]float32{1, 0}), entity.FloatVector([]float32{0, 1})}
+
+	s.Run("over_fetch", func() {
+		defer s.resetMock()
+		topks := make(chan int, 2)
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupSearch(topks)
+
+		results, err := s.client.Search(ctx, testCollectionName, nil, "", []string{"id"}, vectors, "vector", entity.IP, 3, sp,
+			WithGroupBy("product", 2))
+		s.Require().NoError(err)
+		s.Require().Len(results, 2)
+		for _, result := range results {
+			s.Require().NoError(result.Err)
+			s.Equal(6, result.ResultCount)
+			s.Equal([]int64{0, 1, 4, 5, 8, 9}, result.IDs.(*entity.ColumnInt64).Data())
+			// group by field not requested is not returned
+			s.Nil(result.Fields.GetColumn("product"))
+			s.Len(result.Fields, 1)
+			s.Equal([]float32{100, 99, 96, 95, 92, 91}, result.Scores)
+		}
+		// 6 hits contain 2 products only
+		s.Equal(6, <-topks)
+		s.Equal(12, <-topks)
+	})
+
+	s.Run("group_field_requested", func() {
+		defer s.resetMock()
+		topks := make(chan int, 2)
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupSearch(topks)
+
+		results, err := s.client.Search(ctx, testCollectionName, nil, "", []string{"id", "product"}, vectors[:1], "vector", entity.IP, 3, sp,
+			WithGroupBy("product", 2))
+		s.Require().NoError(err)
+		s.Require().NoError(results[0].Err)
+		s.Equal([]int64{1, 1, 2, 2, 3, 3}, results[0].Fields.GetColumn("product").(*entity.ColumnInt64).Data())
+	})
+
+	s.Run("exhausted", func() {
+		defer s.resetMock()
+		topks := make(chan int, 1)
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupSearch(topks)
+
+		results, err := s.client.Search(ctx, testCollectionName, nil, "", nil, vectors[:1], "vector", entity.IP, 20, sp,
+			WithGroupBy("product", 1), WithOffset(2))
+		s.Require().NoError(err)
+		s.Require().NoError(results[0].Err)
+		s.Equal([]int64{8, 10, 11}, results[0].IDs.(*entity.ColumnInt64).Data())
+		s.Equal(22, <-topks)
+	})
+
+	s.Run("bad_option", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+
+		_, err := s.client.Search(ctx, testCollectionName, nil, "", nil, vectors, "vector", entity.IP, 3, sp,
+			WithGroupBy("product", 0))
+		s.Error(err)
+		_, err = s.client.Search(ctx, testCollectionName, nil, "", nil, vectors, "vector", entity.IP, 3, sp,
+			WithGroupBy("product", 1), WithRerankers(NewScoreThresholdReranker(0)))
+		s.Error(err)
+	})
+}
+
+func TestSearchGroupBy(t *testing.T) {
+	suite.Run(t, new(GroupBySuite))
+}
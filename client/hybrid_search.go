@@ -0,0 +1,294 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+const defaultRRFK = 60
+
+// AnnSearch is one vector field search of HybridSearch.
+type AnnSearch struct {
+	VectorField string
+	MetricType  entity.MetricType
+	Vectors     []entity.Vector // shall have the same count for all searches
+	Param       entity.SearchParam
+	TopK        int // count of hits fetched for fusion, topK of HybridSearch is used if not positive
+}
+
+// RankHits is the hits of one AnnSearch for one query, ordered from the most similar.
+type RankHits struct {
+	MetricType entity.MetricType
+	IDs        []interface{}
+	Scores     []float32
+}
+
+// Ranker fuses hits of several searches into one score per id.
+type Ranker interface {
+	// Fuse returns the fused score of each id in hits, greater means more relevant.
+	// hits are in the order of AnnSearch provided to HybridSearch.
+	Fuse(hits []RankHits) map[interface{}]float32
+}
+
+// RRFRanker is the Reciprocal Rank Fusion ranker, scoring each hit as the sum of 1/(k+rank) in all searches.
+type RRFRanker struct {
+	K int
+}
+
+// NewRRFRanker returns a RRFRanker, the smoothing constant k is 60 if not positive.
+func NewRRFRanker(k int) *RRFRanker {
+	if k <= 0 {
+		k = defaultRRFK
+	}
+	return &RRFRanker{K: k}
+}
+
+// Fuse implements Ranker.
+func (r *RRFRanker) Fuse(hits []RankHits) map[interface{}]float32 {
+	scores := make(map[interface{}]float32)
+	for _, h := range hits {
+		for rank, id := range h.IDs {
+			scores[id] += 1 / float32(r.K+rank+1)
+		}
+	}
+	return scores
+}
+
+// WeightedRanker scores each hit as the weighted sum of its normalized scores in all searches.
+// Scores are normalized into [0, 1] according to the metric type, where greater means more similar.
+type WeightedRanker struct {
+	Weights []float32
+}
+
+// NewWeightedRanker returns a WeightedRanker, one weight for each AnnSearch,
+// HybridSearch fails if the count of weights does not match the count of searches.
+func NewWeightedRanker(weights ...float32) *WeightedRanker {
+	return &WeightedRanker{Weights: weights}
+}
+
+// Fuse implements Ranker, searches without weight are ignored.
+func (r *WeightedRanker) Fuse(hits []RankHits) map[interface{}]float32 {
+	scores := make(map[interface{}]float32)
+	for i, h := range hits {
+		if i >= len(r.Weights) {
+			break
+		}
+		for j, id := range h.IDs {
+			scores[id] += r.Weights[i] * NormalizeScore(h.MetricType, h.Scores[j])
+		}
+	}
+	return scores
+}
+
+// NormalizeScore maps the score of metric type into [0, 1], where greater means more similar.
+func NormalizeScore(metricType entity.MetricType, score float32) float32 {
+	switch metricType {
+	case entity.COSINE:
+		return (score + 1) / 2
+	case entity.IP:
+		return 0.5 + float32(math.Atan(float64(score)))/math.Pi
+	default:
+		// distances, the smaller the more similar
+		return 1 - 2*float32(math.Atan(float64(score)))/math.Pi
+	}
+}
+
+// HybridSearch runs searches on several vector fields concurrently with the shared filter and partitions,
+// then fuses hits of each query with ranker, returning topK hits per query with fused scores.
+// Hits of a query fail with Err if any of the searches fails for the query.
+func (c *GrpcClient) HybridSearch(ctx context.Context, collName string, partitions []string, expr string, outputFields []string,
+	searches []AnnSearch, ranker Ranker, topK int, opts ...SearchQueryOptionFunc) ([]SearchResult, error) {
+	if c.Service == nil {
+		return nil, ErrClientNotReady
+	}
+	if len(searches) == 0 {
+		return nil, errors.New("no search provided")
+	}
+	if ranker == nil {
+		return nil, errors.New("ranker cannot be nil")
+	}
+	if topK <= 0 {
+		return nil, errors.New("topK must be positive")
+	}
+	if weighted, ok := ranker.(*WeightedRanker); ok && len(weighted.Weights) != len(searches) {
+		return nil, fmt.Errorf("weighted ranker has %d weights, expected %d", len(weighted.Weights), len(searches))
+	}
+	nq := len(searches[0].Vectors)
+	for i, s := range searches {
+		if len(s.Vectors) != nq {
+			return nil, fmt.Errorf("search %d has %d vectors, expected %d", i, len(s.Vectors), nq)
+		}
+	}
+
+	results := make([][]SearchResult, len(searches))
+	errs := make([]error, len(searches))
+	var wg sync.WaitGroup
+	for i, s := range searches {
+		wg.Add(1)
+		go func(i int, s AnnSearch) {
+			defer wg.Done()
+			k := s.TopK
+			if k <= 0 {
+				k = topK
+			}
+			results[i], errs[i] = c.Search(ctx, collName, partitions, expr, outputFields, s.Vectors, s.VectorField, s.MetricType, k, s.Param, opts...)
+			if errs[i] == nil && len(results[i]) != nq {
+				errs[i] = fmt.Errorf("expected %d results, got %d", nq, len(results[i]))
+			}
+		}(i, s)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("search on field %s failed: %w", searches[i].VectorField, err)
+		}
+	}
+
+	fused := make([]SearchResult, 0, nq)
+	for q := 0; q < nq; q++ {
+		subs := make([]*SearchResult, 0, len(searches))
+		for i := range searches {
+			subs = append(subs, &results[i][q])
+		}
+		result, err := fuseSearchResults(subs, searches, ranker, topK)
+		if err != nil {
+			result = SearchResult{Err: err}
+		}
+		fused = append(fused, result)
+	}
+	return fused, nil
+}
+
+// fuseSearchResults fuses the results of one query by ranker.
+func fuseSearchResults(subs []*SearchResult, searches []AnnSearch, ranker Ranker, topK int) (SearchResult, error) {
+	hits := make([]RankHits, 0, len(subs))
+	// the first hit of each id provides its output fields
+	first := make(map[interface{}]searchHit)
+	var order []interface{}
+	for i, sub := range subs {
+		if sub.Err != nil {
+			return SearchResult{}, fmt.Errorf("search on field %s failed: %w", searches[i].VectorField, sub.Err)
+		}
+		h := RankHits{MetricType: searches[i].MetricType, IDs: make([]interface{}, 0, sub.ResultCount), Scores: sub.Scores}
+		for j := 0; j < sub.ResultCount; j++ {
+			id, err := sub.IDs.Get(j)
+			if err != nil {
+				return SearchResult{}, err
+			}
+			h.IDs = append(h.IDs, id)
+			if _, ok := first[id]; !ok {
+				first[id] = searchHit{result: sub, idx: j}
+				order = append(order, id)
+			}
+		}
+		hits = append(hits, h)
+	}
+
+	scores := ranker.Fuse(hits)
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+	if len(order) > topK {
+		order = order[:topK]
+	}
+	selected := make([]searchHit, 0, len(order))
+	for _, id := range order {
+		hit := first[id]
+		hit.score = scores[id]
+		selected = append(selected, hit)
+	}
+	return buildSearchResult(selected, subs)
+}
+
+// searchHit locates a hit in a search result, with the score to output.
+type searchHit struct {
+	result *SearchResult
+	idx    int
+	score  float32
+}
+
+// buildSearchResult composes the search result of hits, which may come from different search results
+// with the same output fields. Rows are taken once from each source, then arranged in the order of hits.
+// Columns of the result are empty columns of the first source when there is no hit.
+func buildSearchResult(hits []searchHit, sources []*SearchResult) (SearchResult, error) {
+	var base *SearchResult
+	for _, source := range sources {
+		if source.IDs != nil {
+			base = source
+			break
+		}
+	}
+	if base == nil {
+		return SearchResult{}, nil
+	}
+
+	// positions of hits in each source, sources are kept in the order of first hit
+	var order []*SearchResult
+	indices := make(map[*SearchResult][]int)
+	for _, hit := range hits {
+		if _, ok := indices[hit.result]; !ok {
+			order = append(order, hit.result)
+		}
+		indices[hit.result] = append(indices[hit.result], hit.idx)
+	}
+	ids := base.IDs.Slice(0, 0)
+	fields := base.Fields.slice(0, 0)
+	offsets := make(map[*SearchResult]int, len(order))
+	for _, source := range order {
+		offsets[source] = ids.Len()
+		taken, err := takeColumn(source.IDs, indices[source])
+		if err != nil {
+			return SearchResult{}, err
+		}
+		if ids, err = ids.Concat(taken); err != nil {
+			return SearchResult{}, err
+		}
+		rows, err := source.Fields.take(indices[source])
+		if err != nil {
+			return SearchResult{}, err
+		}
+		if fields, err = fields.concat(rows); err != nil {
+			return SearchResult{}, err
+		}
+	}
+
+	// rows of the same source are in hit order, locate each hit by the offset of its source
+	positions := make([]int, 0, len(hits))
+	scores := make([]float32, 0, len(hits))
+	for _, hit := range hits {
+		positions = append(positions, offsets[hit.result])
+		offsets[hit.result]++
+		scores = append(scores, hit.score)
+	}
+	ids, err := takeColumn(ids, positions)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	if fields, err = fields.take(positions); err != nil {
+		return SearchResult{}, err
+	}
+	return SearchResult{
+		ResultCount: len(hits),
+		IDs:         ids,
+		Fields:      fields,
+		Scores:      scores,
+	}, nil
+}
@@ -0,0 +1,230 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+func TestRankers(t *testing.T) {
+	hits := []RankHits{
+		{MetricType: entity.IP, IDs: []interface{}{int64(1), int64(2)}, Scores: []float32{0.9, 0.1}},
+		{MetricType: entity.L2, IDs: []interface{}{int64(2), int64(3)}, Scores: []float32{0.1, 5}},
+	}
+
+	scores := NewRRFRanker(0).Fuse(hits)
+	assert.InDelta(t, 1.0/61, scores[int64(1)], 1e-6)
+	assert.InDelta(t, 1.0/62+1.0/61, scores[int64(2)], 1e-6)
+	assert.InDelta(t, 1.0/62, scores[int64(3)], 1e-6)
+
+	scores = NewWeightedRanker(0.5, 2).Fuse(hits)
+	assert.InDelta(t, 0.5*NormalizeScore(entity.IP, 0.9), scores[int64(1)], 1e-6)
+	assert.InDelta(t, 0.5*NormalizeScore(entity.IP, 0.1)+2*NormalizeScore(entity.L2, 0.1), scores[int64(2)], 1e-6)
+	// weight not provided
+	scores = NewWeightedRanker(1).Fuse(hits)
+	assert.NotContains(t, scores, int64(3))
+
+	// normalized scores keep the similarity order of metric
+	assert.Greater(t, NormalizeScore(entity.L2, 0.1), NormalizeScore(entity.L2, 1))
+	assert.Greater(t, NormalizeScore(entity.IP, 1), NormalizeScore(entity.IP, 0.1))
+	assert.InDelta(t, 1, NormalizeScore(entity.COSINE, 1), 1e-6)
+	assert.InDelta(t, 0, NormalizeScore(entity.COSINE, -1), 1e-6)
+	for _, score := range []float32{-100, -1, 0, 1, 100} {
+		v := NormalizeScore(entity.IP, score)
+		assert.True(t, v >= 0 && v <= 1)
+		// distances are never negative
+		v = NormalizeScore(entity.L2, score*score)
+		assert.True(t, v >= 0 && v <= 1)
+	}
+}
+
+func TestBuildSearchResult(t *testing.T) {
+	newResult := func(ids []int64, names []string) *SearchResult {
+		return &SearchResult{
+			ResultCount: len(ids),
+			IDs:         entity.NewColumnInt64("id", ids),
+			Fields:      ResultSet{entity.NewColumnVarChar("name", names)},
+			Scores:      make([]float32, len(ids)),
+		}
+	}
+	a := newResult([]int64{1, 2, 3}, []string{"a1", "a2", "a3"})
+	b := newResult([]int64{4, 5}, []string{"b4", "b5"})
+
+	// hits interleaved from both sources keep their order
+	result, err := buildSearchResult([]searchHit{
+		{result: b, idx: 1, score: 5}, {result: a, idx: 2, score: 4}, {result: b, idx: 0, score: 3}, {result: a, idx: 0, score: 2},
+	}, []*SearchResult{a, b})
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.ResultCount)
+	assert.Equal(t, []int64{5, 3, 4, 1}, result.IDs.(*entity.ColumnInt64).Data())
+	assert.Equal(t, []string{"b5", "a3", "b4", "a1"}, result.Fields.GetColumn("name").(*entity.ColumnVarChar).Data())
+	assert.Equal(t, []float32{5, 4, 3, 2}, result.Scores)
+	// sources are not modified
+	assert.Equal(t, []int64{1, 2, 3}, a.IDs.(*entity.ColumnInt64).Data())
+
+	result, err = buildSearchResult(nil, []*SearchResult{{}, b})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.IDs.Len())
+	assert.Equal(t, 0, result.Fields.GetColumn("name").Len())
+}
+
+type HybridSearchSuite struct {
+	MockSuiteBase
+	sch *entity.Schema
+}
+
+func (s *HybridSearchSuite) SetupSuite() {
+	s.MockSuiteBase.SetupSuite()
+
+	s.sch = entity.NewSchema().WithName(testCollectionName).
+		WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true)).
+		WithField(entity.NewField().WithName("text").WithDataType(entity.FieldTypeFloatVector).WithDim(2)).
+		WithField(entity.NewField().WithName("image").WithDataType(entity.FieldTypeFloatVector).WithDim(2))
+}
+
+// setupSearch mocks search returning the same hits of field for each query.
+func (s *HybridSearchSuite) setupSearch(hits map[string][]int64, scores map[string][]float32) {
+	s.mock.EXPECT().Search(mock.Anything, mock.AnythingOfType("*milvuspb.SearchRequest")).
+		Call.Return(func(_ context.Context, req *milvuspb.SearchRequest) *milvuspb.SearchResults {
+		s.Equal("id > 0", req.GetDsl())
+		s.Equal([]string{"p1"}, req.GetPartitionNames())
+		field := entity.KvPairsMap(req.GetSearchParams())["anns_field"]
+		if _, ok := hits[field]; !ok {
+			return &milvuspb.SearchResults{Status: &commonpb.Status{ErrorCode: commonpb.ErrorCode_UnexpectedError}}
+		}
+		var ids []int64
+		var result []float32
+		var topks []int64
+		for i := int64(0); i < req.GetNq(); i++ {
+			ids = append(ids, hits[field]...)
+			result = append(result, scores[field]...)
+			topks = append(topks, int64(len(hits[field])))
+		}
+		return &milvuspb.SearchResults{
+			Status: getSuccessStatus(),
+			Results: &schemapb.SearchResultData{
+				NumQueries: req.GetNq(),
+				FieldsData: []*schemapb.FieldData{s.getInt64FieldData("id", ids)},
+				Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids}}},
+				Scores:     result,
+				Topks:      topks,
+			},
+		}
+	}, nil)
+}
+
+func (s *HybridSearchSuite) TestHybridSearch() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sp, err := entity.NewIndexFlatSearchParam()
+	s.Require().NoError(err)
+	vectors := []entity.Vector{entity.FloatVector([]float32{0.1, 0.2}), entity.FloatVector([]float32{0.3, 0.4})}
+	searches := []AnnSearch{
+		{VectorField: "text", MetricType: entity.IP, Vectors: vectors, Param: sp},
+		{VectorField: "image", MetricType: entity.L2, Vectors: vectors, Param: sp, TopK: 5},
+	}
+	hits := map[string][]int64{"text": {1, 2, 3}, "image": {3, 4, 1}}
+	scores := map[string][]float32{"text": {0.9, 0.8, 0.7}, "image": {0.1, 0.2, 0.3}}
+
+	s.Run("rrf", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupSearch(hits, scores)
+
+		results, err := s.client.HybridSearch(ctx, testCollectionName, []string{"p1"}, "id > 0", []string{"id"},
+			searches, NewRRFRanker(60), 3)
+		s.Require().NoError(err)
+		s.Require().Len(results, 2)
+		for _, result := range results {
+			s.NoError(result.Err)
+			s.Equal(3, result.ResultCount)
+			// 1 and 3 rank first and third in both searches
+			s.Equal([]int64{1, 3, 2}, result.IDs.(*entity.ColumnInt64).Data())
+			s.Equal([]int64{1, 3, 2}, result.Fields.GetColumn("id").(*entity.ColumnInt64).Data())
+			s.InDelta(1.0/61+1.0/63, result.Scores[0], 1e-6)
+			s.InDelta(1.0/62, result.Scores[2], 1e-6)
+		}
+	})
+
+	s.Run("weighted", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupSearch(hits, scores)
+
+		results, err := s.client.HybridSearch(ctx, testCollectionName, []string{"p1"}, "id > 0", []string{"id"},
+			searches, NewWeightedRanker(0, 1), 2)
+		s.Require().NoError(err)
+		s.Require().Len(results, 2)
+		// only image search counts, whose distance is the smaller the better
+		s.Equal([]int64{3, 4}, results[0].IDs.(*entity.ColumnInt64).Data())
+		s.InDelta(NormalizeScore(entity.L2, 0.1), results[0].Scores[0], 1e-6)
+	})
+
+	s.Run("search_fail", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupSearch(map[string][]int64{"text": {1}}, map[string][]float32{"text": {1}})
+
+		_, err := s.client.HybridSearch(ctx, testCollectionName, []string{"p1"}, "id > 0", nil, searches, NewRRFRanker(0), 3)
+		s.Error(err)
+	})
+
+	s.Run("bad_args", func() {
+		_, err := s.client.HybridSearch(ctx, testCollectionName, nil, "", nil, nil, NewRRFRanker(0), 3)
+		s.Error(err)
+		_, err = s.client.HybridSearch(ctx, testCollectionName, nil, "", nil, searches, nil, 3)
+		s.Error(err)
+		_, err = s.client.HybridSearch(ctx, testCollectionName, nil, "", nil, searches, NewRRFRanker(0), 0)
+		s.Error(err)
+		_, err = s.client.HybridSearch(ctx, testCollectionName, nil, "", nil, []AnnSearch{
+			{VectorField: "text", MetricType: entity.IP, Vectors: vectors, Param: sp},
+			{VectorField: "image", MetricType: entity.L2, Vectors: vectors[:1], Param: sp},
+		}, NewRRFRanker(0), 3)
+		s.Error(err)
+		_, err = s.client.HybridSearch(ctx, testCollectionName, nil, "", nil, searches, NewWeightedRanker(1), 3)
+		s.Error(err, "weights not matching searches")
+	})
+
+	s.Run("no_hits", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupSearch(map[string][]int64{"text": {}, "image": {}}, map[string][]float32{})
+
+		results, err := s.client.HybridSearch(ctx, testCollectionName, []string{"p1"}, "id > 0", []string{"id"},
+			searches, NewRRFRanker(0), 3)
+		s.Require().NoError(err)
+		s.Require().Len(results, 2)
+		for _, result := range results {
+			s.NoError(result.Err)
+			s.Equal(0, result.ResultCount)
+			s.Require().NotNil(result.IDs)
+			s.Equal(0, result.IDs.Len())
+			s.Require().NotNil(result.Fields.GetColumn("id"))
+			s.Equal(0, result.Fields.GetColumn("id").Len())
+		}
+	})
+}
+
+func TestHybridSearch(t *testing.T) {
+	suite.Run(t, new(HybridSearchSuite))
+}
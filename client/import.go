@@ -0,0 +1,101 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// ObjectStore is the object storage which Milvus reads bulk insert files from,
+// the objectstore package provides the in-memory implementation
+// and the standalone objectstore/s3 module provides the S3 compatible one.
+type ObjectStore interface {
+	// Put uploads the object with key and returns the path to pass to BulkInsert,
+	// which is relative to the bucket of Milvus.
+	Put(ctx context.Context, key string, r io.Reader, size int64) (string, error)
+	// Delete removes the object of the path returned by Put.
+	Delete(ctx context.Context, objectPath string) error
+}
+
+// ImportFiles uploads groups of local files, like bulkwriter.Writer.Files returns, to the object store
+// and calls BulkInsert for each group, returning the task ids in group order.
+// Objects of groups not submitted are deleted when failed, tasks already submitted are returned with the error.
+func ImportFiles(ctx context.Context, c Client, collName string, partitionName string,
+	localFiles [][]string, store ObjectStore, opts ...BulkInsertOption) ([]int64, error) {
+	if c == nil {
+		return nil, ErrClientNotReady
+	}
+	if store == nil {
+		return nil, errors.New("object store cannot be nil")
+	}
+	batch := fmt.Sprintf("bulk_insert/%s/%d", collName, time.Now().UnixNano())
+	taskIDs := make([]int64, 0, len(localFiles))
+	for i, files := range localFiles {
+		// files of a group are kept in the same directory, since numpy files are matched by file name
+		prefix := path.Join(batch, fmt.Sprint(i))
+		objects, err := uploadFiles(ctx, store, prefix, files)
+		if err != nil {
+			return taskIDs, err
+		}
+		taskID, err := c.BulkInsert(ctx, collName, partitionName, objects, opts...)
+		if err != nil {
+			deleteObjects(store, objects)
+			return taskIDs, fmt.Errorf("failed to bulk insert file group %d: %w", i, err)
+		}
+		taskIDs = append(taskIDs, taskID)
+	}
+	return taskIDs, nil
+}
+
+// uploadFiles uploads files under prefix, uploaded objects are deleted if any upload fails.
+func uploadFiles(ctx context.Context, store ObjectStore, prefix string, files []string) ([]string, error) {
+	objects := make([]string, 0, len(files))
+	for _, file := range files {
+		object, err := uploadFile(ctx, store, path.Join(prefix, filepath.Base(file)), file)
+		if err != nil {
+			deleteObjects(store, objects)
+			return nil, fmt.Errorf("failed to upload %s: %w", file, err)
+		}
+		objects = append(objects, object)
+	}
+	return objects, nil
+}
+
+func uploadFile(ctx context.Context, store ObjectStore, key string, file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	return store.Put(ctx, key, f, info.Size())
+}
+
+// deleteObjects deletes objects in best effort, with a context not canceled with the failed request.
+func deleteObjects(store ObjectStore, objects []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	for _, object := range objects {
+		_ = store.Delete(ctx, object)
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/milvus-io/milvus-sdk-go/v2/objectstore"
+)
+
+type ImportFilesSuite struct {
+	MockSuiteBase
+	files [][]string
+}
+
+func (s *ImportFilesSuite) SetupTest() {
+	s.MockSuiteBase.SetupTest()
+	dir := s.T().TempDir()
+	s.files = nil
+	for i, group := range [][]string{{"1.json"}, {"id.npy", "vector.npy"}} {
+		var files []string
+		for _, name := range group {
+			file := filepath.Join(dir, string(rune('a'+i)), name)
+			s.Require().NoError(os.MkdirAll(filepath.Dir(file), 0o755))
+			s.Require().NoError(os.WriteFile(file, []byte(name), 0o644))
+			files = append(files, file)
+		}
+		s.files = append(s.files, files)
+	}
+}
+
+// setupImport mocks Import returning task id from 1, failing the request of failGroup.
+func (s *ImportFilesSuite) setupImport(failGroup int, requests *[][]string) {
+	s.mock.EXPECT().Import(mock.Anything, mock.AnythingOfType("*milvuspb.ImportRequest")).
+		Call.Return(func(_ context.Context, req *milvuspb.ImportRequest) *milvuspb.ImportResponse {
+		*requests = append(*requests, req.GetFiles())
+		if len(*requests)-1 == failGroup {
+			return &milvuspb.ImportResponse{Status: &commonpb.Status{ErrorCode: commonpb.ErrorCode_UnexpectedError}}
+		}
+		return &milvuspb.ImportResponse{Status: getSuccessStatus(), Tasks: []int64{int64(len(*requests))}}
+	}, nil)
+}
+
+func (s *ImportFilesSuite) TestImportFiles() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.Run("normal_case", func() {
+		defer s.resetMock()
+		var requests [][]string
+		s.setupImport(-1, &requests)
+		store := objectstore.NewMemory()
+
+		taskIDs, err := ImportFiles(ctx, s.client, testCollectionName, "", s.files, store)
+		s.Require().NoError(err)
+		s.Equal([]int64{1, 2}, taskIDs)
+		s.Require().Len(requests, 2)
+		s.Len(requests[0], 1)
+		s.True(strings.HasSuffix(requests[0][0], "/0/1.json"))
+		s.Require().Len(requests[1], 2)
+		s.Equal(filepath.Dir(requests[1][0]), filepath.Dir(requests[1][1]))
+		s.True(strings.HasSuffix(requests[1][1], "/1/vector.npy"))
+
+		s.Len(store.Paths(), 3)
+		bs, ok := store.Get(requests[1][0])
+		s.True(ok)
+		s.Equal("id.npy", string(bs))
+	})
+
+	s.Run("bulk_insert_fail", func() {
+		defer s.resetMock()
+		var requests [][]string
+		s.setupImport(1, &requests)
+		store := objectstore.NewMemory()
+
+		taskIDs, err := ImportFiles(ctx, s.client, testCollectionName, "", s.files, store)
+		s.Error(err)
+		s.Equal([]int64{1}, taskIDs)
+		// objects of submitted group are kept
+		s.Equal(requests[0], store.Paths())
+	})
+
+	s.Run("upload_fail", func() {
+		defer s.resetMock()
+		var requests [][]string
+		s.setupImport(-1, &requests)
+		store := objectstore.NewMemory()
+
+		files := [][]string{{s.files[1][0], filepath.Join(s.T().TempDir(), "not_exist.npy")}}
+		taskIDs, err := ImportFiles(ctx, s.client, testCollectionName, "", files, store)
+		s.Error(err)
+		s.Empty(taskIDs)
+		s.Empty(requests)
+		s.Empty(store.Paths())
+	})
+
+	s.Run("nil_store", func() {
+		_, err := ImportFiles(ctx, s.client, testCollectionName, "", s.files, nil)
+		s.Error(err)
+	})
+}
+
+func TestImportFiles(t *testing.T) {
+	suite.Run(t, new(ImportFilesSuite))
+}
+
+type WaitBulkInsertSuite struct {
+	MockSuiteBase
+}
+
+// setupImportState mocks GetImportState returning states of task in order, the last one is repeated.
+func (s *WaitBulkInsertSuite) setupImportState(states map[int64][]*milvuspb.GetImportStateResponse) {
+	var mut sync.Mutex
+	calls := make(map[int64]int)
+	s.mock.EXPECT().GetImportState(mock.Anything, mock.AnythingOfType("*milvuspb.GetImportStateRequest")).
+		Call.Return(func(_ context.Context, req *milvuspb.GetImportStateRequest) *milvuspb.GetImportStateResponse {
+		mut.Lock()
+		defer mut.Unlock()
+		responses := states[req.GetTask()]
+		idx := calls[req.GetTask()]
+		if idx >= len(responses) {
+			idx = len(responses) - 1
+		}
+		calls[req.GetTask()]++
+		return responses[idx]
+	}, nil)
+}
+
+func importState(id int64, state commonpb.ImportState, progress string, infos ...*commonpb.KeyValuePair) *milvuspb.GetImportStateResponse {
+	return &milvuspb.GetImportStateResponse{
+		Status:     getSuccessStatus(),
+		Id:         id,
+		State:      state,
+		RowCount:   int64(10 * id),
+		SegmentIds: []int64{id * 100},
+		Infos:      append(infos, &commonpb.KeyValuePair{Key: entity.ImportProgress, Value: progress}),
+	}
+}
+
+func (s *WaitBulkInsertSuite) TestWait() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.Run("normal_case", func() {
+		defer s.resetMock()
+		s.setupImportState(map[int64][]*milvuspb.GetImportStateResponse{
+			1: {importState(1, commonpb.ImportState_ImportStarted, "50"), importState(1, commonpb.ImportState_ImportPersisted, "100")},
+			2: {importState(2, commonpb.ImportState_ImportPersisted, "100")},
+		})
+		var events []BulkInsertProgress
+		results, err := WaitBulkInsert(ctx, s.client, []int64{1, 2},
+			WithWaitInterval(time.Millisecond, time.Millisecond),
+			WithWaitProgress(func(p BulkInsertProgress) { events = append(events, p) }))
+		s.Require().NoError(err)
+		s.Require().Len(results, 2)
+		s.Equal(BulkInsertResult{TaskID: 1, State: entity.BulkInsertPersisted, RowCount: 10, SegmentIDs: []int64{100}}, results[0])
+		s.EqualValues(20, results[1].RowCount)
+
+		s.Require().Len(events, 3)
+		s.Equal(25, events[0].Percent)
+		s.Equal(75, events[1].Percent)
+		s.Equal(1, events[1].Finished)
+		s.Equal(100, events[2].Percent)
+		s.Equal(2, events[2].Finished)
+		s.EqualValues(1, events[2].Task.ID)
+	})
+
+	s.Run("wait_index", func() {
+		defer s.resetMock()
+		s.setupImportState(map[int64][]*milvuspb.GetImportStateResponse{
+			1: {importState(1, commonpb.ImportState_ImportPersisted, "100"), importState(1, commonpb.ImportState_ImportCompleted, "100")},
+		})
+		ch := make(chan BulkInsertProgress, 10)
+		results, err := WaitBulkInsert(ctx, s.client, []int64{1},
+			WithWaitInterval(time.Millisecond, 0), WithWaitIndex(), WithWaitProgressChan(ch))
+		s.Require().NoError(err)
+		s.Equal(entity.BulkInsertCompleted, results[0].State)
+		s.Len(ch, 2)
+	})
+
+	s.Run("task_failed", func() {
+		defer s.resetMock()
+		s.setupImportState(map[int64][]*milvuspb.GetImportStateResponse{
+			1: {importState(1, commonpb.ImportState_ImportFailed, "0",
+				&commonpb.KeyValuePair{Key: entity.ImportFailedReason, Value: "bad file"})},
+			2: {importState(2, commonpb.ImportState_ImportCompleted, "100")},
+		})
+		results, err := WaitBulkInsert(ctx, s.client, []int64{1, 2})
+		s.Error(err)
+		s.Contains(err.Error(), "bad file")
+		s.Require().Len(results, 2)
+		s.True(results[0].Failed())
+		s.Equal("bad file", results[0].Reason)
+		s.False(results[1].Failed())
+	})
+
+	s.Run("get_state_fail", func() {
+		defer s.resetMock()
+		s.mock.EXPECT().GetImportState(mock.Anything, mock.AnythingOfType("*milvuspb.GetImportStateRequest")).
+			Return(&milvuspb.GetImportStateResponse{Status: &commonpb.Status{ErrorCode: commonpb.ErrorCode_UnexpectedError}}, nil)
+		_, err := WaitBulkInsert(ctx, s.client, []int64{1})
+		s.Error(err)
+	})
+
+	s.Run("context_canceled", func() {
+		// mock is not reset, the GetImportState call canceled on client side may still reach the server
+		s.setupImportState(map[int64][]*milvuspb.GetImportStateResponse{
+			1: {importState(1, commonpb.ImportState_ImportStarted, "10")},
+		})
+		cctx, ccancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer ccancel()
+		_, err := WaitBulkInsert(cctx, s.client, []int64{1}, WithWaitInterval(time.Millisecond, 5*time.Millisecond))
+		s.Error(err)
+	})
+}
+
+func TestWaitBulkInsert(t *testing.T) {
+	suite.Run(t, new(WaitBulkInsertSuite))
+}
@@ -0,0 +1,220 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+const (
+	defaultWaitInterval    = 500 * time.Millisecond
+	defaultWaitMaxInterval = 10 * time.Second
+)
+
+// BulkInsertProgress is the event emitted when state or progress of a bulk insert task changes.
+type BulkInsertProgress struct {
+	Task     *entity.BulkInsertTaskState // the changed task
+	Total    int                         // count of waited tasks
+	Finished int                         // count of tasks succeeded or failed
+	Failed   int                         // count of failed tasks
+	Percent  int                         // average progress percent of all tasks
+}
+
+// BulkInsertResult is the final result of a bulk insert task.
+type BulkInsertResult struct {
+	TaskID     int64
+	State      entity.BulkInsertState
+	RowCount   int64
+	IDList     []int64 // auto generated ids if the primary key is autoid
+	SegmentIDs []int64
+	Reason     string // failure reason reported by server
+}
+
+// Failed reports whether the task failed.
+func (r BulkInsertResult) Failed() bool {
+	return r.State == entity.BulkInsertFailed || r.State == entity.BulkInsertFailedAndCleaned
+}
+
+// WaitBulkInsertOption is option for WaitBulkInsert.
+type WaitBulkInsertOption func(opt *waitBulkInsertOpt)
+
+type waitBulkInsertOpt struct {
+	interval    time.Duration
+	maxInterval time.Duration
+	waitIndex   bool
+	progress    func(BulkInsertProgress)
+	progressCh  chan<- BulkInsertProgress
+}
+
+// WithWaitInterval sets the initial polling interval, which is doubled after each poll up to maxInterval.
+// By default polling starts at 500ms, up to 10s.
+func WithWaitInterval(interval, maxInterval time.Duration) WaitBulkInsertOption {
+	return func(opt *waitBulkInsertOpt) {
+		opt.interval = interval
+		opt.maxInterval = maxInterval
+	}
+}
+
+// WithWaitIndex waits until the indexes of imported segments are built,
+// otherwise tasks are finished once data is persisted.
+func WithWaitIndex() WaitBulkInsertOption {
+	return func(opt *waitBulkInsertOpt) {
+		opt.waitIndex = true
+	}
+}
+
+// WithWaitProgress sets the callback called with progress events.
+func WithWaitProgress(fn func(BulkInsertProgress)) WaitBulkInsertOption {
+	return func(opt *waitBulkInsertOpt) {
+		opt.progress = fn
+	}
+}
+
+// WithWaitProgressChan sends progress events to ch, sending blocks polling until the event is received.
+// The channel is not closed by WaitBulkInsert.
+func WithWaitProgressChan(ch chan<- BulkInsertProgress) WaitBulkInsertOption {
+	return func(opt *waitBulkInsertOpt) {
+		opt.progressCh = ch
+	}
+}
+
+// WaitBulkInsert polls bulk insert tasks until all of them succeed or fail, and returns results in task order.
+// When some tasks fail, results are returned with an error containing the failure reasons.
+func WaitBulkInsert(ctx context.Context, c Client, taskIDs []int64, opts ...WaitBulkInsertOption) ([]BulkInsertResult, error) {
+	if c == nil {
+		return nil, ErrClientNotReady
+	}
+	opt := waitBulkInsertOpt{interval: defaultWaitInterval, maxInterval: defaultWaitMaxInterval}
+	for _, o := range opts {
+		o(&opt)
+	}
+	if opt.interval <= 0 {
+		opt.interval = defaultWaitInterval
+	}
+	if opt.maxInterval < opt.interval {
+		opt.maxInterval = opt.interval
+	}
+
+	states := make([]*entity.BulkInsertTaskState, len(taskIDs))
+	interval := opt.interval
+	for {
+		pending := 0
+		for i, taskID := range taskIDs {
+			if states[i] != nil && opt.finished(states[i].State) {
+				continue
+			}
+			state, err := c.GetBulkInsertState(ctx, taskID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get state of bulk insert task %d: %w", taskID, err)
+			}
+			changed := states[i] == nil || states[i].State != state.State || states[i].Progress() != state.Progress()
+			states[i] = state
+			if !opt.finished(state.State) {
+				pending++
+			}
+			if changed {
+				if err := opt.emit(ctx, newBulkInsertProgress(state, states, opt)); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if pending == 0 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+		interval *= 2
+		if interval > opt.maxInterval {
+			interval = opt.maxInterval
+		}
+	}
+
+	results := make([]BulkInsertResult, 0, len(states))
+	var failures []string
+	for _, state := range states {
+		result := BulkInsertResult{
+			TaskID:     state.ID,
+			State:      state.State,
+			RowCount:   state.RowCount,
+			IDList:     state.IDList,
+			SegmentIDs: state.SegmentIDs,
+			Reason:     state.FailedReason(),
+		}
+		if result.Failed() {
+			failures = append(failures, fmt.Sprintf("task %d: %s", state.ID, result.Reason))
+		}
+		results = append(results, result)
+	}
+	if len(failures) > 0 {
+		return results, fmt.Errorf("bulk insert failed, %s", strings.Join(failures, "; "))
+	}
+	return results, nil
+}
+
+// finished reports whether the task reaches a final state.
+func (opt waitBulkInsertOpt) finished(state entity.BulkInsertState) bool {
+	switch state {
+	case entity.BulkInsertFailed, entity.BulkInsertFailedAndCleaned, entity.BulkInsertCompleted:
+		return true
+	case entity.BulkInsertPersisted:
+		return !opt.waitIndex
+	}
+	return false
+}
+
+func (opt waitBulkInsertOpt) emit(ctx context.Context, progress BulkInsertProgress) error {
+	if opt.progress != nil {
+		opt.progress(progress)
+	}
+	if opt.progressCh != nil {
+		select {
+		case opt.progressCh <- progress:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func newBulkInsertProgress(task *entity.BulkInsertTaskState, states []*entity.BulkInsertTaskState, opt waitBulkInsertOpt) BulkInsertProgress {
+	progress := BulkInsertProgress{Task: task, Total: len(states)}
+	var percent int
+	for _, state := range states {
+		if state == nil {
+			continue
+		}
+		switch {
+		case state.State == entity.BulkInsertFailed || state.State == entity.BulkInsertFailedAndCleaned:
+			progress.Finished++
+			progress.Failed++
+			percent += 100
+		case opt.finished(state.State):
+			progress.Finished++
+			percent += 100
+		default:
+			percent += state.Progress()
+		}
+	}
+	if len(states) > 0 {
+		progress.Percent = percent / len(states)
+	}
+	return progress
+}
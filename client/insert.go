@@ -92,6 +92,7 @@ func (c *GrpcClient) processInsertColumns(colSchema *entity.Schema, columns ...e
 	}
 	mNameColumn := make(map[string]entity.Column)
 	var dynamicColumns []entity.Column
+	var dynamicField entity.Column // json column of the whole dynamic field, e.g. queried by output field $meta
 	for _, column := range columns {
 		_, dup := mNameColumn[column.Name()]
 		if dup {
@@ -110,6 +111,13 @@ func (c *GrpcClient) processInsertColumns(colSchema *entity.Schema, columns ...e
 			if !isDynamic {
 				return nil, 0, fmt.Errorf("field %s does not exist in collection %s", column.Name(), colSchema.CollectionName)
 			}
+			if jsonColumn, ok := column.(*entity.ColumnJSONBytes); ok && jsonColumn.IsDynamic() {
+				if dynamicField != nil {
+					return nil, 0, fmt.Errorf("duplicated dynamic field column %s found", column.Name())
+				}
+				dynamicField = column
+				continue
+			}
 			// add to dynamic column list for further processing
 			dynamicColumns = append(dynamicColumns, column)
 			continue
@@ -123,8 +131,14 @@ func (c *GrpcClient) processInsertColumns(colSchema *entity.Schema, columns ...e
 			dim := 0
 			switch column := column.(type) {
 			case *entity.ColumnFloatVector:
+				if err := column.Err(); err != nil {
+					return nil, 0, err
+				}
 				dim = column.Dim()
 			case *entity.ColumnBinaryVector:
+				if err := column.Err(); err != nil {
+					return nil, 0, err
+				}
 				dim = column.Dim()
 			}
 			if fmt.Sprintf("%d", dim) != field.TypeParams[entity.TypeParamDim] {
@@ -146,6 +160,12 @@ func (c *GrpcClient) processInsertColumns(colSchema *entity.Schema, columns ...e
 	for _, fixedColumn := range mNameColumn {
 		fieldsData = append(fieldsData, fixedColumn.FieldData())
 	}
+	if dynamicField != nil {
+		if len(dynamicColumns) > 0 {
+			return nil, 0, errors.New("dynamic field column cannot be used with columns of dynamic keys")
+		}
+		fieldsData = append(fieldsData, dynamicField.FieldData())
+	}
 	if len(dynamicColumns) > 0 {
 		// use empty column name here
 		col, err := c.mergeDynamicColumns("", rowSize, dynamicColumns)
@@ -278,7 +298,10 @@ func (c *GrpcClient) DeleteByPks(ctx context.Context, collName string, partition
 		return errors.New("only delete by primary key is supported now")
 	}
 
-	expr := PKs2Expr(pkf.Name, ids)
+	expr, err := pks2Expr(pkf.Name, ids)
+	if err != nil {
+		return err
+	}
 
 	req := &milvuspb.DeleteRequest{
 		DbName:         "",
@@ -339,7 +362,8 @@ func (c *GrpcClient) Delete(ctx context.Context, collName string, partitionName
 
 // Upsert Index into collection with column-based format
 // collName is the collection name
-// partitionName is the partition to upsert, if not specified(empty), default partition will be used
+// partitionName is the partition to upsert, if not specified(empty), default partition will be used,
+// or the partition chosen by partition key for partition key collection
 // columns are slice of the column-based data
 func (c *GrpcClient) Upsert(ctx context.Context, collName string, partitionName string, columns ...entity.Column) (entity.Column, error) {
 	if c.Service == nil {
@@ -357,51 +381,15 @@ func (c *GrpcClient) Upsert(ctx context.Context, collName string, partitionName
 		}
 	}
 	// fields
-	var rowSize int
 	coll, err := c.DescribeCollection(ctx, collName)
 	if err != nil {
 		return nil, err
 	}
-	mNameField := make(map[string]*entity.Field)
-	for _, field := range coll.Schema.Fields {
-		mNameField[field.Name] = field
-	}
-	mNameColumn := make(map[string]entity.Column)
-	for _, column := range columns {
-		mNameColumn[column.Name()] = column
-		l := column.Len()
-		if rowSize == 0 {
-			rowSize = l
-		} else {
-			if rowSize != l {
-				return nil, errors.New("column size not match")
-			}
-		}
-		field, has := mNameField[column.Name()]
-		if !has {
-			return nil, fmt.Errorf("field %s does not exist in collection %s", column.Name(), collName)
-		}
-		if column.Type() != field.DataType {
-			return nil, fmt.Errorf("param column %s has type %v but collection field definition is %v", column.Name(), column.FieldData(), field.DataType)
-		}
-		if field.DataType == entity.FieldTypeFloatVector || field.DataType == entity.FieldTypeBinaryVector {
-			dim := 0
-			switch column := column.(type) {
-			case *entity.ColumnFloatVector:
-				dim = column.Dim()
-			case *entity.ColumnBinaryVector:
-				dim = column.Dim()
-			}
-			if fmt.Sprintf("%d", dim) != field.TypeParams[entity.TypeParamDim] {
-				return nil, fmt.Errorf("params column %s vector dim %d not match collection definition, which has dim of %s", field.Name, dim, field.TypeParams[entity.TypeParamDim])
-			}
-		}
-	}
-	for _, field := range coll.Schema.Fields {
-		_, has := mNameColumn[field.Name]
-		if !has && !field.AutoID {
-			return nil, fmt.Errorf("field %s not passed", field.Name)
-		}
+
+	// convert columns to field data
+	fieldsData, rowSize, err := c.processInsertColumns(coll.Schema, columns...)
+	if err != nil {
+		return nil, err
 	}
 
 	// 2. do upsert request
@@ -410,13 +398,12 @@ func (c *GrpcClient) Upsert(ctx context.Context, collName string, partitionName
 		CollectionName: collName,
 		PartitionName:  partitionName,
 	}
-	if req.PartitionName == "" {
+	// partitions of partition key collection are chosen by server
+	if req.PartitionName == "" && !hasPartitionKey(coll.Schema) {
 		req.PartitionName = "_default" // use default partition
 	}
 	req.NumRows = uint32(rowSize)
-	for _, column := range columns {
-		req.FieldsData = append(req.FieldsData, column.FieldData())
-	}
+	req.FieldsData = fieldsData
 	resp, err := c.Service.Upsert(ctx, req)
 	if err != nil {
 		return nil, err
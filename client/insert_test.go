@@ -305,3 +305,127 @@ func (s *InsertSuite) TestInsertSuccess() {
 func TestGrpcInsert(t *testing.T) {
 	suite.Run(t, new(InsertSuite))
 }
+
+type UpsertSuite struct {
+	MockSuiteBase
+}
+
+func (s *UpsertSuite) setupCollection(dynamic bool) {
+	s.setupHasCollection(testCollectionName)
+	s.setupHasPartition(testCollectionName, "partition_1")
+	s.setupDescribeCollection(testCollectionName, entity.NewSchema().
+		WithDynamicFieldEnabled(dynamic).
+		WithField(entity.NewField().WithIsPrimaryKey(true).WithName("ID").WithDataType(entity.FieldTypeInt64)).
+		WithField(entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithTypeParams(entity.TypeParamDim, "128")),
+	)
+}
+
+func (s *UpsertSuite) TestUpsertFail() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	vector := entity.NewColumnFloatVector("vector", 128, generateFloatVector(1, 128))
+	pk := entity.NewColumnInt64("ID", []int64{1})
+	meta := entity.NewColumnJSONBytes("$meta", [][]byte{[]byte(`{"a":1}`)}).WithIsDynamic(true)
+
+	for name, c := range map[string]struct {
+		dynamic bool
+		columns []entity.Column
+	}{
+		"field_not_exist":       {false, []entity.Column{pk, vector, entity.NewColumnInt64("extra", []int64{1})}},
+		"missing_field":         {false, []entity.Column{vector}},
+		"column_len_not_match":  {false, []entity.Column{entity.NewColumnInt64("ID", []int64{1, 2}), vector}},
+		"dim_not_match":         {false, []entity.Column{pk, entity.NewColumnFloatVector("vector", 8, generateFloatVector(1, 8))}},
+		"invalid_vector":        {false, []entity.Column{entity.NewColumnFloatVector("vector", 128, generateFloatVector(1, 8)), pk}},
+		"dynamic_not_enabled":   {false, []entity.Column{pk, vector, meta}},
+		"dynamic_field_and_key": {true, []entity.Column{pk, vector, meta, entity.NewColumnInt64("extra", []int64{1})}},
+	} {
+		s.Run(name, func() {
+			defer s.resetMock()
+			s.setupCollection(c.dynamic)
+
+			_, err := s.client.Upsert(ctx, testCollectionName, "partition_1", c.columns...)
+			s.Error(err)
+		})
+	}
+}
+
+func (s *UpsertSuite) TestUpsertSuccess() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	vector := entity.NewColumnFloatVector("vector", 128, generateFloatVector(1, 128))
+	pk := entity.NewColumnInt64("ID", []int64{1})
+
+	s.Run("dynamic_keys", func() {
+		defer s.resetMock()
+		s.setupCollection(true)
+		s.mock.EXPECT().Upsert(mock.Anything, mock.AnythingOfType("*milvuspb.UpsertRequest")).
+			Call.Return(func(_ context.Context, req *milvuspb.UpsertRequest) *milvuspb.MutationResult {
+			s.EqualValues(1, req.GetNumRows())
+			s.Equal("partition_1", req.GetPartitionName())
+			s.Require().Len(req.GetFieldsData(), 3)
+			dynamic := req.GetFieldsData()[2]
+			s.True(dynamic.GetIsDynamic())
+			s.JSONEq(`{"extra":1}`, string(dynamic.GetScalars().GetJsonData().GetData()[0]))
+			return &milvuspb.MutationResult{
+				Status: getSuccessStatus(),
+				IDs:    &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1}}}},
+			}
+		}, nil)
+
+		ids, err := s.client.Upsert(ctx, testCollectionName, "partition_1", pk, vector, entity.NewColumnInt64("extra", []int64{1}))
+		s.Require().NoError(err)
+		s.Equal(1, ids.Len())
+	})
+
+	s.Run("dynamic_field_column", func() {
+		defer s.resetMock()
+		s.setupCollection(true)
+		s.mock.EXPECT().Upsert(mock.Anything, mock.AnythingOfType("*milvuspb.UpsertRequest")).
+			Call.Return(func(_ context.Context, req *milvuspb.UpsertRequest) *milvuspb.MutationResult {
+			s.Equal("_default", req.GetPartitionName())
+			s.Require().Len(req.GetFieldsData(), 3)
+			// the dynamic field column is sent as is
+			dynamic := req.GetFieldsData()[2]
+			s.True(dynamic.GetIsDynamic())
+			s.Equal("$meta", dynamic.GetFieldName())
+			s.Equal(`{"a":1}`, string(dynamic.GetScalars().GetJsonData().GetData()[0]))
+			return &milvuspb.MutationResult{
+				Status: getSuccessStatus(),
+				IDs:    &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1}}}},
+			}
+		}, nil)
+
+		meta := entity.NewColumnJSONBytes("$meta", [][]byte{[]byte(`{"a":1}`)}).WithIsDynamic(true)
+		ids, err := s.client.Upsert(ctx, testCollectionName, "", pk, vector, meta)
+		s.Require().NoError(err)
+		s.Equal(1, ids.Len())
+	})
+
+	s.Run("partition_key", func() {
+		defer s.resetMock()
+		s.setupHasCollection(testCollectionName)
+		s.setupDescribeCollection(testCollectionName, entity.NewSchema().
+			WithField(entity.NewField().WithIsPrimaryKey(true).WithName("ID").WithDataType(entity.FieldTypeInt64)).
+			WithField(entity.NewField().WithName("tenant").WithDataType(entity.FieldTypeInt64).WithIsPartitionKey(true)).
+			WithField(entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithTypeParams(entity.TypeParamDim, "128")),
+		)
+		s.mock.EXPECT().Upsert(mock.Anything, mock.AnythingOfType("*milvuspb.UpsertRequest")).
+			Call.Return(func(_ context.Context, req *milvuspb.UpsertRequest) *milvuspb.MutationResult {
+			// partition is chosen by server
+			s.Equal("", req.GetPartitionName())
+			return &milvuspb.MutationResult{
+				Status: getSuccessStatus(),
+				IDs:    &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1}}}},
+			}
+		}, nil)
+
+		_, err := s.client.Upsert(ctx, testCollectionName, "", pk, vector, entity.NewColumnInt64("tenant", []int64{1}))
+		s.Require().NoError(err)
+	})
+}
+
+func TestGrpcUpsert(t *testing.T) {
+	suite.Run(t, new(UpsertSuite))
+}
@@ -0,0 +1,266 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/protobuf/proto"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/milvus-io/milvus-sdk-go/v2/expr"
+)
+
+const (
+	defaultIteratorBatchSize = 1000
+	// maxIteratorBatchSize is the max query window of server
+	maxIteratorBatchSize    = 16384
+	defaultIteratorMaxBytes = 4 * 1024 * 1024
+)
+
+// ErrIteratorClosed is returned by Next of closed iterator.
+var ErrIteratorClosed = errors.New("iterator closed")
+
+// IteratorOption is option for QueryIterator and SearchIterator.
+type IteratorOption func(opt *iteratorOpt)
+
+type iteratorOpt struct {
+	partitions         []string
+	batchSize          int
+	maxBytes           int64
+	limit              int64
+	guaranteeTimestamp uint64
+}
+
+// WithIteratorPartitions sets the partitions to iterate, all partitions by default.
+func WithIteratorPartitions(partitionNames ...string) IteratorOption {
+	return func(opt *iteratorOpt) {
+		opt.partitions = partitionNames
+	}
+}
+
+// WithIteratorBatchSize sets the initial row count of each batch, 1000 by default.
+func WithIteratorBatchSize(batchSize int) IteratorOption {
+	return func(opt *iteratorOpt) {
+		opt.batchSize = batchSize
+	}
+}
+
+// WithIteratorMaxBytes sets the target response size of each batch, 4MB by default.
+// The batch size is adapted according to the size of previous responses, only used by QueryIterator.
+func WithIteratorMaxBytes(size int64) IteratorOption {
+	return func(opt *iteratorOpt) {
+		opt.maxBytes = size
+	}
+}
+
+// WithIteratorLimit limits the total count of rows returned by the iterator.
+func WithIteratorLimit(limit int64) IteratorOption {
+	return func(opt *iteratorOpt) {
+		opt.limit = limit
+	}
+}
+
+// WithIteratorGuaranteeTimestamp pins the guarantee timestamp of all requests,
+// a timestamp allocated by server when the iterator is created is used by default.
+func WithIteratorGuaranteeTimestamp(ts uint64) IteratorOption {
+	return func(opt *iteratorOpt) {
+		opt.guaranteeTimestamp = ts
+	}
+}
+
+func makeIteratorOpt(opts ...IteratorOption) iteratorOpt {
+	opt := iteratorOpt{
+		batchSize: defaultIteratorBatchSize,
+		maxBytes:  defaultIteratorMaxBytes,
+	}
+	for _, o := range opts {
+		o(&opt)
+	}
+	if opt.batchSize <= 0 {
+		opt.batchSize = defaultIteratorBatchSize
+	}
+	if opt.batchSize > maxIteratorBatchSize {
+		opt.batchSize = maxIteratorBatchSize
+	}
+	return opt
+}
+
+// iteratorTimestamp returns the guarantee timestamp shared by all requests of an iterator.
+// Servers without AllocTimestamp fall back to the session timestamp of the collection,
+// so that rows written by this client are still visible.
+func (c *GrpcClient) iteratorTimestamp(ctx context.Context, collName string, opt iteratorOpt) (uint64, error) {
+	if opt.guaranteeTimestamp != 0 {
+		return opt.guaranteeTimestamp, nil
+	}
+	ts, err := c.allocTimestamp(ctx)
+	if status.Code(err) == codes.Unimplemented {
+		if ts, ok := MetaCache.getSessionTs(collName); ok {
+			return ts, nil
+		}
+		return EventuallyTimestamp, nil
+	}
+	return ts, err
+}
+
+// allocTimestamp returns a timestamp allocated by server.
+func (c *GrpcClient) allocTimestamp(ctx context.Context) (uint64, error) {
+	resp, err := c.Service.AllocTimestamp(ctx, &milvuspb.AllocTimestampRequest{})
+	if err != nil {
+		return 0, err
+	}
+	if err := handleRespStatus(resp.GetStatus()); err != nil {
+		return 0, err
+	}
+	return resp.GetTimestamp(), nil
+}
+
+// QueryIterator pages through query results by primary key ranges,
+// each batch queries `expr and pk > last` and sorts the results by primary key.
+type QueryIterator struct {
+	client       Client
+	collName     string
+	expr         string
+	outputFields []string
+	pkName       string
+	opt          iteratorOpt
+
+	batchSize int
+	returned  int64
+	last      interface{} // primary key of the last returned row
+	done      bool
+	closed    bool
+}
+
+// QueryIterator returns an iterator over all rows matching the expression, ordered by primary key.
+// Requests are sent with a pinned guarantee timestamp, so that rows inserted after the iterator is created
+// are not guaranteed to be visible.
+func (c *GrpcClient) QueryIterator(ctx context.Context, collName string, expr string, outputFields []string, opts ...IteratorOption) (*QueryIterator, error) {
+	if c.Service == nil {
+		return nil, ErrClientNotReady
+	}
+	coll, err := c.DescribeCollection(ctx, collName)
+	if err != nil {
+		return nil, err
+	}
+	pk := getPKField(coll.Schema)
+	if pk == nil {
+		return nil, fmt.Errorf("collection %s has no primary key", collName)
+	}
+	opt := makeIteratorOpt(opts...)
+	if opt.guaranteeTimestamp, err = c.iteratorTimestamp(ctx, collName, opt); err != nil {
+		return nil, err
+	}
+	return &QueryIterator{
+		client:       c,
+		collName:     collName,
+		expr:         expr,
+		outputFields: outputFields,
+		pkName:       pk.Name,
+		opt:          opt,
+		batchSize:    opt.batchSize,
+	}, nil
+}
+
+// Next returns the next batch of rows, or io.EOF when all rows are returned.
+func (it *QueryIterator) Next(ctx context.Context) (ResultSet, error) {
+	if it.closed {
+		return nil, ErrIteratorClosed
+	}
+	if it.done {
+		return nil, io.EOF
+	}
+	batchSize := int64(it.batchSize)
+	if it.opt.limit > 0 && it.opt.limit-it.returned < batchSize {
+		batchSize = it.opt.limit - it.returned
+	}
+
+	filter, err := it.filter()
+	if err != nil {
+		return nil, err
+	}
+	rs, err := it.client.Query(ctx, it.collName, it.opt.partitions, filter, it.outputFields,
+		WithSearchQueryConsistencyLevel(entity.ClCustomized),
+		WithGuaranteeTimestamp(it.opt.guaranteeTimestamp),
+		WithLimit(batchSize))
+	if err != nil {
+		return nil, err
+	}
+	// server returns the rows with the smallest primary keys, but not necessarily in order
+	if rs, err = rs.SortBy(it.pkName); err != nil {
+		return nil, err
+	}
+
+	n := rs.Len()
+	it.returned += int64(n)
+	if int64(n) < batchSize || (it.opt.limit > 0 && it.returned >= it.opt.limit) {
+		it.done = true
+	}
+	if n == 0 {
+		return nil, io.EOF
+	}
+	if it.last, err = rs.GetColumn(it.pkName).Get(n - 1); err != nil {
+		return nil, err
+	}
+	it.batchSize = adaptBatchSize(it.batchSize, rs, it.opt.maxBytes)
+	return rs, nil
+}
+
+// Close closes the iterator, Next returns ErrIteratorClosed afterwards.
+func (it *QueryIterator) Close() error {
+	it.closed = true
+	return nil
+}
+
+// filter returns the expression of the next batch.
+func (it *QueryIterator) filter() (string, error) {
+	if it.last == nil {
+		return it.expr, nil
+	}
+	next := expr.Field(it.pkName).Gt(it.last)
+	if it.expr != "" {
+		next = expr.Raw(it.expr).And(next)
+	}
+	return next.Build()
+}
+
+// adaptBatchSize returns the row count of next batch, making response size close to maxBytes.
+func adaptBatchSize(batchSize int, rs ResultSet, maxBytes int64) int {
+	n := rs.Len()
+	if maxBytes <= 0 || n == 0 {
+		return batchSize
+	}
+	var size int64
+	for _, column := range rs {
+		if fd := column.FieldData(); fd != nil {
+			size += int64(proto.Size(fd))
+		}
+	}
+	rowSize := size / int64(n)
+	if rowSize <= 0 {
+		return batchSize
+	}
+	next := int(maxBytes / rowSize)
+	switch {
+	case next < 1:
+		next = 1
+	case next > maxIteratorBatchSize:
+		next = maxIteratorBatchSize
+	}
+	return next
+}
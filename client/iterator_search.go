@@ -0,0 +1,276 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"io"
+	"math"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+const (
+	radiusKey      = "radius"
+	rangeFilterKey = "range_filter"
+
+	// maxSearchIteratorExpand is the times a distance band is widened before searching without outer bound
+	maxSearchIteratorExpand = 8
+)
+
+// SearchIterator pages through search results of a single vector ordered by similarity,
+// each batch range searches the distance band next to the last returned result.
+type SearchIterator struct {
+	client       Client
+	collName     string
+	expr         string
+	outputFields []string
+	vector       entity.Vector
+	vectorField  string
+	metricType   entity.MetricType
+	params       map[string]interface{}
+	opt          iteratorOpt
+
+	returned int64
+	started  bool
+	last     float32              // score of the last returned result
+	ties     map[interface{}]bool // ids returned with score equal to last
+	width    float64              // width of the distance band to search next
+	done     bool
+	closed   bool
+}
+
+// SearchIterator returns an iterator over search results of vector, ordered from the most similar.
+// Requests are sent with a pinned guarantee timestamp, radius and range_filter in sp are overwritten.
+func (c *GrpcClient) SearchIterator(ctx context.Context, collName string, expr string, outputFields []string,
+	vector entity.Vector, vectorField string, metricType entity.MetricType, sp entity.SearchParam, opts ...IteratorOption) (*SearchIterator, error) {
+	if c.Service == nil {
+		return nil, ErrClientNotReady
+	}
+	if vector == nil {
+		return nil, errors.New("search vector cannot be nil")
+	}
+	if sp == nil {
+		return nil, errors.New("search param cannot be nil")
+	}
+	if metricType == "" {
+		return nil, errors.New("metric type must be provided to determine search direction")
+	}
+	params := sp.Params()
+	delete(params, radiusKey)
+	delete(params, rangeFilterKey)
+	opt := makeIteratorOpt(opts...)
+	ts, err := c.iteratorTimestamp(ctx, collName, opt)
+	if err != nil {
+		return nil, err
+	}
+	opt.guaranteeTimestamp = ts
+	return &SearchIterator{
+		client:       c,
+		collName:     collName,
+		expr:         expr,
+		outputFields: outputFields,
+		vector:       vector,
+		vectorField:  vectorField,
+		metricType:   metricType,
+		params:       params,
+		opt:          opt,
+		ties:         make(map[interface{}]bool),
+	}, nil
+}
+
+// Next returns the next batch of results, or io.EOF when all results are returned.
+func (it *SearchIterator) Next(ctx context.Context) (SearchResult, error) {
+	if it.closed {
+		return SearchResult{}, ErrIteratorClosed
+	}
+	if it.done {
+		return SearchResult{}, io.EOF
+	}
+	batchSize := int64(it.opt.batchSize)
+	if it.opt.limit > 0 && it.opt.limit-it.returned < batchSize {
+		batchSize = it.opt.limit - it.returned
+	}
+
+	var (
+		result  SearchResult
+		indices []int
+		err     error
+	)
+	for expand := 0; ; expand++ {
+		// search without outer bound at last, so that all the remaining results are reachable
+		bounded := it.started && expand < maxSearchIteratorExpand
+		result, indices, err = it.search(ctx, int(batchSize), bounded)
+		if err != nil {
+			return SearchResult{}, err
+		}
+		if int64(len(indices)) >= batchSize || !bounded {
+			break
+		}
+		it.width *= 2
+	}
+	if int64(len(indices)) > batchSize {
+		indices = indices[:batchSize]
+	}
+	if int64(len(indices)) < batchSize {
+		it.done = true
+	}
+	if len(indices) == 0 {
+		return SearchResult{}, io.EOF
+	}
+
+	page, err := it.page(result, indices)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	it.returned += int64(page.ResultCount)
+	if it.opt.limit > 0 && it.returned >= it.opt.limit {
+		it.done = true
+	}
+	it.advance(page)
+	return page, nil
+}
+
+// Close closes the iterator, Next returns ErrIteratorClosed afterwards.
+func (it *SearchIterator) Close() error {
+	it.closed = true
+	return nil
+}
+
+// search sends the request of next band and returns the indices of results not returned before.
+func (it *SearchIterator) search(ctx context.Context, batchSize int, bounded bool) (SearchResult, []int, error) {
+	sp := &iteratorSearchParam{params: make(map[string]interface{}, len(it.params)+2)}
+	for k, v := range it.params {
+		sp.params[k] = v
+	}
+	if it.started {
+		sp.AddRangeFilter(float64(it.last))
+		if bounded {
+			sp.AddRadius(float64(it.last) + it.direction()*it.width)
+		} else {
+			sp.AddRadius(it.direction() * math.MaxFloat32)
+		}
+	}
+	// results tied with last are returned again since range filter is inclusive
+	topK := batchSize + len(it.ties)
+	if topK > maxIteratorBatchSize {
+		topK = maxIteratorBatchSize
+	}
+	results, err := it.client.Search(ctx, it.collName, it.opt.partitions, it.expr, it.outputFields,
+		[]entity.Vector{it.vector}, it.vectorField, it.metricType, topK, sp,
+		WithSearchQueryConsistencyLevel(entity.ClCustomized),
+		WithGuaranteeTimestamp(it.opt.guaranteeTimestamp))
+	if err != nil {
+		return SearchResult{}, nil, err
+	}
+	if len(results) == 0 {
+		return SearchResult{}, nil, nil
+	}
+	result := results[0]
+	if result.Err != nil {
+		return SearchResult{}, nil, result.Err
+	}
+	indices := make([]int, 0, result.ResultCount)
+	for i := 0; i < result.ResultCount; i++ {
+		id, err := result.IDs.Get(i)
+		if err != nil {
+			return SearchResult{}, nil, err
+		}
+		if it.ties[id] {
+			continue
+		}
+		indices = append(indices, i)
+	}
+	return result, indices, nil
+}
+
+// page takes results of indices.
+func (it *SearchIterator) page(result SearchResult, indices []int) (SearchResult, error) {
+	ids, err := takeColumn(result.IDs, indices)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	fields, err := result.Fields.take(indices)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	scores := make([]float32, 0, len(indices))
+	for _, idx := range indices {
+		scores = append(scores, result.Scores[idx])
+	}
+	return SearchResult{
+		ResultCount: len(indices),
+		IDs:         ids,
+		Fields:      fields,
+		Scores:      scores,
+	}, nil
+}
+
+// advance moves the band next to the last result of page.
+func (it *SearchIterator) advance(page SearchResult) {
+	first, last := page.Scores[0], page.Scores[page.ResultCount-1]
+	if !it.started || last != it.last {
+		it.ties = make(map[interface{}]bool)
+	}
+	for i := page.ResultCount - 1; i >= 0 && page.Scores[i] == last; i-- {
+		id, _ := page.IDs.Get(i)
+		it.ties[id] = true
+	}
+	// the next band is expected to contain about the same count of results as this page
+	width := math.Abs(float64(last - first))
+	if width == 0 {
+		width = math.Max(math.Abs(float64(last)), 1) * 0.01
+	}
+	it.width = width
+	it.last = last
+	it.started = true
+}
+
+// direction returns 1 if greater distance means less similar, -1 otherwise.
+func (it *SearchIterator) direction() float64 {
+	if metricLargerIsSimilar(it.metricType) {
+		return -1
+	}
+	return 1
+}
+
+// metricLargerIsSimilar reports whether greater scores of metric mean more similar.
+func metricLargerIsSimilar(metricType entity.MetricType) bool {
+	switch metricType {
+	case entity.IP, entity.COSINE:
+		return true
+	}
+	return false
+}
+
+// iteratorSearchParam is the search param with band bounds set by SearchIterator.
+type iteratorSearchParam struct {
+	params map[string]interface{}
+}
+
+func (sp *iteratorSearchParam) Params() map[string]interface{} {
+	params := make(map[string]interface{}, len(sp.params))
+	for k, v := range sp.params {
+		params[k] = v
+	}
+	return params
+}
+
+func (sp *iteratorSearchParam) AddRadius(radius float64) {
+	sp.params[radiusKey] = radius
+}
+
+func (sp *iteratorSearchParam) AddRangeFilter(rangeFilter float64) {
+	sp.params[rangeFilterKey] = rangeFilter
+}
@@ -0,0 +1,417 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/milvus-io/milvus-sdk-go/v2/expr"
+)
+
+type QueryIteratorSuite struct {
+	MockSuiteBase
+	sch *entity.Schema
+}
+
+func (s *QueryIteratorSuite) SetupSuite() {
+	s.MockSuiteBase.SetupSuite()
+
+	s.sch = entity.NewSchema().WithName(testCollectionName).
+		WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true)).
+		WithField(entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithDim(2))
+}
+
+// setupQuery mocks Query over rows with primary keys 1 to n, returning the smallest rows matching expr
+// in reverse order, requests are recorded.
+func (s *QueryIteratorSuite) setupQuery(n int64, requests *[]*milvuspb.QueryRequest) {
+	s.mock.EXPECT().Query(mock.Anything, mock.AnythingOfType("*milvuspb.QueryRequest")).
+		Call.Return(func(_ context.Context, req *milvuspb.QueryRequest) *milvuspb.QueryResults {
+		*requests = append(*requests, req)
+		var limit int64
+		for _, kv := range req.GetQueryParams() {
+			if kv.GetKey() == limitKey {
+				limit, _ = strconv.ParseInt(kv.GetValue(), 10, 64)
+			}
+		}
+		var node expr.Node
+		if req.GetExpr() != "" {
+			var err error
+			node, err = expr.Parse(req.GetExpr())
+			s.Require().NoError(err)
+		}
+		var ids []int64
+		for id := int64(1); id <= n && int64(len(ids)) < limit; id++ {
+			if node != nil {
+				ok, err := expr.Evaluate(node, map[string]interface{}{"id": id})
+				s.Require().NoError(err)
+				if !ok {
+					continue
+				}
+			}
+			ids = append(ids, id)
+		}
+		for i, j := 0, len(ids)-1; i < j; i, j = i+1, j-1 {
+			ids[i], ids[j] = ids[j], ids[i]
+		}
+		return &milvuspb.QueryResults{
+			Status:     getSuccessStatus(),
+			FieldsData: []*schemapb.FieldData{s.getInt64FieldData("id", ids)},
+		}
+	}, nil)
+}
+
+func (s *QueryIteratorSuite) collect(it *QueryIterator) ([]int64, []int) {
+	ctx := context.Background()
+	var ids []int64
+	var batches []int
+	for {
+		rs, err := it.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		s.Require().NoError(err)
+		batches = append(batches, rs.Len())
+		ids = append(ids, rs.GetColumn("id").(*entity.ColumnInt64).Data()...)
+	}
+	return ids, batches
+}
+
+func (s *QueryIteratorSuite) TestIterate() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.Run("normal_case", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupAllocTimestamp(1000)
+		var requests []*milvuspb.QueryRequest
+		s.setupQuery(10, &requests)
+
+		it, err := s.client.QueryIterator(ctx, testCollectionName, "id != 5", []string{"id"}, WithIteratorBatchSize(4))
+		s.Require().NoError(err)
+		ids, batches := s.collect(it)
+		s.Equal([]int64{1, 2, 3, 4, 6, 7, 8, 9, 10}, ids)
+		// batch size grows after the first page, since rows are small
+		s.Equal([]int{4, 5}, batches)
+
+		s.Require().Len(requests, 2)
+		s.Equal("id != 5", requests[0].GetExpr())
+		s.Equal("(id != 5) and id > 4", requests[1].GetExpr())
+		for _, req := range requests {
+			s.EqualValues(1000, req.GetGuaranteeTimestamp())
+		}
+
+		s.NoError(it.Close())
+		_, err = it.Next(ctx)
+		s.ErrorIs(err, ErrIteratorClosed)
+	})
+
+	s.Run("limit_and_adapt", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		var requests []*milvuspb.QueryRequest
+		s.setupQuery(100, &requests)
+
+		// each row takes a few bytes in response, batches shrink to fit max bytes
+		it, err := s.client.QueryIterator(ctx, testCollectionName, "", nil,
+			WithIteratorBatchSize(10), WithIteratorMaxBytes(16), WithIteratorLimit(25), WithIteratorGuaranteeTimestamp(100))
+		s.Require().NoError(err)
+		ids, batches := s.collect(it)
+		s.Len(ids, 25)
+		s.EqualValues(25, ids[24])
+		s.Equal(10, batches[0])
+		s.Less(batches[1], 10)
+		s.EqualValues(100, requests[0].GetGuaranteeTimestamp())
+		s.Equal("", requests[0].GetExpr())
+	})
+
+	s.Run("empty_result", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupAllocTimestamp(1000)
+		var requests []*milvuspb.QueryRequest
+		s.setupQuery(0, &requests)
+
+		it, err := s.client.QueryIterator(ctx, testCollectionName, "", nil)
+		s.Require().NoError(err)
+		_, err = it.Next(ctx)
+		s.ErrorIs(err, io.EOF)
+		_, err = it.Next(ctx)
+		s.ErrorIs(err, io.EOF)
+		s.Len(requests, 1)
+	})
+
+	s.Run("query_fail", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupAllocTimestamp(1000)
+		s.mock.EXPECT().Query(mock.Anything, mock.AnythingOfType("*milvuspb.QueryRequest")).
+			Return(&milvuspb.QueryResults{Status: &commonpb.Status{ErrorCode: commonpb.ErrorCode_UnexpectedError}}, nil)
+
+		it, err := s.client.QueryIterator(ctx, testCollectionName, "", nil)
+		s.Require().NoError(err)
+		_, err = it.Next(ctx)
+		s.Error(err)
+	})
+
+	s.Run("describe_fail", func() {
+		defer s.resetMock()
+		s.setupDescribeCollectionError(commonpb.ErrorCode_UnexpectedError, errors.New("mock"))
+		_, err := s.client.QueryIterator(ctx, testCollectionName, "", nil)
+		s.Error(err)
+	})
+
+	s.Run("alloc_timestamp_fail", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.mock.EXPECT().AllocTimestamp(mock.Anything, mock.AnythingOfType("*milvuspb.AllocTimestampRequest")).
+			Return(&milvuspb.AllocTimestampResponse{Status: &commonpb.Status{ErrorCode: commonpb.ErrorCode_UnexpectedError}}, nil)
+		_, err := s.client.QueryIterator(ctx, testCollectionName, "", nil)
+		s.Error(err)
+	})
+
+	s.Run("alloc_timestamp_unimplemented", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.mock.EXPECT().AllocTimestamp(mock.Anything, mock.AnythingOfType("*milvuspb.AllocTimestampRequest")).
+			Return(nil, status.Error(codes.Unimplemented, "mock"))
+		var requests []*milvuspb.QueryRequest
+		s.setupQuery(3, &requests)
+		MetaCache.setSessionTs(testCollectionName, 500)
+
+		it, err := s.client.QueryIterator(ctx, testCollectionName, "", nil)
+		s.Require().NoError(err)
+		_, err = it.Next(ctx)
+		s.Require().NoError(err)
+		// falls back to the session timestamp, rows written by this client are visible
+		s.Require().Len(requests, 1)
+		s.GreaterOrEqual(requests[0].GetGuaranteeTimestamp(), uint64(500))
+	})
+}
+
+func TestQueryIterator(t *testing.T) {
+	suite.Run(t, new(QueryIteratorSuite))
+}
+
+type SearchIteratorSuite struct {
+	MockSuiteBase
+	sch *entity.Schema
+	sp  entity.SearchParam
+}
+
+func (s *SearchIteratorSuite) SetupSuite() {
+	s.MockSuiteBase.SetupSuite()
+
+	s.sch = entity.NewSchema().WithName(testCollectionName).
+		WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true)).
+		WithField(entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithDim(2))
+	sp, err := entity.NewIndexFlatSearchParam()
+	s.Require().NoError(err)
+	s.sp = sp
+}
+
+// setupSearch mocks range search over ids 1 to len(scores), where scores[i] is the score of id i+1.
+func (s *SearchIteratorSuite) setupSearch(scores []float32, largerIsSimilar bool, requests *[]map[string]interface{}) {
+	s.mock.EXPECT().Search(mock.Anything, mock.AnythingOfType("*milvuspb.SearchRequest")).
+		Call.Return(func(_ context.Context, req *milvuspb.SearchRequest) *milvuspb.SearchResults {
+		var topK int
+		params := make(map[string]interface{})
+		for _, kv := range req.GetSearchParams() {
+			switch kv.GetKey() {
+			case "topk":
+				topK, _ = strconv.Atoi(kv.GetValue())
+			case "params":
+				s.Require().NoError(json.Unmarshal([]byte(kv.GetValue()), &params))
+			}
+		}
+		params["guarantee_timestamp"] = req.GetGuaranteeTimestamp()
+		*requests = append(*requests, params)
+
+		inRange := func(score float64) bool {
+			radius, ok := params["radius"].(float64)
+			if !ok {
+				return true
+			}
+			rangeFilter := params["range_filter"].(float64)
+			if largerIsSimilar {
+				return score > radius && score <= rangeFilter
+			}
+			return score >= rangeFilter && score < radius
+		}
+		var ids []int64
+		for i, score := range scores {
+			if inRange(float64(score)) {
+				ids = append(ids, int64(i+1))
+			}
+		}
+		sort.SliceStable(ids, func(i, j int) bool {
+			if largerIsSimilar {
+				return scores[ids[i]-1] > scores[ids[j]-1]
+			}
+			return scores[ids[i]-1] < scores[ids[j]-1]
+		})
+		if len(ids) > topK {
+			ids = ids[:topK]
+		}
+		result := make([]float32, 0, len(ids))
+		for _, id := range ids {
+			result = append(result, scores[id-1])
+		}
+		return &milvuspb.SearchResults{
+			Status: getSuccessStatus(),
+			Results: &schemapb.SearchResultData{
+				NumQueries: 1,
+				TopK:       int64(topK),
+				FieldsData: []*schemapb.FieldData{s.getInt64FieldData("id", ids)},
+				Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids}}},
+				Scores:     result,
+				Topks:      []int64{int64(len(ids))},
+			},
+		}
+	}, nil)
+}
+
+func (s *SearchIteratorSuite) collect(it *SearchIterator) ([]int64, []float32) {
+	ctx := context.Background()
+	var ids []int64
+	var scores []float32
+	for {
+		result, err := it.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		s.Require().NoError(err)
+		s.Require().Equal(result.ResultCount, result.IDs.Len())
+		ids = append(ids, result.IDs.(*entity.ColumnInt64).Data()...)
+		scores = append(scores, result.Scores...)
+		s.Equal(result.IDs.(*entity.ColumnInt64).Data(), result.Fields.GetColumn("id").(*entity.ColumnInt64).Data())
+	}
+	return ids, scores
+}
+
+func (s *SearchIteratorSuite) TestIterate() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	vector := entity.FloatVector([]float32{0.1, 0.2})
+
+	s.Run("l2_with_ties", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupAllocTimestamp(1000)
+		scores := make([]float32, 20)
+		expected := make([]int64, 0, 20)
+		for i := range scores {
+			// every two ids share the same score
+			scores[i] = float32(i/2 + 1)
+			expected = append(expected, int64(i+1))
+		}
+		var requests []map[string]interface{}
+		s.setupSearch(scores, false, &requests)
+
+		it, err := s.client.SearchIterator(ctx, testCollectionName, "", []string{"id"}, vector, "vector", entity.L2, s.sp,
+			WithIteratorBatchSize(3))
+		s.Require().NoError(err)
+		ids, result := s.collect(it)
+		s.Equal(expected, ids)
+		s.True(sort.SliceIsSorted(result, func(i, j int) bool { return result[i] < result[j] }))
+
+		s.NotContains(requests[0], "radius")
+		s.EqualValues(1000, requests[0]["guarantee_timestamp"])
+		for _, req := range requests[1:] {
+			s.Less(req["range_filter"], req["radius"])
+			s.EqualValues(1000, req["guarantee_timestamp"])
+		}
+
+		s.NoError(it.Close())
+		_, err = it.Next(ctx)
+		s.ErrorIs(err, ErrIteratorClosed)
+	})
+
+	s.Run("ip_with_limit", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		scores := make([]float32, 50)
+		for i := range scores {
+			scores[i] = 1 - float32(i)*0.01
+		}
+		var requests []map[string]interface{}
+		s.setupSearch(scores, true, &requests)
+
+		it, err := s.client.SearchIterator(ctx, testCollectionName, "", []string{"id"}, vector, "vector", entity.IP, s.sp,
+			WithIteratorBatchSize(4), WithIteratorLimit(10), WithIteratorGuaranteeTimestamp(100))
+		s.Require().NoError(err)
+		ids, _ := s.collect(it)
+		s.Equal([]int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, ids)
+		for _, req := range requests[1:] {
+			s.Greater(req["range_filter"], req["radius"])
+			s.EqualValues(100, req["guarantee_timestamp"])
+		}
+	})
+
+	s.Run("sparse_scores", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupAllocTimestamp(1000)
+		var requests []map[string]interface{}
+		s.setupSearch([]float32{1, 2, 1e6}, false, &requests)
+
+		it, err := s.client.SearchIterator(ctx, testCollectionName, "", []string{"id"}, vector, "vector", entity.L2, s.sp,
+			WithIteratorBatchSize(2))
+		s.Require().NoError(err)
+		ids, _ := s.collect(it)
+		s.Equal([]int64{1, 2, 3}, ids)
+		// bands are widened before searching without outer bound
+		s.Equal(float64(math.MaxFloat32), requests[len(requests)-1]["radius"])
+	})
+
+	s.Run("search_fail", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupAllocTimestamp(1000)
+		s.mock.EXPECT().Search(mock.Anything, mock.AnythingOfType("*milvuspb.SearchRequest")).
+			Return(&milvuspb.SearchResults{Status: &commonpb.Status{ErrorCode: commonpb.ErrorCode_UnexpectedError}}, nil)
+
+		it, err := s.client.SearchIterator(ctx, testCollectionName, "", nil, vector, "vector", entity.L2, s.sp)
+		s.Require().NoError(err)
+		_, err = it.Next(ctx)
+		s.Error(err)
+	})
+
+	s.Run("bad_args", func() {
+		_, err := s.client.SearchIterator(ctx, testCollectionName, "", nil, nil, "vector", entity.L2, s.sp)
+		s.Error(err)
+		_, err = s.client.SearchIterator(ctx, testCollectionName, "", nil, vector, "vector", entity.L2, nil)
+		s.Error(err)
+		_, err = s.client.SearchIterator(ctx, testCollectionName, "", nil, vector, "vector", "", s.sp)
+		s.Error(err)
+	})
+}
+
+func TestSearchIterator(t *testing.T) {
+	suite.Run(t, new(SearchIteratorSuite))
+}
@@ -0,0 +1,202 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+// SearchSource is one collection searched by MultiSearch, with its own partitions and filter.
+type SearchSource struct {
+	Collection string
+	Partitions []string
+	Expr       string
+}
+
+// MultiSearchResult is the merged hits of one query of MultiSearch.
+type MultiSearchResult struct {
+	SearchResult
+	Collections  []string     // source collection of each hit
+	SourceErrors SourceErrors // sources whose hits of the query are missing, nil if all sources succeeded
+}
+
+// SourceErrors is the errors of sources failed in MultiSearch, keyed by the index of source.
+type SourceErrors map[int]error
+
+// Error implements error.
+func (e SourceErrors) Error() string {
+	indices := make([]int, 0, len(e))
+	for idx := range e {
+		indices = append(indices, idx)
+	}
+	sort.Ints(indices)
+	msgs := make([]string, 0, len(indices))
+	for _, idx := range indices {
+		msgs = append(msgs, fmt.Sprintf("source %d: %s", idx, e[idx].Error()))
+	}
+	return fmt.Sprintf("%d sources failed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// MultiSearch searches the vectors in all sources in parallel, then merges hits of each query into global topK hits
+// ordered by the metric type, labeled with their source collections. Offset is applied to the merged hits.
+// Sources shall have the same vector field and output fields.
+// When metricType is empty, it is taken from the indexes of sources, which shall have the same metric type.
+//
+// Sources failed are skipped, each result merges the hits of the other sources and reports the failed ones
+// in its SourceErrors, so partial results shall be checked with SourceErrors rather than the returned error.
+// Err of a result is set only if all sources fail for the query, and SourceErrors is returned as error
+// only if all sources fail.
+func (c *GrpcClient) MultiSearch(ctx context.Context, sources []SearchSource, outputFields []string, vectors []entity.Vector,
+	vectorField string, metricType entity.MetricType, topK int, sp entity.SearchParam, opts ...SearchQueryOptionFunc) ([]MultiSearchResult, error) {
+	if c.Service == nil {
+		return nil, ErrClientNotReady
+	}
+	if len(sources) == 0 {
+		return nil, errors.New("no search source provided")
+	}
+	if topK <= 0 {
+		return nil, errors.New("topK must be positive")
+	}
+	if metricType == "" {
+		var err error
+		if metricType, err = c.sourcesMetricType(ctx, sources, vectorField); err != nil {
+			return nil, err
+		}
+	}
+	option := &SearchQueryOption{}
+	for _, o := range opts {
+		o(option)
+	}
+	offset := int(option.Offset)
+	// each source returns offset+topK hits, from which merged hits are selected
+	opts = append(opts[:len(opts):len(opts)], WithOffset(0))
+
+	results := make([][]SearchResult, len(sources))
+	errs := make([]error, len(sources))
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		wg.Add(1)
+		go func(i int, source SearchSource) {
+			defer wg.Done()
+			results[i], errs[i] = c.Search(ctx, source.Collection, source.Partitions, source.Expr, outputFields,
+				vectors, vectorField, metricType, offset+topK, sp, opts...)
+			if errs[i] == nil && len(results[i]) != len(vectors) {
+				errs[i] = fmt.Errorf("expected %d results, got %d", len(vectors), len(results[i]))
+			}
+		}(i, source)
+	}
+	wg.Wait()
+	var sourceErrs SourceErrors
+	for i, err := range errs {
+		if err != nil {
+			if sourceErrs == nil {
+				sourceErrs = make(SourceErrors)
+			}
+			sourceErrs[i] = fmt.Errorf("search collection %s failed: %w", sources[i].Collection, err)
+		}
+	}
+	if len(sourceErrs) == len(sources) {
+		return nil, sourceErrs
+	}
+
+	merged := make([]MultiSearchResult, 0, len(vectors))
+	for q := range vectors {
+		merged = append(merged, mergeSearchResults(sources, results, sourceErrs, q, metricType, offset, topK))
+	}
+	return merged, nil
+}
+
+// sourcesMetricType returns the metric type of the indexes on vector field of sources,
+// which orders the merged hits, error occurs if sources have different metric types.
+func (c *GrpcClient) sourcesMetricType(ctx context.Context, sources []SearchSource, vectorField string) (entity.MetricType, error) {
+	var result entity.MetricType
+	for i, source := range sources {
+		metricType, err := c.indexMetricType(ctx, source.Collection, vectorField)
+		if err != nil {
+			return "", fmt.Errorf("source %d collection %s: %w", i, source.Collection, err)
+		}
+		if result != "" && metricType != result {
+			return "", fmt.Errorf("metric type not provided and sources have different metric types %s and %s", result, metricType)
+		}
+		result = metricType
+	}
+	return result, nil
+}
+
+// mergeSearchResults merges the hits of query q in the results of sources succeeded,
+// sources failed for the query are skipped and recorded in SourceErrors of the result.
+func mergeSearchResults(sources []SearchSource, results [][]SearchResult, sourceErrs SourceErrors, q int,
+	metricType entity.MetricType, offset, topK int) MultiSearchResult {
+	var hits []searchHit
+	var subs []*SearchResult
+	var collections []string
+	var failed SourceErrors
+	for i, result := range results {
+		err := sourceErrs[i]
+		if err == nil && result[q].Err != nil {
+			err = fmt.Errorf("search collection %s failed: %w", sources[i].Collection, result[q].Err)
+		}
+		if err != nil {
+			if failed == nil {
+				failed = make(SourceErrors)
+			}
+			failed[i] = err
+			continue
+		}
+		sub := &result[q]
+		subs = append(subs, sub)
+		for j := 0; j < sub.ResultCount; j++ {
+			hits = append(hits, searchHit{result: sub, idx: j, score: sub.Scores[j]})
+			collections = append(collections, sources[i].Collection)
+		}
+	}
+	indices := make([]int, len(hits))
+	for i := range indices {
+		indices[i] = i
+	}
+	larger := metricLargerIsSimilar(metricType)
+	sort.SliceStable(indices, func(i, j int) bool {
+		if larger {
+			return hits[indices[i]].score > hits[indices[j]].score
+		}
+		return hits[indices[i]].score < hits[indices[j]].score
+	})
+	start, end := offset, offset+topK
+	if start > len(indices) {
+		start = len(indices)
+	}
+	if end > len(indices) {
+		end = len(indices)
+	}
+	selected := make([]searchHit, 0, end-start)
+	labels := make([]string, 0, end-start)
+	for _, idx := range indices[start:end] {
+		selected = append(selected, hits[idx])
+		labels = append(labels, collections[idx])
+	}
+	if len(subs) == 0 {
+		return MultiSearchResult{SearchResult: SearchResult{Err: failed}, SourceErrors: failed}
+	}
+	result, err := buildSearchResult(selected, subs)
+	if err != nil {
+		return MultiSearchResult{SearchResult: SearchResult{Err: err}, SourceErrors: failed}
+	}
+	return MultiSearchResult{SearchResult: result, Collections: labels, SourceErrors: failed}
+}
@@ -0,0 +1,220 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+type MultiSearchSuite struct {
+	MockSuiteBase
+	sch *entity.Schema
+}
+
+func (s *MultiSearchSuite) SetupSuite() {
+	s.MockSuiteBase.SetupSuite()
+
+	s.sch = entity.NewSchema().WithName(testCollectionName).
+		WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true)).
+		WithField(entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithDim(2))
+}
+
+// setupSearch mocks search returning the hits of collection for each query, collections not in hits fail.
+func (s *MultiSearchSuite) setupSearch(hits map[string][]int64, scores map[string][]float32) {
+	s.mock.EXPECT().Search(mock.Anything, mock.AnythingOfType("*milvuspb.SearchRequest")).
+		Call.Return(func(_ context.Context, req *milvuspb.SearchRequest) *milvuspb.SearchResults {
+		coll := req.GetCollectionName()
+		if _, ok := hits[coll]; !ok {
+			return &milvuspb.SearchResults{Status: &commonpb.Status{ErrorCode: commonpb.ErrorCode_UnexpectedError}}
+		}
+		s.Equal("0", entity.KvPairsMap(req.GetSearchParams())["offset"])
+		var ids, topks []int64
+		var result []float32
+		for i := int64(0); i < req.GetNq(); i++ {
+			ids = append(ids, hits[coll]...)
+			result = append(result, scores[coll]...)
+			topks = append(topks, int64(len(hits[coll])))
+		}
+		return &milvuspb.SearchResults{
+			Status: getSuccessStatus(),
+			Results: &schemapb.SearchResultData{
+				NumQueries: req.GetNq(),
+				FieldsData: []*schemapb.FieldData{s.getInt64FieldData("id", ids)},
+				Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids}}},
+				Scores:     result,
+				Topks:      topks,
+			},
+		}
+	}, nil)
+}
+
+func (s *MultiSearchSuite) TestMultiSearch() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sp, err := entity.NewIndexFlatSearchParam()
+	s.Require().NoError(err)
+	vectors := []entity.Vector{entity.FloatVector([]float32{1, 0}), entity.FloatVector([]float32{0, 1})}
+	sources := []SearchSource{
+		{Collection: "coll_a", Partitions: []string{"p1"}, Expr: "id > 0"},
+		{Collection: "coll_b"},
+		{Collection: "coll_c"},
+	}
+	hits := map[string][]int64{"coll_a": {1, 2, 3}, "coll_b": {10, 11}}
+
+	s.Run("distance", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupSearch(hits, map[string][]float32{"coll_a": {0.1, 0.4, 0.5}, "coll_b": {0.2, 0.3}})
+
+		// the failed source is reported in each result
+		results, err := s.client.MultiSearch(ctx, sources, []string{"id"}, vectors, "vector", entity.L2, 3, sp)
+		s.Require().NoError(err)
+		s.Require().Len(results, 2)
+		for _, result := range results {
+			s.Require().NoError(result.Err)
+			s.Len(result.SourceErrors, 1)
+			s.Error(result.SourceErrors[2])
+			s.Equal(3, result.ResultCount)
+			s.Equal([]int64{1, 10, 11}, result.IDs.(*entity.ColumnInt64).Data())
+			s.Equal([]int64{1, 10, 11}, result.Fields.GetColumn("id").(*entity.ColumnInt64).Data())
+			s.Equal([]float32{0.1, 0.2, 0.3}, result.Scores)
+			s.Equal([]string{"coll_a", "coll_b", "coll_b"}, result.Collections)
+		}
+	})
+
+	s.Run("similarity_offset", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupSearch(hits, map[string][]float32{"coll_a": {0.9, 0.6, 0.5}, "coll_b": {0.8, 0.7}})
+
+		results, err := s.client.MultiSearch(ctx, sources[:2], nil, vectors[:1], "vector", entity.IP, 3, sp, WithOffset(1))
+		s.Require().NoError(err)
+		s.Require().Len(results, 1)
+		s.Nil(results[0].SourceErrors)
+		s.Equal([]int64{10, 11, 2}, results[0].IDs.(*entity.ColumnInt64).Data())
+		s.Equal([]string{"coll_b", "coll_b", "coll_a"}, results[0].Collections)
+	})
+
+	s.Run("index_metric_type", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupSearch(hits, map[string][]float32{"coll_a": {0.9, 0.6, 0.5}, "coll_b": {0.8, 0.7}})
+		s.mock.EXPECT().DescribeIndex(mock.Anything, mock.AnythingOfType("*milvuspb.DescribeIndexRequest")).
+			Return(&milvuspb.DescribeIndexResponse{
+				Status: getSuccessStatus(),
+				IndexDescriptions: []*milvuspb.IndexDescription{{
+					FieldName: "vector",
+					Params:    entity.MapKvPairs(map[string]string{"index_type": "HNSW", "metric_type": "IP"}),
+				}},
+			}, nil)
+
+		// hits are merged by similarity of the index metric
+		results, err := s.client.MultiSearch(ctx, sources[:2], nil, vectors[:1], "vector", "", 3, sp)
+		s.Require().NoError(err)
+		s.Require().Len(results, 1)
+		s.Equal([]int64{1, 10, 11}, results[0].IDs.(*entity.ColumnInt64).Data())
+	})
+
+	s.Run("index_metric_type_mismatch", func() {
+		defer s.resetMock()
+		s.mock.EXPECT().DescribeIndex(mock.Anything, mock.AnythingOfType("*milvuspb.DescribeIndexRequest")).
+			Call.Return(func(_ context.Context, req *milvuspb.DescribeIndexRequest) *milvuspb.DescribeIndexResponse {
+			metricType := "IP"
+			if req.GetCollectionName() == "coll_b" {
+				metricType = "L2"
+			}
+			return &milvuspb.DescribeIndexResponse{
+				Status: getSuccessStatus(),
+				IndexDescriptions: []*milvuspb.IndexDescription{{
+					FieldName: "vector",
+					Params:    entity.MapKvPairs(map[string]string{"index_type": "HNSW", "metric_type": metricType}),
+				}},
+			}
+		}, nil)
+
+		_, err := s.client.MultiSearch(ctx, sources[:2], nil, vectors[:1], "vector", "", 3, sp)
+		s.Error(err)
+	})
+
+	s.Run("all_fail", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupSearch(nil, nil)
+
+		results, err := s.client.MultiSearch(ctx, sources, nil, vectors, "vector", entity.L2, 3, sp)
+		var sourceErrs SourceErrors
+		s.Require().True(errors.As(err, &sourceErrs))
+		s.Len(sourceErrs, 3)
+		s.Nil(results)
+	})
+
+	s.Run("bad_args", func() {
+		_, err := s.client.MultiSearch(ctx, nil, nil, vectors, "vector", entity.L2, 3, sp)
+		s.Error(err)
+		_, err = s.client.MultiSearch(ctx, sources, nil, vectors, "vector", entity.L2, 0, sp)
+		s.Error(err)
+	})
+}
+
+func TestMergeSearchResults(t *testing.T) {
+	sources := []SearchSource{{Collection: "coll_a"}, {Collection: "coll_b"}, {Collection: "coll_c"}}
+	newResult := func(ids []int64, scores []float32) SearchResult {
+		return SearchResult{
+			ResultCount: len(ids),
+			IDs:         entity.NewColumnInt64("id", ids),
+			Fields:      ResultSet{entity.NewColumnInt64("id", ids)},
+			Scores:      scores,
+		}
+	}
+	results := [][]SearchResult{
+		{newResult([]int64{1, 2}, []float32{0.1, 0.4}), {Err: errors.New("mock")}},
+		{newResult([]int64{10}, []float32{0.2}), {Err: errors.New("mock")}},
+		nil,
+	}
+	sourceErrs := SourceErrors{2: errors.New("mock")}
+
+	// source failed for one query only is skipped for the query
+	results[0][1] = newResult([]int64{3}, []float32{0.3})
+	merged := mergeSearchResults(sources, results, sourceErrs, 1, entity.L2, 0, 3)
+	require.NoError(t, merged.Err)
+	assert.Equal(t, []int64{3}, merged.IDs.(*entity.ColumnInt64).Data())
+	assert.Equal(t, []string{"coll_a"}, merged.Collections)
+	assert.Len(t, merged.SourceErrors, 2)
+	assert.Contains(t, merged.SourceErrors[1].Error(), "coll_b")
+
+	merged = mergeSearchResults(sources, results, sourceErrs, 0, entity.L2, 0, 3)
+	require.NoError(t, merged.Err)
+	assert.Equal(t, []int64{1, 10, 2}, merged.IDs.(*entity.ColumnInt64).Data())
+	assert.Len(t, merged.SourceErrors, 1)
+
+	// all sources failed for the query
+	results[0][1] = SearchResult{Err: errors.New("mock")}
+	merged = mergeSearchResults(sources, results, sourceErrs, 1, entity.L2, 0, 3)
+	assert.Error(t, merged.Err)
+	assert.Len(t, merged.SourceErrors, 3)
+}
+
+func TestMultiSearch(t *testing.T) {
+	suite.Run(t, new(MultiSearchSuite))
+}
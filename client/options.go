@@ -129,6 +129,20 @@ type SearchQueryOption struct {
 
 	IgnoreGrowing bool
 	ForTuning     bool
+	// Decimal places of search scores, -1 for no rounding
+	RoundDecimal int
+
+	// Rerankers applied to search results in order
+	Rerankers []Reranker
+
+	// Group hits by the field, topK is the count of groups
+	GroupByField string
+	GroupSize    int
+
+	// Split search vectors into chunks searched concurrently
+	ChunkNq          int
+	ChunkBytes       int
+	ChunkConcurrency int
 }
 
 // SearchQueryOptionFunc is a function which modifies SearchOption
@@ -160,6 +174,47 @@ func WithLimit(limit int64) SearchQueryOptionFunc {
 	}
 }
 
+// WithRerankers returns search option reranking hits of each query with rerankers in order.
+// Hits are over-fetched as rerankers require, offset and topK are applied after reranking.
+func WithRerankers(rerankers ...Reranker) SearchQueryOptionFunc {
+	return func(option *SearchQueryOption) {
+		option.Rerankers = append(option.Rerankers, rerankers...)
+	}
+}
+
+// WithGroupBy returns search option grouping hits by the scalar output field, topK of search is the count of groups.
+// Groups are ordered by their best hits, each containing up to groupSize hits, where groupSize 1 returns hits
+// distinct by the field. Hits are over-fetched until enough groups found, offset skips groups.
+func WithGroupBy(field string, groupSize int) SearchQueryOptionFunc {
+	return func(option *SearchQueryOption) {
+		option.GroupByField = field
+		option.GroupSize = groupSize
+	}
+}
+
+// WithSearchChunk returns search option splitting vectors into requests of at most maxNq vectors
+// and maxBytes estimated vector payload, default 16384 vectors and 64MB.
+func WithSearchChunk(maxNq, maxBytes int) SearchQueryOptionFunc {
+	return func(option *SearchQueryOption) {
+		option.ChunkNq = maxNq
+		option.ChunkBytes = maxBytes
+	}
+}
+
+// WithSearchConcurrency returns search option limiting the count of chunk requests in flight, default 4.
+func WithSearchConcurrency(concurrency int) SearchQueryOptionFunc {
+	return func(option *SearchQueryOption) {
+		option.ChunkConcurrency = concurrency
+	}
+}
+
+// WithRoundDecimal returns search option rounding scores to decimal places in [0, 6], -1 for no rounding.
+func WithRoundDecimal(decimal int) SearchQueryOptionFunc {
+	return func(option *SearchQueryOption) {
+		option.RoundDecimal = decimal
+	}
+}
+
 // WithSearchQueryConsistencyLevel specifies consistency level
 func WithSearchQueryConsistencyLevel(cl entity.ConsistencyLevel) SearchQueryOptionFunc {
 	return func(option *SearchQueryOption) {
@@ -182,6 +237,7 @@ func WithTravelTimestamp(_ uint64) SearchQueryOptionFunc {
 func makeSearchQueryOption(collName string, opts ...SearchQueryOptionFunc) (*SearchQueryOption, error) {
 	opt := &SearchQueryOption{
 		ConsistencyLevel: entity.ClBounded, // default
+		RoundDecimal:     -1,
 	}
 	info, ok := MetaCache.getCollectionInfo(collName)
 	if ok {
@@ -194,6 +250,9 @@ func makeSearchQueryOption(collName string, opts ...SearchQueryOptionFunc) (*Sea
 	if opt.ConsistencyLevel != entity.ClCustomized && opt.GuaranteeTimestamp != 0 {
 		return nil, errors.New("user can only specify guarantee timestamp under customized consistency level")
 	}
+	if opt.RoundDecimal < -1 || opt.RoundDecimal > 6 {
+		return nil, fmt.Errorf("round decimal %d out of range [-1, 6]", opt.RoundDecimal)
+	}
 
 	switch opt.ConsistencyLevel {
 	case entity.ClStrong:
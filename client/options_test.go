@@ -78,6 +78,7 @@ func TestMakeSearchQueryOption(t *testing.T) {
 			GuaranteeTimestamp: StrongTimestamp,
 			IgnoreGrowing:      false,
 			ForTuning:          false,
+			RoundDecimal:       -1,
 		}
 		assert.Equal(t, expected, opt)
 	})
@@ -91,6 +92,7 @@ func TestMakeSearchQueryOption(t *testing.T) {
 			GuaranteeTimestamp: StrongTimestamp,
 			IgnoreGrowing:      true,
 			ForTuning:          false,
+			RoundDecimal:       -1,
 		}
 		assert.Equal(t, expected, opt)
 	})
@@ -104,6 +106,7 @@ func TestMakeSearchQueryOption(t *testing.T) {
 			GuaranteeTimestamp: StrongTimestamp,
 			IgnoreGrowing:      false,
 			ForTuning:          true,
+			RoundDecimal:       -1,
 		}
 		assert.Equal(t, expected, opt)
 	})
@@ -117,6 +120,7 @@ func TestMakeSearchQueryOption(t *testing.T) {
 			GuaranteeTimestamp: EventuallyTimestamp,
 			IgnoreGrowing:      false,
 			ForTuning:          false,
+			RoundDecimal:       -1,
 		}
 		assert.Equal(t, expected, opt)
 
@@ -129,6 +133,7 @@ func TestMakeSearchQueryOption(t *testing.T) {
 			GuaranteeTimestamp: 99,
 			IgnoreGrowing:      false,
 			ForTuning:          false,
+			RoundDecimal:       -1,
 		}
 		assert.Equal(t, expected, opt)
 	})
@@ -142,6 +147,7 @@ func TestMakeSearchQueryOption(t *testing.T) {
 			GuaranteeTimestamp: BoundedTimestamp,
 			IgnoreGrowing:      false,
 			ForTuning:          false,
+			RoundDecimal:       -1,
 		}
 		assert.Equal(t, expected, opt)
 	})
@@ -155,6 +161,7 @@ func TestMakeSearchQueryOption(t *testing.T) {
 			GuaranteeTimestamp: EventuallyTimestamp,
 			IgnoreGrowing:      false,
 			ForTuning:          false,
+			RoundDecimal:       -1,
 		}
 		assert.Equal(t, expected, opt)
 	})
@@ -168,6 +175,7 @@ func TestMakeSearchQueryOption(t *testing.T) {
 			GuaranteeTimestamp: 100,
 			IgnoreGrowing:      false,
 			ForTuning:          false,
+			RoundDecimal:       -1,
 		}
 		assert.Equal(t, expected, opt)
 	})
@@ -176,4 +184,15 @@ func TestMakeSearchQueryOption(t *testing.T) {
 		_, err := makeSearchQueryOption(c.Name, WithSearchQueryConsistencyLevel(entity.ClStrong), WithGuaranteeTimestamp(100))
 		assert.Error(t, err)
 	})
+
+	t.Run("round decimal", func(t *testing.T) {
+		opt, err := makeSearchQueryOption(c.Name, WithRoundDecimal(3))
+		assert.Nil(t, err)
+		assert.Equal(t, 3, opt.RoundDecimal)
+
+		_, err = makeSearchQueryOption(c.Name, WithRoundDecimal(7))
+		assert.Error(t, err)
+		_, err = makeSearchQueryOption(c.Name, WithRoundDecimal(-2))
+		assert.Error(t, err)
+	})
 }
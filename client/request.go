@@ -0,0 +1,355 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+// SearchRequest is the request of SearchWith, built by NewSearchRequest and the With methods.
+type SearchRequest struct {
+	collName     string
+	partitions   []string
+	vectors      []entity.Vector
+	vectorField  string
+	metricType   entity.MetricType
+	filter       string
+	topK         int
+	outputFields []string
+	sp           entity.SearchParam
+	opts         []SearchQueryOptionFunc
+}
+
+// NewSearchRequest returns a search request on the collection.
+// The vector field is the only vector field of collection if not provided,
+// metric type is the one of vector field index if not provided.
+func NewSearchRequest(collName string) *SearchRequest {
+	return &SearchRequest{collName: collName}
+}
+
+// WithPartitions sets the partitions to search, all partitions are searched if not provided.
+func (r *SearchRequest) WithPartitions(partitions ...string) *SearchRequest {
+	r.partitions = partitions
+	return r
+}
+
+// WithVectors sets the query vectors.
+func (r *SearchRequest) WithVectors(vectors ...entity.Vector) *SearchRequest {
+	r.vectors = vectors
+	return r
+}
+
+// WithVectorField sets the vector field to search.
+func (r *SearchRequest) WithVectorField(field string) *SearchRequest {
+	r.vectorField = field
+	return r
+}
+
+// WithMetricType sets the metric type of search.
+func (r *SearchRequest) WithMetricType(metricType entity.MetricType) *SearchRequest {
+	r.metricType = metricType
+	return r
+}
+
+// WithFilter sets the boolean expression filtering entities.
+func (r *SearchRequest) WithFilter(expr string) *SearchRequest {
+	r.filter = expr
+	return r
+}
+
+// WithTopK sets the count of hits returned for each query vector.
+func (r *SearchRequest) WithTopK(topK int) *SearchRequest {
+	r.topK = topK
+	return r
+}
+
+// WithOutputFields sets the fields returned with hits.
+func (r *SearchRequest) WithOutputFields(fields ...string) *SearchRequest {
+	r.outputFields = fields
+	return r
+}
+
+// WithParams sets the index search params, empty params are used if not provided.
+func (r *SearchRequest) WithParams(sp entity.SearchParam) *SearchRequest {
+	r.sp = sp
+	return r
+}
+
+// WithOffset sets the count of hits skipped.
+func (r *SearchRequest) WithOffset(offset int64) *SearchRequest {
+	return r.WithOptions(WithOffset(offset))
+}
+
+// WithRoundDecimal sets the decimal places of scores in [0, 6], -1 for no rounding.
+func (r *SearchRequest) WithRoundDecimal(decimal int) *SearchRequest {
+	return r.WithOptions(WithRoundDecimal(decimal))
+}
+
+// WithIgnoreGrowing sets whether to skip growing segments.
+func (r *SearchRequest) WithIgnoreGrowing(ignoreGrowing bool) *SearchRequest {
+	return r.WithOptions(func(option *SearchQueryOption) {
+		option.IgnoreGrowing = ignoreGrowing
+	})
+}
+
+// WithConsistencyLevel sets the consistency level of search.
+func (r *SearchRequest) WithConsistencyLevel(cl entity.ConsistencyLevel) *SearchRequest {
+	return r.WithOptions(WithSearchQueryConsistencyLevel(cl))
+}
+
+// WithGuaranteeTimestamp sets the guarantee timestamp of search, for customized consistency level only.
+func (r *SearchRequest) WithGuaranteeTimestamp(ts uint64) *SearchRequest {
+	return r.WithOptions(WithGuaranteeTimestamp(ts))
+}
+
+// WithOptions appends search options, like WithRerankers, WithGroupBy and WithSearchChunk.
+func (r *SearchRequest) WithOptions(opts ...SearchQueryOptionFunc) *SearchRequest {
+	r.opts = append(r.opts, opts...)
+	return r
+}
+
+// SearchWith validates the request against the collection schema, then searches as Search does.
+func (c *GrpcClient) SearchWith(ctx context.Context, req *SearchRequest) ([]SearchResult, error) {
+	if c.Service == nil {
+		return nil, ErrClientNotReady
+	}
+	if req == nil || req.collName == "" {
+		return nil, errors.New("collection name cannot be empty")
+	}
+	if len(req.vectors) == 0 {
+		return nil, errors.New("no query vector provided")
+	}
+	if req.topK <= 0 || req.topK > maxSearchTopK {
+		return nil, fmt.Errorf("topK %d out of range [1, %d]", req.topK, maxSearchTopK)
+	}
+	option, err := makeSearchQueryOption(req.collName, req.opts...)
+	if err != nil {
+		return nil, err
+	}
+	if option.Offset < 0 || int(option.Offset)+req.topK > maxSearchTopK {
+		return nil, fmt.Errorf("offset %d out of range [0, %d]", option.Offset, maxSearchTopK-req.topK)
+	}
+	coll, err := c.DescribeCollection(ctx, req.collName)
+	if err != nil {
+		return nil, err
+	}
+	field, err := searchVectorField(coll.Schema, req.vectorField)
+	if err != nil {
+		return nil, err
+	}
+	for i, vector := range req.vectors {
+		if vector.FieldType() != field.DataType {
+			return nil, fmt.Errorf("vector %d is %s, expected %s of field %s", i, vector.FieldType().Name(), field.DataType.Name(), field.Name)
+		}
+		if dim := field.TypeParams[entity.TypeParamDim]; strconv.Itoa(vector.Dim()) != dim {
+			return nil, fmt.Errorf("vector %d has dim %d, expected %s of field %s", i, vector.Dim(), dim, field.Name)
+		}
+	}
+	if err := checkOutputFields(coll.Schema, req.outputFields); err != nil {
+		return nil, err
+	}
+	metricType := req.metricType
+	if metricType == "" {
+		if metricType, err = c.indexMetricType(ctx, req.collName, field.Name); err != nil {
+			return nil, err
+		}
+	}
+	sp := req.sp
+	if sp == nil {
+		if sp, err = entity.NewIndexFlatSearchParam(); err != nil {
+			return nil, err
+		}
+	}
+	return c.searchWithOption(ctx, coll.Schema, req.collName, req.partitions, req.filter, req.outputFields, req.vectors, field.Name,
+		metricType, req.topK, sp, option)
+}
+
+// indexMetricType returns the metric type of the index on vector field.
+func (c *GrpcClient) indexMetricType(ctx context.Context, collName string, fieldName string) (entity.MetricType, error) {
+	descs, err := c.describeIndex(ctx, collName, fieldName)
+	if err != nil {
+		return "", fmt.Errorf("metric type not provided, failed to get index of field %s: %w", fieldName, err)
+	}
+	for _, desc := range descs {
+		if desc.GetFieldName() != "" && desc.GetFieldName() != fieldName {
+			continue
+		}
+		if metricType := entity.KvPairsMap(desc.GetParams())["metric_type"]; metricType != "" {
+			return entity.MetricType(metricType), nil
+		}
+	}
+	return "", fmt.Errorf("metric type not provided and not found in index of field %s", fieldName)
+}
+
+// searchVectorField returns the vector field of name, or the only vector field if name is empty.
+func searchVectorField(sch *entity.Schema, name string) (*entity.Field, error) {
+	var vectorFields []*entity.Field
+	for _, field := range sch.Fields {
+		if field.DataType == entity.FieldTypeFloatVector || field.DataType == entity.FieldTypeBinaryVector {
+			vectorFields = append(vectorFields, field)
+		}
+	}
+	if name == "" {
+		if len(vectorFields) != 1 {
+			return nil, fmt.Errorf("collection has %d vector fields, vector field must be provided", len(vectorFields))
+		}
+		return vectorFields[0], nil
+	}
+	for _, field := range vectorFields {
+		if field.Name == name {
+			return field, nil
+		}
+	}
+	return nil, fmt.Errorf("vector field %s not found in collection %s", name, sch.CollectionName)
+}
+
+// checkOutputFields checks output fields exist in schema, any field is accepted with dynamic field enabled.
+func checkOutputFields(sch *entity.Schema, outputFields []string) error {
+	if sch.EnableDynamicField {
+		return nil
+	}
+	for _, name := range outputFields {
+		found := false
+		for _, field := range sch.Fields {
+			if field.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("output field %s not found in collection %s", name, sch.CollectionName)
+		}
+	}
+	return nil
+}
+
+// QueryRequest is the request of QueryWith, built by NewQueryRequest and the With methods.
+type QueryRequest struct {
+	collName     string
+	partitions   []string
+	filter       string
+	ids          entity.Column
+	outputFields []string
+	opts         []SearchQueryOptionFunc
+}
+
+// NewQueryRequest returns a query request on the collection.
+func NewQueryRequest(collName string) *QueryRequest {
+	return &QueryRequest{collName: collName}
+}
+
+// WithPartitions sets the partitions to query, all partitions are queried if not provided.
+func (r *QueryRequest) WithPartitions(partitions ...string) *QueryRequest {
+	r.partitions = partitions
+	return r
+}
+
+// WithFilter sets the boolean expression of entities to query.
+func (r *QueryRequest) WithFilter(expr string) *QueryRequest {
+	r.filter = expr
+	return r
+}
+
+// WithIDs sets the primary keys of entities to query, instead of filter.
+func (r *QueryRequest) WithIDs(ids entity.Column) *QueryRequest {
+	r.ids = ids
+	return r
+}
+
+// WithOutputFields sets the fields returned, the primary key is always returned.
+func (r *QueryRequest) WithOutputFields(fields ...string) *QueryRequest {
+	r.outputFields = fields
+	return r
+}
+
+// WithOffset sets the count of entities skipped, limit must be provided with offset.
+func (r *QueryRequest) WithOffset(offset int64) *QueryRequest {
+	return r.WithOptions(WithOffset(offset))
+}
+
+// WithLimit sets the max count of entities returned.
+func (r *QueryRequest) WithLimit(limit int64) *QueryRequest {
+	return r.WithOptions(WithLimit(limit))
+}
+
+// WithIgnoreGrowing sets whether to skip growing segments.
+func (r *QueryRequest) WithIgnoreGrowing(ignoreGrowing bool) *QueryRequest {
+	return r.WithOptions(func(option *SearchQueryOption) {
+		option.IgnoreGrowing = ignoreGrowing
+	})
+}
+
+// WithConsistencyLevel sets the consistency level of query.
+func (r *QueryRequest) WithConsistencyLevel(cl entity.ConsistencyLevel) *QueryRequest {
+	return r.WithOptions(WithSearchQueryConsistencyLevel(cl))
+}
+
+// WithGuaranteeTimestamp sets the guarantee timestamp of query, for customized consistency level only.
+func (r *QueryRequest) WithGuaranteeTimestamp(ts uint64) *QueryRequest {
+	return r.WithOptions(WithGuaranteeTimestamp(ts))
+}
+
+// WithOptions appends query options.
+func (r *QueryRequest) WithOptions(opts ...SearchQueryOptionFunc) *QueryRequest {
+	r.opts = append(r.opts, opts...)
+	return r
+}
+
+// QueryWith validates the request against the collection schema, then queries by ids or filter
+// as QueryByPks or Query does.
+func (c *GrpcClient) QueryWith(ctx context.Context, req *QueryRequest) (ResultSet, error) {
+	if c.Service == nil {
+		return nil, ErrClientNotReady
+	}
+	if req == nil || req.collName == "" {
+		return nil, errors.New("collection name cannot be empty")
+	}
+	if (req.filter == "") == (req.ids == nil) {
+		return nil, errors.New("exactly one of filter and ids must be provided")
+	}
+	if req.ids != nil && req.ids.Len() == 0 {
+		return nil, errors.New("ids cannot be empty")
+	}
+	option, err := makeSearchQueryOption(req.collName, req.opts...)
+	if err != nil {
+		return nil, err
+	}
+	if option.Offset < 0 || option.Limit < 0 {
+		return nil, errors.New("offset and limit cannot be negative")
+	}
+	if option.Offset > 0 && option.Limit == 0 {
+		return nil, errors.New("limit must be provided with offset")
+	}
+	if option.Offset+option.Limit > maxSearchTopK {
+		return nil, fmt.Errorf("offset + limit %d exceeds %d", option.Offset+option.Limit, maxSearchTopK)
+	}
+	coll, err := c.DescribeCollection(ctx, req.collName)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOutputFields(coll.Schema, req.outputFields); err != nil {
+		return nil, err
+	}
+	filter := req.filter
+	if req.ids != nil {
+		if filter, err = pks2Expr("", req.ids); err != nil {
+			return nil, err
+		}
+	}
+	return c.queryWithOption(ctx, coll.Schema, req.collName, req.partitions, filter, req.outputFields, option)
+}
@@ -0,0 +1,244 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+type RequestSuite struct {
+	MockSuiteBase
+	sch *entity.Schema
+}
+
+func (s *RequestSuite) SetupSuite() {
+	s.MockSuiteBase.SetupSuite()
+
+	s.sch = entity.NewSchema().WithName(testCollectionName).
+		WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true)).
+		WithField(entity.NewField().WithName("tag").WithDataType(entity.FieldTypeVarChar).WithMaxLength(16)).
+		WithField(entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithDim(2))
+}
+
+func (s *RequestSuite) TestSearchWith() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	vector := entity.FloatVector([]float32{0.1, 0.2})
+
+	s.Run("normal_case", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		reqs := make(chan *milvuspb.SearchRequest, 1)
+		s.mock.EXPECT().Search(mock.Anything, mock.AnythingOfType("*milvuspb.SearchRequest")).
+			Call.Return(func(_ context.Context, req *milvuspb.SearchRequest) *milvuspb.SearchResults {
+			reqs <- req
+			return &milvuspb.SearchResults{
+				Status: getSuccessStatus(),
+				Results: &schemapb.SearchResultData{
+					NumQueries: 1,
+					FieldsData: []*schemapb.FieldData{s.getVarcharFieldData("tag", []string{"a"})},
+					Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1}}}},
+					Scores:     []float32{0.5},
+					Topks:      []int64{1},
+				},
+			}
+		}, nil)
+
+		sp, err := entity.NewIndexIvfFlatSearchParam(16)
+		s.Require().NoError(err)
+		results, err := s.client.SearchWith(ctx, NewSearchRequest(testCollectionName).
+			WithPartitions("p1").
+			WithVectors(vector).
+			WithFilter("id > 0").
+			WithTopK(10).
+			WithOutputFields("tag").
+			WithParams(sp).
+			WithMetricType(entity.IP).
+			WithOffset(2).
+			WithRoundDecimal(3).
+			WithIgnoreGrowing(true).
+			WithConsistencyLevel(entity.ClStrong))
+		s.Require().NoError(err)
+		s.Require().Len(results, 1)
+		s.Equal([]int64{1}, results[0].IDs.(*entity.ColumnInt64).Data())
+		s.Equal([]string{"a"}, results[0].Fields.GetColumn("tag").(*entity.ColumnVarChar).Data())
+
+		req := <-reqs
+		s.Equal([]string{"p1"}, req.GetPartitionNames())
+		s.Equal("id > 0", req.GetDsl())
+		s.Equal([]string{"tag"}, req.GetOutputFields())
+		s.EqualValues(1, req.GetNq())
+		s.Equal(StrongTimestamp, req.GetGuaranteeTimestamp())
+		params := entity.KvPairsMap(req.GetSearchParams())
+		s.Equal("vector", params["anns_field"])
+		s.Equal("10", params["topk"])
+		s.Equal("IP", params["metric_type"])
+		s.Equal("3", params["round_decimal"])
+		s.Equal("2", params["offset"])
+		s.Equal("true", params[ignoreGrowingKey])
+		s.Contains(params["params"], `"nprobe":16`)
+	})
+
+	s.Run("index_metric_type", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.mock.EXPECT().DescribeIndex(mock.Anything, mock.AnythingOfType("*milvuspb.DescribeIndexRequest")).
+			Call.Return(func(_ context.Context, req *milvuspb.DescribeIndexRequest) *milvuspb.DescribeIndexResponse {
+			s.Equal("vector", req.GetFieldName())
+			return &milvuspb.DescribeIndexResponse{
+				Status: getSuccessStatus(),
+				IndexDescriptions: []*milvuspb.IndexDescription{{
+					IndexName: "vec_idx",
+					FieldName: "vector",
+					Params:    entity.MapKvPairs(map[string]string{"index_type": "HNSW", "metric_type": "COSINE"}),
+				}},
+			}
+		}, nil)
+		reqs := make(chan *milvuspb.SearchRequest, 1)
+		s.mock.EXPECT().Search(mock.Anything, mock.AnythingOfType("*milvuspb.SearchRequest")).
+			Call.Return(func(_ context.Context, req *milvuspb.SearchRequest) *milvuspb.SearchResults {
+			reqs <- req
+			return &milvuspb.SearchResults{
+				Status:  getSuccessStatus(),
+				Results: &schemapb.SearchResultData{NumQueries: 1, Topks: []int64{0}},
+			}
+		}, nil)
+
+		_, err := s.client.SearchWith(ctx, NewSearchRequest(testCollectionName).WithVectors(vector).WithTopK(10))
+		s.Require().NoError(err)
+		req := <-reqs
+		s.Equal("COSINE", entity.KvPairsMap(req.GetSearchParams())["metric_type"])
+	})
+
+	s.Run("no_index_metric_type", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.mock.EXPECT().DescribeIndex(mock.Anything, mock.AnythingOfType("*milvuspb.DescribeIndexRequest")).
+			Return(&milvuspb.DescribeIndexResponse{Status: &commonpb.Status{ErrorCode: commonpb.ErrorCode_IndexNotExist}}, nil)
+
+		_, err := s.client.SearchWith(ctx, NewSearchRequest(testCollectionName).WithVectors(vector).WithTopK(10))
+		s.Error(err)
+	})
+
+	s.Run("invalid_request", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+
+		for name, req := range map[string]*SearchRequest{
+			"no_collection":    NewSearchRequest("").WithVectors(vector).WithTopK(10),
+			"no_vectors":       NewSearchRequest(testCollectionName).WithTopK(10),
+			"no_topk":          NewSearchRequest(testCollectionName).WithVectors(vector),
+			"large_topk":       NewSearchRequest(testCollectionName).WithVectors(vector).WithTopK(maxSearchTopK + 1),
+			"large_offset":     NewSearchRequest(testCollectionName).WithVectors(vector).WithTopK(10).WithOffset(maxSearchTopK),
+			"round_decimal":    NewSearchRequest(testCollectionName).WithVectors(vector).WithTopK(10).WithRoundDecimal(7),
+			"vector_field":     NewSearchRequest(testCollectionName).WithVectors(vector).WithTopK(10).WithVectorField("tag"),
+			"vector_dim":       NewSearchRequest(testCollectionName).WithVectors(entity.FloatVector([]float32{1})).WithTopK(10),
+			"vector_type":      NewSearchRequest(testCollectionName).WithVectors(entity.BinaryVector([]byte{1})).WithTopK(10),
+			"output_field":     NewSearchRequest(testCollectionName).WithVectors(vector).WithTopK(10).WithOutputFields("unknown"),
+			"consistency_mode": NewSearchRequest(testCollectionName).WithVectors(vector).WithTopK(10).WithGuaranteeTimestamp(100),
+		} {
+			_, err := s.client.SearchWith(ctx, req)
+			s.Error(err, name)
+		}
+
+		_, err := s.client.SearchWith(ctx, nil)
+		s.Error(err)
+	})
+
+	s.Run("ambiguous_vector_field", func() {
+		defer s.resetMock()
+		sch := entity.NewSchema().WithName(testCollectionName).
+			WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true)).
+			WithField(entity.NewField().WithName("v1").WithDataType(entity.FieldTypeFloatVector).WithDim(2)).
+			WithField(entity.NewField().WithName("v2").WithDataType(entity.FieldTypeFloatVector).WithDim(2))
+		s.setupDescribeCollection(testCollectionName, sch)
+
+		_, err := s.client.SearchWith(ctx, NewSearchRequest(testCollectionName).WithVectors(vector).WithTopK(10))
+		s.Error(err)
+	})
+}
+
+func (s *RequestSuite) TestQueryWith() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.Run("normal_case", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		reqs := make(chan *milvuspb.QueryRequest, 2)
+		s.mock.EXPECT().Query(mock.Anything, mock.AnythingOfType("*milvuspb.QueryRequest")).
+			Call.Return(func(_ context.Context, req *milvuspb.QueryRequest) *milvuspb.QueryResults {
+			reqs <- req
+			return &milvuspb.QueryResults{
+				Status: getSuccessStatus(),
+				FieldsData: []*schemapb.FieldData{
+					s.getInt64FieldData("id", []int64{1, 2}),
+					s.getVarcharFieldData("tag", []string{"a", "b"}),
+				},
+			}
+		}, nil)
+
+		rs, err := s.client.QueryWith(ctx, NewQueryRequest(testCollectionName).
+			WithPartitions("p1").
+			WithFilter("id > 0").
+			WithOutputFields("tag").
+			WithOffset(5).
+			WithLimit(10).
+			WithConsistencyLevel(entity.ClEventually))
+		s.Require().NoError(err)
+		s.Equal(2, rs.Len())
+		req := <-reqs
+		s.Equal("id > 0", req.GetExpr())
+		s.Equal([]string{"p1"}, req.GetPartitionNames())
+		s.Equal(EventuallyTimestamp, req.GetGuaranteeTimestamp())
+		params := entity.KvPairsMap(req.GetQueryParams())
+		s.Equal("5", params[offsetKey])
+		s.Equal("10", params[limitKey])
+
+		ids := entity.NewColumnInt64("id", []int64{1, 2})
+		_, err = s.client.QueryWith(ctx, NewQueryRequest(testCollectionName).WithIDs(ids))
+		s.Require().NoError(err)
+		s.Equal(PKs2Expr("", ids), (<-reqs).GetExpr())
+	})
+
+	s.Run("invalid_request", func() {
+		defer s.resetMock()
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		ids := entity.NewColumnInt64("id", []int64{1})
+
+		for name, req := range map[string]*QueryRequest{
+			"no_collection":  NewQueryRequest("").WithFilter("id > 0"),
+			"no_filter":      NewQueryRequest(testCollectionName),
+			"filter_and_ids": NewQueryRequest(testCollectionName).WithFilter("id > 0").WithIDs(ids),
+			"empty_ids":      NewQueryRequest(testCollectionName).WithIDs(entity.NewColumnInt64("id", nil)),
+			"offset_only":    NewQueryRequest(testCollectionName).WithFilter("id > 0").WithOffset(1),
+			"large_limit":    NewQueryRequest(testCollectionName).WithFilter("id > 0").WithLimit(maxSearchTopK + 1),
+			"output_field":   NewQueryRequest(testCollectionName).WithFilter("id > 0").WithOutputFields("unknown"),
+		} {
+			_, err := s.client.QueryWith(ctx, req)
+			s.Error(err, name)
+		}
+	})
+}
+
+func TestRequest(t *testing.T) {
+	suite.Run(t, new(RequestSuite))
+}
@@ -0,0 +1,295 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/distance"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+const (
+	defaultMMRFetchFactor   = 3
+	defaultBoostFetchFactor = 2
+)
+
+// Reranker reorders the hits of one query after Search, attached to Search by WithRerankers.
+//
+// Scores passed to rerankers are normalized into [0, 1] by NormalizeScore, where greater means more relevant,
+// and the reranked scores are returned by Search in place of the raw scores.
+type Reranker interface {
+	// FetchSize returns the count of hits to fetch from server to output topK hits.
+	FetchSize(topK int) int
+	// OutputFields returns the fields the reranker reads, which are fetched and removed from results if not requested.
+	OutputFields() []string
+	// Rerank returns the hits of result reordered, hits may be dropped but not added.
+	Rerank(metricType entity.MetricType, result SearchResult) (SearchResult, error)
+}
+
+// ScoreThresholdReranker drops hits with normalized score less than MinScore.
+type ScoreThresholdReranker struct {
+	MinScore float32
+}
+
+// NewScoreThresholdReranker returns a ScoreThresholdReranker with the minimum normalized score.
+func NewScoreThresholdReranker(minScore float32) *ScoreThresholdReranker {
+	return &ScoreThresholdReranker{MinScore: minScore}
+}
+
+// FetchSize implements Reranker.
+func (r *ScoreThresholdReranker) FetchSize(topK int) int {
+	return topK
+}
+
+// OutputFields implements Reranker.
+func (r *ScoreThresholdReranker) OutputFields() []string {
+	return nil
+}
+
+// Rerank implements Reranker.
+func (r *ScoreThresholdReranker) Rerank(_ entity.MetricType, result SearchResult) (SearchResult, error) {
+	indices := make([]int, 0, result.ResultCount)
+	for i, score := range result.Scores {
+		if score >= r.MinScore {
+			indices = append(indices, i)
+		}
+	}
+	return reorderSearchResult(result, indices, result.Scores)
+}
+
+// FieldBoostReranker multiplies the score of each hit by the boost computed from its output fields,
+// like promoting hits by business rules.
+type FieldBoostReranker struct {
+	Fields []string
+	// Boost returns the factor of the row, which is the same as ResultSet.Rows returns.
+	Boost       func(row map[string]interface{}) float32
+	FetchFactor int // hits fetched are FetchFactor times topK, 2 if not positive
+}
+
+// NewFieldBoostReranker returns a FieldBoostReranker reading fields.
+func NewFieldBoostReranker(fields []string, boost func(row map[string]interface{}) float32) *FieldBoostReranker {
+	return &FieldBoostReranker{Fields: fields, Boost: boost, FetchFactor: defaultBoostFetchFactor}
+}
+
+// FetchSize implements Reranker.
+func (r *FieldBoostReranker) FetchSize(topK int) int {
+	return topK * fetchFactor(r.FetchFactor, defaultBoostFetchFactor)
+}
+
+// OutputFields implements Reranker.
+func (r *FieldBoostReranker) OutputFields() []string {
+	return r.Fields
+}
+
+// Rerank implements Reranker.
+func (r *FieldBoostReranker) Rerank(_ entity.MetricType, result SearchResult) (SearchResult, error) {
+	scores := make([]float32, result.ResultCount)
+	indices := make([]int, result.ResultCount)
+	for i := range indices {
+		indices[i] = i
+		scores[i] = result.Scores[i] * r.Boost(result.Fields.row(i))
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return scores[indices[i]] > scores[indices[j]]
+	})
+	return reorderSearchResult(result, indices, scores)
+}
+
+// MMRReranker diversifies hits by Maximal Marginal Relevance, selecting the hit maximizing
+// Lambda * score - (1 - Lambda) * max similarity to the hits selected, where similarity of vectors in
+// VectorField is computed with the search metric type and normalized as scores.
+// Scores of hits are kept, so they are not in descending order after reranking.
+type MMRReranker struct {
+	VectorField string
+	Lambda      float32 // 1 ranks by relevance only, 0 by diversity only
+	FetchFactor int     // hits fetched are FetchFactor times topK, 3 if not positive
+}
+
+// NewMMRReranker returns a MMRReranker on the float vector field.
+func NewMMRReranker(vectorField string, lambda float32) *MMRReranker {
+	return &MMRReranker{VectorField: vectorField, Lambda: lambda, FetchFactor: defaultMMRFetchFactor}
+}
+
+// FetchSize implements Reranker.
+func (r *MMRReranker) FetchSize(topK int) int {
+	return topK * fetchFactor(r.FetchFactor, defaultMMRFetchFactor)
+}
+
+// OutputFields implements Reranker.
+func (r *MMRReranker) OutputFields() []string {
+	return []string{r.VectorField}
+}
+
+// Rerank implements Reranker.
+func (r *MMRReranker) Rerank(metricType entity.MetricType, result SearchResult) (SearchResult, error) {
+	column, ok := result.Fields.GetColumn(r.VectorField).(*entity.ColumnFloatVector)
+	if !ok {
+		return SearchResult{}, fmt.Errorf("float vector field %s not found in search result", r.VectorField)
+	}
+	n := result.ResultCount
+	// maxSim is the max similarity of each hit to the hits selected
+	maxSim := make([]float32, n)
+	selected := make([]bool, n)
+	indices := make([]int, 0, n)
+	for len(indices) < n {
+		best, bestValue := -1, float32(0)
+		for i := 0; i < n; i++ {
+			if selected[i] {
+				continue
+			}
+			value := r.Lambda*result.Scores[i] - (1-r.Lambda)*maxSim[i]
+			if best < 0 || value > bestValue {
+				best, bestValue = i, value
+			}
+		}
+		selected[best] = true
+		indices = append(indices, best)
+		bestVector, err := column.ValueByIdx(best)
+		if err != nil {
+			return SearchResult{}, err
+		}
+		for i := 0; i < n; i++ {
+			if selected[i] {
+				continue
+			}
+			vector, err := column.ValueByIdx(i)
+			if err != nil {
+				return SearchResult{}, err
+			}
+			sim, err := vectorSimilarity(metricType, vector, bestVector)
+			if err != nil {
+				return SearchResult{}, err
+			}
+			if sim > maxSim[i] {
+				maxSim[i] = sim
+			}
+		}
+	}
+	return reorderSearchResult(result, indices, result.Scores)
+}
+
+// vectorSimilarity returns the normalized similarity of float vectors with the metric type.
+func vectorSimilarity(metricType entity.MetricType, a, b entity.FloatVector) (float32, error) {
+	switch metricType {
+	case entity.L2:
+		return NormalizeScore(metricType, distance.L2(a, b)), nil
+	case entity.IP:
+		return NormalizeScore(metricType, distance.IP(a, b)), nil
+	case entity.COSINE:
+		return NormalizeScore(metricType, distance.Cosine(a, b)), nil
+	default:
+		return 0, fmt.Errorf("metric type %s not supported for vector similarity", metricType)
+	}
+}
+
+func fetchFactor(factor, defaultFactor int) int {
+	if factor <= 0 {
+		return defaultFactor
+	}
+	return factor
+}
+
+// searchRerank searches with hits and fields required by rerankers, then reranks hits of each query,
+// applying offset and topK on reranked hits.
+func (c *GrpcClient) searchRerank(ctx context.Context, schema *entity.Schema, collName string, partitions []string,
+	expr string, outputFields []string, vectors []entity.Vector, vectorField string, metricType entity.MetricType, topK int, sp entity.SearchParam, option *SearchQueryOption) ([]SearchResult, error) {
+	offset := int(option.Offset)
+	fetch := offset + topK
+	fields := append([]string{}, outputFields...)
+	exists := make(map[string]bool)
+	for _, field := range fields {
+		exists[field] = true
+	}
+	for _, r := range option.Rerankers {
+		if size := r.FetchSize(offset + topK); size > fetch {
+			fetch = size
+		}
+		for _, field := range r.OutputFields() {
+			if !exists[field] {
+				exists[field] = true
+				fields = append(fields, field)
+			}
+		}
+	}
+	opt := *option
+	opt.Offset = 0
+	results, err := c.search(ctx, schema, collName, partitions, expr, fields, vectors, vectorField, metricType, fetch, sp, &opt)
+	if err != nil {
+		return nil, err
+	}
+	for i, result := range results {
+		if result.Err != nil {
+			continue
+		}
+		result, err = rerankSearchResult(result, metricType, option.Rerankers)
+		if err != nil {
+			results[i] = SearchResult{Err: err}
+			continue
+		}
+		start, end := offset, offset+topK
+		if start > result.ResultCount {
+			start = result.ResultCount
+		}
+		if end > result.ResultCount {
+			end = result.ResultCount
+		}
+		// output fields come first in fields
+		results[i] = SearchResult{
+			ResultCount: end - start,
+			IDs:         result.IDs.Slice(start, end),
+			Fields:      result.Fields.slice(start, end)[:len(outputFields)],
+			Scores:      result.Scores[start:end],
+		}
+	}
+	return results, nil
+}
+
+func rerankSearchResult(result SearchResult, metricType entity.MetricType, rerankers []Reranker) (SearchResult, error) {
+	scores := make([]float32, len(result.Scores))
+	for i, score := range result.Scores {
+		scores[i] = NormalizeScore(metricType, score)
+	}
+	result.Scores = scores
+	for _, r := range rerankers {
+		var err error
+		result, err = r.Rerank(metricType, result)
+		if err != nil {
+			return SearchResult{}, err
+		}
+	}
+	return result, nil
+}
+
+// reorderSearchResult returns the hits of result at indices, with scores of the hits from scores.
+func reorderSearchResult(result SearchResult, indices []int, scores []float32) (SearchResult, error) {
+	ids, err := takeColumn(result.IDs, indices)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	fields, err := result.Fields.take(indices)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	reordered := make([]float32, 0, len(indices))
+	for _, idx := range indices {
+		reordered = append(reordered, scores[idx])
+	}
+	return SearchResult{
+		ResultCount: len(indices),
+		IDs:         ids,
+		Fields:      fields,
+		Scores:      reordered,
+	}, nil
+}
@@ -0,0 +1,192 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+type RerankSuite struct {
+	MockSuiteBase
+	sch *entity.Schema
+}
+
+func (s *RerankSuite) SetupSuite() {
+	s.MockSuiteBase.SetupSuite()
+
+	s.sch = entity.NewSchema().WithName(testCollectionName).
+		WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true)).
+		WithField(entity.NewField().WithName("tag").WithDataType(entity.FieldTypeInt64)).
+		WithField(entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithDim(2))
+}
+
+// setupSearch mocks search returning hits 1, 2, 3 for one query, where 1 and 2 have the same vector.
+// The topk and output fields requested are sent to reqs.
+func (s *RerankSuite) setupSearch(scores []float32, reqs chan<- *milvuspb.SearchRequest) {
+	s.mock.EXPECT().Search(mock.Anything, mock.AnythingOfType("*milvuspb.SearchRequest")).
+		Call.Return(func(_ context.Context, req *milvuspb.SearchRequest) *milvuspb.SearchResults {
+		reqs <- req
+		ids := []int64{1, 2, 3}
+		var fields []*schemapb.FieldData
+		for _, field := range req.GetOutputFields() {
+			switch field {
+			case "id":
+				fields = append(fields, s.getInt64FieldData("id", ids))
+			case "tag":
+				fields = append(fields, s.getInt64FieldData("tag", []int64{0, 0, 1}))
+			case "vector":
+				fields = append(fields, s.getFloatVectorFieldData("vector", 2, []float32{1, 0, 1, 0, 0, 1}))
+			}
+		}
+		return &milvuspb.SearchResults{
+			Status: getSuccessStatus(),
+			Results: &schemapb.SearchResultData{
+				NumQueries: 1,
+				FieldsData: fields,
+				Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids}}},
+				Scores:     scores,
+				Topks:      []int64{3},
+			},
+		}
+	}, nil)
+}
+
+func (s *RerankSuite) TestSearchRerank() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sp, err := entity.NewIndexFlatSearchParam()
+	s.Require().NoError(err)
+	vectors := []entity.Vector{entity.FloatVector([]float32{1, 0})}
+
+	s.Run("threshold", func() {
+		defer s.resetMock()
+		reqs := make(chan *milvuspb.SearchRequest, 1)
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupSearch([]float32{0.1, 0.5, 3}, reqs)
+
+		results, err := s.client.Search(ctx, testCollectionName, nil, "", nil, vectors, "vector", entity.L2, 3, sp,
+			WithRerankers(NewScoreThresholdReranker(0.5)))
+		s.Require().NoError(err)
+		s.Require().Len(results, 1)
+		s.Require().NoError(results[0].Err)
+		s.Equal(2, results[0].ResultCount)
+		s.Equal([]int64{1, 2}, results[0].IDs.(*entity.ColumnInt64).Data())
+		s.InDelta(NormalizeScore(entity.L2, 0.1), results[0].Scores[0], 1e-6)
+		s.InDelta(NormalizeScore(entity.L2, 0.5), results[0].Scores[1], 1e-6)
+		s.Equal("3", entity.KvPairsMap((<-reqs).GetSearchParams())["topk"])
+	})
+
+	s.Run("boost", func() {
+		defer s.resetMock()
+		reqs := make(chan *milvuspb.SearchRequest, 1)
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupSearch([]float32{0.9, 0.8, 0.7}, reqs)
+
+		boost := NewFieldBoostReranker([]string{"tag"}, func(row map[string]interface{}) float32 {
+			if row["tag"].(int64) == 1 {
+				return 2
+			}
+			return 1
+		})
+		results, err := s.client.Search(ctx, testCollectionName, nil, "", []string{"id"}, vectors, "vector", entity.IP, 2, sp,
+			WithRerankers(boost))
+		s.Require().NoError(err)
+		s.Require().NoError(results[0].Err)
+		s.Equal([]int64{3, 1}, results[0].IDs.(*entity.ColumnInt64).Data())
+		s.InDelta(2*NormalizeScore(entity.IP, 0.7), results[0].Scores[0], 1e-6)
+		// tag is fetched for reranking only
+		s.Require().Len(results[0].Fields, 1)
+		s.Equal("id", results[0].Fields[0].Name())
+
+		req := <-reqs
+		s.Equal("4", entity.KvPairsMap(req.GetSearchParams())["topk"])
+		s.Equal([]string{"id", "tag"}, req.GetOutputFields())
+	})
+
+	s.Run("mmr", func() {
+		defer s.resetMock()
+		reqs := make(chan *milvuspb.SearchRequest, 2)
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupSearch([]float32{0.9, 0.89, 0.5}, reqs)
+
+		// 2 is the same as 1, replaced by 3 with less relevance
+		results, err := s.client.Search(ctx, testCollectionName, nil, "", nil, vectors, "vector", entity.IP, 2, sp,
+			WithRerankers(NewMMRReranker("vector", 0.5)))
+		s.Require().NoError(err)
+		s.Require().NoError(results[0].Err)
+		s.Equal([]int64{1, 3}, results[0].IDs.(*entity.ColumnInt64).Data())
+		s.Len(results[0].Fields, 0)
+		s.Equal("6", entity.KvPairsMap((<-reqs).GetSearchParams())["topk"])
+
+		results, err = s.client.Search(ctx, testCollectionName, nil, "", nil, vectors, "vector", entity.IP, 2, sp,
+			WithRerankers(NewMMRReranker("vector", 0.5)), WithOffset(1))
+		s.Require().NoError(err)
+		s.Require().NoError(results[0].Err)
+		s.Equal([]int64{3, 2}, results[0].IDs.(*entity.ColumnInt64).Data())
+		params := entity.KvPairsMap((<-reqs).GetSearchParams())
+		s.Equal("9", params["topk"])
+		s.Equal("0", params["offset"])
+
+		// relevance only
+		results, err = s.client.Search(ctx, testCollectionName, nil, "", nil, vectors, "vector", entity.IP, 3, sp,
+			WithRerankers(&MMRReranker{VectorField: "vector", Lambda: 1}))
+		s.Require().NoError(err)
+		s.Equal([]int64{1, 2, 3}, results[0].IDs.(*entity.ColumnInt64).Data())
+		<-reqs
+	})
+
+	s.Run("mmr_bad_metric", func() {
+		defer s.resetMock()
+		reqs := make(chan *milvuspb.SearchRequest, 1)
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupSearch([]float32{0.1, 0.2, 0.3}, reqs)
+
+		results, err := s.client.Search(ctx, testCollectionName, nil, "", nil, vectors, "vector", entity.HAMMING, 2, sp,
+			WithRerankers(NewMMRReranker("vector", 0.5)))
+		s.Require().NoError(err)
+		s.Error(results[0].Err)
+	})
+}
+
+func TestVectorSimilarity(t *testing.T) {
+	for _, c := range []struct {
+		metricType entity.MetricType
+		a, b       []float32
+		expected   float32
+	}{
+		{entity.L2, []float32{1, 0}, []float32{1, 0}, 1},
+		{entity.IP, []float32{1, 0}, []float32{0, 1}, 0.5},
+		{entity.COSINE, []float32{2, 0}, []float32{1, 0}, 1},
+		{entity.COSINE, []float32{1, 0}, []float32{-1, 0}, 0},
+		{entity.COSINE, []float32{0, 0}, []float32{1, 0}, 0.5},
+	} {
+		t.Run(string(c.metricType)+strconv.FormatFloat(float64(c.expected), 'f', -1, 32), func(t *testing.T) {
+			sim, err := vectorSimilarity(c.metricType, c.a, c.b)
+			if err != nil || sim < c.expected-1e-6 || sim > c.expected+1e-6 {
+				t.Errorf("expected %v, got %v, %v", c.expected, sim, err)
+			}
+		})
+	}
+}
+
+func TestRerank(t *testing.T) {
+	suite.Run(t, new(RerankSuite))
+}
@@ -0,0 +1,366 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/bulkwriter"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+// RestoreOption is option for Restore.
+type RestoreOption func(opt *restoreOpt)
+
+type restoreOpt struct {
+	collName    string
+	skipAliases bool
+	store       ObjectStore
+	loaderOpts  []BulkLoaderOption
+}
+
+// WithRestoreCollectionName restores into the collection with provided name instead of the backup collection name.
+func WithRestoreCollectionName(collName string) RestoreOption {
+	return func(opt *restoreOpt) {
+		opt.collName = collName
+	}
+}
+
+// WithRestoreSkipAliases skips creating aliases of the backup collection,
+// which is required when restoring into the database the aliases still exist in.
+func WithRestoreSkipAliases() RestoreOption {
+	return func(opt *restoreOpt) {
+		opt.skipAliases = true
+	}
+}
+
+// WithRestoreObjectStore restores data by bulk insert from the object store instead of Insert.
+func WithRestoreObjectStore(store ObjectStore) RestoreOption {
+	return func(opt *restoreOpt) {
+		opt.store = store
+	}
+}
+
+// WithRestoreLoaderOptions sets the options of BulkLoader used to insert data.
+func WithRestoreLoaderOptions(opts ...BulkLoaderOption) RestoreOption {
+	return func(opt *restoreOpt) {
+		opt.loaderOpts = opts
+	}
+}
+
+// restoreState records the progress of restore, stored as restore_<collection>.json in the backup directory.
+type restoreState struct {
+	Created bool             `json:"created"`
+	Done    map[string]bool  `json:"done"`    // restored data files
+	Loading string           `json:"loading"` // data file being inserted into auto id collection
+	Tasks   map[string]int64 `json:"tasks"`   // submitted bulk insert tasks by data file and file group
+	Indexed bool             `json:"indexed"`
+	Aliases map[string]bool  `json:"aliases"` // created aliases
+}
+
+// Restore replays the backup in dir, written by Backup, into the database the client uses.
+// The collection is created with the backup schema, followed by partitions, data, indexes and aliases.
+// Data files are verified with checksums before restored. Progress is recorded in the backup directory,
+// calling Restore again after a failure resumes from the files not restored and the bulk insert tasks not finished.
+// Primary keys of auto id collections are regenerated, rows are inserted instead of upserted,
+// so a restore interrupted while inserting a data file cannot be resumed without duplicating rows,
+// drop the collection and restore again instead. Restoring with an object store has no such limitation.
+func (c *GrpcClient) Restore(ctx context.Context, dir string, opts ...RestoreOption) error {
+	if c.Service == nil {
+		return ErrClientNotReady
+	}
+	manifest, err := readBackupManifest(dir)
+	if err != nil {
+		return err
+	}
+	if manifest == nil {
+		return fmt.Errorf("backup manifest not found in %s", dir)
+	}
+	if !manifest.Completed {
+		return fmt.Errorf("backup in %s is not completed", dir)
+	}
+	opt := restoreOpt{collName: manifest.Collection}
+	for _, o := range opts {
+		o(&opt)
+	}
+	sch, err := manifest.CollectionSchema()
+	if err != nil {
+		return err
+	}
+	sch = restoreSchema(sch, opt.collName)
+
+	statePath := filepath.Join(dir, fmt.Sprintf("restore_%s.json", opt.collName))
+	state, err := readRestoreState(statePath)
+	if err != nil {
+		return err
+	}
+	if !state.Created {
+		if err := c.restoreCollection(ctx, manifest, sch); err != nil {
+			return err
+		}
+		state.Created = true
+		if err := writeJSONFile(statePath, state); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range manifest.Partitions {
+		if err := c.restorePartition(ctx, dir, p, sch, state, statePath, opt); err != nil {
+			return fmt.Errorf("failed to restore partition %s: %w", p.Name, err)
+		}
+	}
+
+	if !state.Indexed {
+		for _, idx := range manifest.Indexes {
+			index := entity.NewGenericIndex(idx.Name, entity.IndexType(idx.Params["index_type"]), idx.Params)
+			if err := c.CreateIndex(ctx, opt.collName, idx.Field, index, true, WithIndexName(idx.Name)); err != nil {
+				return fmt.Errorf("failed to create index %s: %w", idx.Name, err)
+			}
+		}
+		state.Indexed = true
+		if err := writeJSONFile(statePath, state); err != nil {
+			return err
+		}
+	}
+	if !opt.skipAliases {
+		for _, alias := range manifest.Aliases {
+			if state.Aliases[alias] {
+				continue
+			}
+			if err := c.CreateAlias(ctx, opt.collName, alias); err != nil {
+				return fmt.Errorf("failed to create alias %s: %w", alias, err)
+			}
+			state.Aliases[alias] = true
+			if err := writeJSONFile(statePath, state); err != nil {
+				return err
+			}
+		}
+	}
+	return os.Remove(statePath)
+}
+
+// restoreSchema returns the schema to create, the dynamic field is created by server.
+func restoreSchema(sch *entity.Schema, collName string) *entity.Schema {
+	result := entity.NewSchema().WithName(collName).WithDescription(sch.Description).
+		WithAutoID(sch.AutoID).WithDynamicFieldEnabled(sch.EnableDynamicField)
+	for _, field := range sch.Fields {
+		if !field.IsDynamic {
+			result.WithField(field)
+		}
+	}
+	return result
+}
+
+func (c *GrpcClient) restoreCollection(ctx context.Context, manifest *BackupManifest, sch *entity.Schema) error {
+	has, err := c.HasCollection(ctx, sch.CollectionName)
+	if err != nil {
+		return err
+	}
+	if has {
+		return fmt.Errorf("collection %s already exists", sch.CollectionName)
+	}
+	if err := c.CreateCollection(ctx, sch, manifest.ShardNum, WithConsistencyLevel(manifest.ConsistencyLevel)); err != nil {
+		return err
+	}
+	for _, p := range manifest.Partitions {
+		if p.Name == "" || p.Name == defaultPartitionName {
+			continue
+		}
+		if err := c.CreatePartition(ctx, sch.CollectionName, p.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restorePartition inserts data files not restored yet, by BulkLoader or bulk insert.
+func (c *GrpcClient) restorePartition(ctx context.Context, dir string, part *BackupPartition, sch *entity.Schema,
+	state *restoreState, statePath string, opt restoreOpt) error {
+	var files []BackupFile
+	for _, file := range part.Files {
+		if !state.Done[file.Path] {
+			files = append(files, file)
+		}
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	// primary keys are not accepted by auto id collections
+	var skipped string
+	if pk := getPKField(sch); pk != nil && pk.AutoID {
+		skipped = pk.Name
+	}
+	read := func(file BackupFile) ([]entity.Column, error) {
+		columns, err := readBackupFile(dir, file)
+		if err != nil {
+			return nil, err
+		}
+		result := columns[:0]
+		for _, column := range columns {
+			if column.Name() != skipped {
+				result = append(result, column)
+			}
+		}
+		return result, nil
+	}
+
+	// partition key collections do not accept partition names in requests
+	partitionName := part.Name
+	if hasPartitionKey(sch) {
+		partitionName = ""
+	}
+	if opt.store != nil {
+		return c.importPartition(ctx, files, read, partitionName, sch, state, statePath, opt.store)
+	}
+	loaderOpts := append([]BulkLoaderOption{WithBulkPartition(partitionName)}, opt.loaderOpts...)
+	loader := NewBulkLoader(c, sch.CollectionName, loaderOpts...)
+	// upsert makes re-inserting a partially restored file idempotent, insert of auto id collection does not
+	load := loader.Upsert
+	if skipped != "" {
+		if state.Loading != "" {
+			return fmt.Errorf("restore of auto id collection %s was interrupted while inserting %s, "+
+				"drop the collection and restore again", sch.CollectionName, state.Loading)
+		}
+		load = loader.Insert
+	}
+	for _, file := range files {
+		columns, err := read(file)
+		if err != nil {
+			return err
+		}
+		if skipped != "" {
+			state.Loading = file.Path
+			if err := writeJSONFile(statePath, state); err != nil {
+				return err
+			}
+		}
+		if _, err := load(ctx, columns...); err != nil {
+			return err
+		}
+		state.Loading = ""
+		state.Done[file.Path] = true
+		if err := writeJSONFile(statePath, state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importPartition rewrites each data file as bulk insert files, imports the file groups not submitted yet
+// and waits until all tasks finish. Submitted tasks are recorded by data file and file group,
+// so that resuming waits for them instead of importing the rows again.
+func (c *GrpcClient) importPartition(ctx context.Context, files []BackupFile, read func(BackupFile) ([]entity.Column, error),
+	partName string, sch *entity.Schema, state *restoreState, statePath string, store ObjectStore) error {
+	tmp, err := os.MkdirTemp("", "milvus_restore")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	var keys []string
+	var taskIDs []int64
+	for i, file := range files {
+		groups, err := writeImportFiles(filepath.Join(tmp, fmt.Sprint(i)), sch, file, read)
+		if err != nil {
+			return err
+		}
+		for j, group := range groups {
+			key := fmt.Sprintf("%s#%d", file.Path, j)
+			taskID, ok := state.Tasks[key]
+			if !ok {
+				ids, err := ImportFiles(ctx, c, sch.CollectionName, partName, [][]string{group}, store)
+				if err != nil {
+					return err
+				}
+				taskID = ids[0]
+				state.Tasks[key] = taskID
+				if err := writeJSONFile(statePath, state); err != nil {
+					return err
+				}
+			}
+			keys = append(keys, key)
+			taskIDs = append(taskIDs, taskID)
+		}
+	}
+
+	results, err := WaitBulkInsert(ctx, c, taskIDs)
+	if err != nil {
+		// rows of failed tasks are cleaned by server, their file groups are imported again when resumed
+		for i, result := range results {
+			if result.Failed() {
+				delete(state.Tasks, keys[i])
+			}
+		}
+		if len(results) > 0 {
+			if werr := writeJSONFile(statePath, state); werr != nil {
+				return werr
+			}
+		}
+		return err
+	}
+	for _, key := range keys {
+		delete(state.Tasks, key)
+	}
+	for _, file := range files {
+		state.Done[file.Path] = true
+	}
+	return writeJSONFile(statePath, state)
+}
+
+// writeImportFiles writes rows of data file as bulk insert files in dir, returning the file groups.
+func writeImportFiles(dir string, sch *entity.Schema, file BackupFile, read func(BackupFile) ([]entity.Column, error)) ([][]string, error) {
+	columns, err := read(file)
+	if err != nil {
+		return nil, err
+	}
+	w, err := bulkwriter.NewWriter(sch, dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.AppendColumns(columns...); err != nil {
+		return nil, fmt.Errorf("failed to write bulk insert file of %s: %w", file.Path, err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return w.Files(), nil
+}
+
+func readRestoreState(path string) (*restoreState, error) {
+	state := &restoreState{}
+	bs, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(bs, state); err != nil {
+			return nil, fmt.Errorf("invalid restore state %s: %w", path, err)
+		}
+	}
+	if state.Done == nil {
+		state.Done = make(map[string]bool)
+	}
+	if state.Tasks == nil {
+		state.Tasks = make(map[string]int64)
+	}
+	if state.Aliases == nil {
+		state.Aliases = make(map[string]bool)
+	}
+	return state, nil
+}
@@ -1,6 +1,15 @@
 package client
 
-import "github.com/milvus-io/milvus-sdk-go/v2/entity"
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
 
 // SearchResult contains the result from Search api of client
 // IDs is the auto generated id values for the entities
@@ -26,3 +35,283 @@ func (rs ResultSet) GetColumn(fieldName string) entity.Column {
 	}
 	return nil
 }
+
+// Unmarshal puts result set rows into receiver, which shall be a pointer to slice of struct or struct pointer.
+// Struct fields are mapped to columns by the `name` setting of milvus tag or field name.
+// When dynamic field is in result set, values of unmapped fields are looked up from it
+// and the field tagged with `dynamic` receives all dynamic field data.
+func (rs ResultSet) Unmarshal(receiver interface{}) error {
+	return unmarshalRows(receiver, rs, nil, nil)
+}
+
+// Unmarshal puts search result entries into receiver, which shall be a pointer to slice of struct or struct pointer.
+// Besides the output fields, the field tagged with `primary_key` is filled with IDs
+// and the field tagged with `score` is filled with Scores.
+func (sr *SearchResult) Unmarshal(receiver interface{}) error {
+	if sr.Err != nil {
+		return sr.Err
+	}
+	return unmarshalRows(receiver, sr.Fields, sr.IDs, sr.Scores)
+}
+
+func unmarshalRows(receiver interface{}, columns []entity.Column, ids entity.Column, scores []float32) error {
+	rv := reflect.ValueOf(receiver)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("receiver must be a non-nil pointer to slice, got %T", receiver)
+	}
+	structType := rv.Elem().Type().Elem()
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	d, err := newRowDecoder(structType)
+	if err != nil {
+		return err
+	}
+	return unmarshalRowsWith(d, receiver, columns, ids, scores)
+}
+
+// unmarshalRowsWith decodes rows into receiver with provided decoder, receiver must be pointer to slice.
+func unmarshalRowsWith(d *rowDecoder, receiver interface{}, columns []entity.Column, ids entity.Column, scores []float32) error {
+	sv := reflect.ValueOf(receiver).Elem()
+	elemType := sv.Type().Elem()
+	structType := elemType
+	if elemType.Kind() == reflect.Ptr {
+		structType = elemType.Elem()
+	}
+
+	rowCount := -1
+	if ids != nil {
+		rowCount = ids.Len()
+	}
+	for _, column := range columns {
+		if rowCount >= 0 && column.Len() != rowCount {
+			return fmt.Errorf("column %s length %d not match row count %d", column.Name(), column.Len(), rowCount)
+		}
+		rowCount = column.Len()
+	}
+	if rowCount < 0 {
+		rowCount = 0
+	}
+
+	rows := reflect.MakeSlice(sv.Type(), rowCount, rowCount)
+	for i := 0; i < rowCount; i++ {
+		v := rows.Index(i)
+		if elemType.Kind() == reflect.Ptr {
+			v.Set(reflect.New(structType))
+			v = v.Elem()
+		}
+		if err := d.decode(v, columns, ids, scores, i); err != nil {
+			return err
+		}
+	}
+	sv.Set(rows)
+	return nil
+}
+
+// rowDecoder decodes column-based data into struct rows.
+// Struct fields are mapped to columns with the same rule as `entity.AnyToColumns`,
+// which uses the `name` setting of milvus tag if provided.
+type rowDecoder struct {
+	typ        reflect.Type
+	names      []string       // column names in struct field order
+	fields     map[string]int // column name to struct field index
+	pkIdx      int            // index of field tagged primary_key, -1 if not exist
+	dynamicIdx int            // index of field tagged dynamic, -1 if not exist
+	scoreIdx   int            // index of field tagged score, -1 if not exist
+}
+
+func newRowDecoder(t reflect.Type) (*rowDecoder, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("row type must be struct, got %v", t)
+	}
+	d := &rowDecoder{
+		typ:        t,
+		fields:     make(map[string]int),
+		pkIdx:      -1,
+		dynamicIdx: -1,
+		scoreIdx:   -1,
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous || !f.IsExported() {
+			continue
+		}
+		name := f.Name
+		if tag, ok := f.Tag.Lookup(entity.MilvusTag); ok {
+			if tag == entity.MilvusSkipTagValue {
+				continue
+			}
+			settings := entity.ParseTagSetting(tag, entity.MilvusTagSep)
+			if _, has := settings[entity.MilvusDynamicField]; has {
+				d.dynamicIdx = i
+				continue
+			}
+			if _, has := settings[entity.MilvusScore]; has {
+				d.scoreIdx = i
+				continue
+			}
+			if _, has := settings[entity.MilvusPrimaryKey]; has {
+				d.pkIdx = i
+			}
+			if fn, has := settings[entity.MilvusTagName]; has {
+				name = fn
+			}
+		}
+		if _, dup := d.fields[name]; dup {
+			return nil, fmt.Errorf("duplicated column name %s when parsing field %s", name, f.Name)
+		}
+		d.names = append(d.names, name)
+		d.fields[name] = i
+	}
+	return d, nil
+}
+
+// fieldNames returns the column names mapped by the row struct.
+func (d *rowDecoder) fieldNames() []string {
+	return d.names
+}
+
+// decode fills struct value `v` with the idx-th row of columns.
+// ids and scores are used for search result only, and could be nil.
+func (d *rowDecoder) decode(v reflect.Value, columns []entity.Column, ids entity.Column, scores []float32, idx int) error {
+	filled := make([]bool, d.typ.NumField())
+	if ids != nil && d.pkIdx >= 0 {
+		if err := assignColumnValue(v.Field(d.pkIdx), ids, idx); err != nil {
+			return fmt.Errorf("row %d field %s: %w", idx, d.typ.Field(d.pkIdx).Name, err)
+		}
+		filled[d.pkIdx] = true
+	}
+	if scores != nil && d.scoreIdx >= 0 {
+		f := v.Field(d.scoreIdx)
+		if f.Kind() != reflect.Float32 && f.Kind() != reflect.Float64 {
+			return fmt.Errorf("row %d field %s: %w: score field must be float", idx, d.typ.Field(d.scoreIdx).Name, ErrFieldTypeNotMatch)
+		}
+		f.SetFloat(float64(scores[idx]))
+	}
+
+	var meta *entity.ColumnJSONBytes
+	for _, column := range columns {
+		if c, ok := column.(*entity.ColumnJSONBytes); ok && c.IsDynamic() {
+			meta = c
+			continue
+		}
+		fidx, ok := d.fields[column.Name()]
+		if !ok {
+			continue
+		}
+		if err := assignColumnValue(v.Field(fidx), column, idx); err != nil {
+			return fmt.Errorf("row %d field %s: %w", idx, column.Name(), err)
+		}
+		filled[fidx] = true
+	}
+	if meta == nil {
+		return nil
+	}
+
+	raw, err := meta.ValueByIdx(idx)
+	if err != nil {
+		return fmt.Errorf("row %d field %s: %w", idx, meta.Name(), err)
+	}
+	if d.dynamicIdx >= 0 {
+		if err := assignJSON(v.Field(d.dynamicIdx), raw); err != nil {
+			return fmt.Errorf("row %d field %s: %w", idx, d.typ.Field(d.dynamicIdx).Name, err)
+		}
+	}
+	for _, name := range d.names {
+		fidx := d.fields[name]
+		if filled[fidx] {
+			continue
+		}
+		r := gjson.GetBytes(raw, gjsonEscape(name))
+		if !r.Exists() {
+			continue
+		}
+		if err := assignJSON(v.Field(fidx), []byte(r.Raw)); err != nil {
+			return fmt.Errorf("row %d field %s: %w", idx, name, err)
+		}
+	}
+	return nil
+}
+
+// gjsonEscape escapes gjson path special characters in key.
+func gjsonEscape(key string) string {
+	var sb strings.Builder
+	for _, r := range key {
+		switch r {
+		case '.', '*', '?', '|', '#', '@', '\\', '!', '=', '<', '>', '%':
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// assignColumnValue sets struct field `f` with the idx-th value of column.
+func assignColumnValue(f reflect.Value, column entity.Column, idx int) error {
+	val, err := column.Get(idx)
+	if err != nil {
+		// dynamic column does not have value for this row
+		if _, ok := column.(*entity.ColumnDynamic); ok {
+			return nil
+		}
+		return err
+	}
+
+	// json values are unmarshaled into struct field unless field is raw bytes or string
+	if column.Type() == entity.FieldTypeJSON {
+		switch v := val.(type) {
+		case []byte:
+			return assignJSON(f, v)
+		case string:
+			return assignJSON(f, []byte(v))
+		}
+	}
+
+	if f.Kind() == reflect.Ptr {
+		if f.IsNil() {
+			f.Set(reflect.New(f.Type().Elem()))
+		}
+		f = f.Elem()
+	}
+
+	rv := reflect.ValueOf(val)
+	switch {
+	case rv.Type().AssignableTo(f.Type()):
+		f.Set(rv)
+	case f.Kind() == reflect.Array && rv.Kind() == reflect.Slice:
+		if f.Len() != rv.Len() {
+			return fmt.Errorf("array length %d not match value length %d", f.Len(), rv.Len())
+		}
+		if rv.Type().Elem() != f.Type().Elem() {
+			return fmt.Errorf("%w: cannot set %v with %T", ErrFieldTypeNotMatch, f.Type(), val)
+		}
+		reflect.Copy(f, rv)
+	case f.Kind() == rv.Kind() && rv.Type().ConvertibleTo(f.Type()):
+		f.Set(rv.Convert(f.Type()))
+	default:
+		return fmt.Errorf("%w: cannot set %v with %T", ErrFieldTypeNotMatch, f.Type(), val)
+	}
+	return nil
+}
+
+// assignJSON sets struct field `f` with json value, raw bytes and string fields receive the json text as is.
+func assignJSON(f reflect.Value, raw []byte) error {
+	switch {
+	case f.Kind() == reflect.Slice && f.Type().Elem().Kind() == reflect.Uint8:
+		f.SetBytes(append([]byte(nil), raw...))
+		return nil
+	case f.Kind() == reflect.String:
+		// json string value is unquoted, other json text is kept as is
+		var str string
+		if err := json.Unmarshal(raw, &str); err == nil {
+			f.SetString(str)
+			return nil
+		}
+		f.SetString(string(raw))
+		return nil
+	}
+	return json.Unmarshal(raw, f.Addr().Interface())
+}
@@ -0,0 +1,595 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+// VectorEncoding is the vector value format used in exported files.
+type VectorEncoding int
+
+const (
+	// VectorEncodingJSON encodes vectors as json arrays, binary vectors are arrays of byte values.
+	VectorEncodingJSON VectorEncoding = iota
+	// VectorEncodingBase64 encodes vectors as base64 strings of the serialized bytes,
+	// float vectors are serialized in little endian, same as search placeholder.
+	VectorEncodingBase64
+)
+
+// Column names of search result entries in exported files.
+const (
+	ExportQueryField = "query" // index of the search vector
+	ExportIDField    = "id"    // id of the entry when ids column has no name
+	ExportScoreField = "score" // distance to the search vector
+)
+
+type exportOption struct {
+	vectorEncoding VectorEncoding
+	flattenJSON    bool
+	header         bool
+	delimiter      rune
+	fields         []string
+}
+
+// ExportOption is the option for ResultSet and SearchResult writers.
+type ExportOption func(*exportOption)
+
+// WithVectorEncoding sets the vector format, default is VectorEncodingJSON.
+func WithVectorEncoding(encoding VectorEncoding) ExportOption {
+	return func(opt *exportOption) {
+		opt.vectorEncoding = encoding
+	}
+}
+
+// WithFlattenJSON sets whether json values are flattened.
+// When enabled, keys of dynamic field become top level fields
+// and keys of other json fields become fields named `<field>.<key>`.
+func WithFlattenJSON(flatten bool) ExportOption {
+	return func(opt *exportOption) {
+		opt.flattenJSON = flatten
+	}
+}
+
+// WithCSVHeader sets whether header line is written, default is true.
+// Disable it when appending rows to an existing csv file.
+func WithCSVHeader(header bool) ExportOption {
+	return func(opt *exportOption) {
+		opt.header = header
+	}
+}
+
+// WithCSVDelimiter sets the csv field delimiter, default is ','.
+func WithCSVDelimiter(delimiter rune) ExportOption {
+	return func(opt *exportOption) {
+		opt.delimiter = delimiter
+	}
+}
+
+// WithExportFields sets the exported fields in order, which is the csv header.
+// Names are column names, or json keys named as WithFlattenJSON does when json is flattened.
+// Fields missing in a result set are written empty, other columns and json keys are skipped.
+func WithExportFields(names ...string) ExportOption {
+	return func(opt *exportOption) {
+		opt.fields = names
+	}
+}
+
+func newExportOption(opts []ExportOption) *exportOption {
+	opt := &exportOption{
+		vectorEncoding: VectorEncodingJSON,
+		header:         true,
+		delimiter:      ',',
+	}
+	for _, o := range opts {
+		o(opt)
+	}
+	return opt
+}
+
+// WriteCSV writes the result set into w in csv format.
+// Use CSVWriter to write result sets in batches, query iterator results for instance.
+func (rs ResultSet) WriteCSV(w io.Writer, opts ...ExportOption) error {
+	return writeCSV(w, []ResultSet{rs}, newExportOption(opts))
+}
+
+// WriteJSONL writes the result set into w in json lines format, one json object per row.
+// Use JSONLWriter to write result sets in batches.
+func (rs ResultSet) WriteJSONL(w io.Writer, opts ...ExportOption) error {
+	return writeJSONL(w, []ResultSet{rs}, newExportOption(opts))
+}
+
+// CSVWriter writes result sets into csv rows one batch after another,
+// so large results need not be kept in memory.
+// The header is planned from the first written result set unless set by WithExportFields,
+// then result sets with columns or flattened json keys out of the header are rejected.
+type CSVWriter struct {
+	cw      *csv.Writer
+	opt     *exportOption
+	names   []string // exported field names, nil until planned
+	planned bool     // names are planned from the first result set
+	started bool     // header is written
+	record  []string
+}
+
+// NewCSVWriter creates a CSVWriter writing into w.
+func NewCSVWriter(w io.Writer, opts ...ExportOption) *CSVWriter {
+	return newCSVWriter(w, newExportOption(opts))
+}
+
+func newCSVWriter(w io.Writer, opt *exportOption) *CSVWriter {
+	cw := csv.NewWriter(w)
+	cw.Comma = opt.delimiter
+	return &CSVWriter{
+		cw:    cw,
+		opt:   opt,
+		names: opt.fields,
+	}
+}
+
+// Write writes rows of the result set, the header is written before the first rows.
+func (w *CSVWriter) Write(rs ResultSet) error {
+	var fields []exportField
+	switch {
+	case w.names == nil:
+		fields = exportFields([]ResultSet{rs}, w.opt)
+		w.names = exportNames(fields)
+		w.planned = true
+	case w.planned:
+		if err := checkExportFields(exportFields([]ResultSet{rs}, w.opt), w.names); err != nil {
+			return err
+		}
+		fields = exportFieldsByName(rs, w.names, w.opt)
+	default:
+		fields = exportFieldsByName(rs, w.names, w.opt)
+	}
+	if !w.started {
+		w.started = true
+		w.record = make([]string, len(w.names))
+		if w.opt.header {
+			if err := w.cw.Write(w.names); err != nil {
+				return err
+			}
+		}
+	}
+	return walkExportRows([]ResultSet{rs}, fields, w.opt, func(cells []exportCell) error {
+		for i, cell := range cells {
+			w.record[i] = cell.text
+		}
+		return w.cw.Write(w.record)
+	})
+}
+
+// Flush writes buffered rows into the underlying writer.
+func (w *CSVWriter) Flush() error {
+	w.cw.Flush()
+	return w.cw.Error()
+}
+
+// JSONLWriter writes result sets into json lines one batch after another,
+// so large results need not be kept in memory.
+// Fields of each row are planned from its result set unless set by WithExportFields.
+type JSONLWriter struct {
+	bw  *bufio.Writer
+	opt *exportOption
+}
+
+// NewJSONLWriter creates a JSONLWriter writing into w.
+func NewJSONLWriter(w io.Writer, opts ...ExportOption) *JSONLWriter {
+	return newJSONLWriter(w, newExportOption(opts))
+}
+
+func newJSONLWriter(w io.Writer, opt *exportOption) *JSONLWriter {
+	return &JSONLWriter{
+		bw:  bufio.NewWriter(w),
+		opt: opt,
+	}
+}
+
+// Write writes rows of the result set, one json object per row.
+func (w *JSONLWriter) Write(rs ResultSet) error {
+	var fields []exportField
+	if w.opt.fields != nil {
+		fields = exportFieldsByName(rs, w.opt.fields, w.opt)
+	} else {
+		fields = exportFields([]ResultSet{rs}, w.opt)
+	}
+	keys := make([][]byte, 0, len(fields))
+	for _, field := range fields {
+		key, err := json.Marshal(field.name)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+	bw := w.bw
+	return walkExportRows([]ResultSet{rs}, fields, w.opt, func(cells []exportCell) error {
+		bw.WriteByte('{')
+		first := true
+		for i, cell := range cells {
+			// missing fields and flattened keys are omitted
+			if cell.json == nil || (!cell.present && fields[i].key != "") {
+				continue
+			}
+			if !first {
+				bw.WriteByte(',')
+			}
+			first = false
+			bw.Write(keys[i])
+			bw.WriteByte(':')
+			bw.Write(cell.json)
+		}
+		_, err := bw.WriteString("}\n")
+		return err
+	})
+}
+
+// Flush writes buffered rows into the underlying writer.
+func (w *JSONLWriter) Flush() error {
+	return w.bw.Flush()
+}
+
+// WriteSearchResultsCSV writes search results into w in csv format.
+// Each entry is written with the search vector index, id and score besides the output fields.
+func WriteSearchResultsCSV(w io.Writer, results []SearchResult, opts ...ExportOption) error {
+	sets, err := searchResultSets(results)
+	if err != nil {
+		return err
+	}
+	return writeCSV(w, sets, newExportOption(opts))
+}
+
+// WriteSearchResultsJSONL writes search results into w in json lines format.
+// Each entry is written with the search vector index, id and score besides the output fields.
+func WriteSearchResultsJSONL(w io.Writer, results []SearchResult, opts ...ExportOption) error {
+	sets, err := searchResultSets(results)
+	if err != nil {
+		return err
+	}
+	return writeJSONL(w, sets, newExportOption(opts))
+}
+
+// searchResultSets converts search results into result sets with query index, id and score columns.
+func searchResultSets(results []SearchResult) ([]ResultSet, error) {
+	sets := make([]ResultSet, 0, len(results))
+	for i, result := range results {
+		if result.Err != nil {
+			return nil, fmt.Errorf("result %d: %w", i, result.Err)
+		}
+		if result.IDs == nil {
+			return nil, fmt.Errorf("result %d: ids column is nil", i)
+		}
+		idName := result.IDs.Name()
+		if idName == "" {
+			idName = ExportIDField
+		}
+		var ids entity.Column
+		switch c := result.IDs.(type) {
+		case *entity.ColumnInt64:
+			ids = entity.NewColumnInt64(idName, c.Data())
+		case *entity.ColumnVarChar:
+			ids = entity.NewColumnVarChar(idName, c.Data())
+		case *entity.ColumnString:
+			ids = entity.NewColumnVarChar(idName, c.Data())
+		default:
+			return nil, fmt.Errorf("result %d: ids column type %s not supported", i, result.IDs.Type().Name())
+		}
+		query := make([]int64, ids.Len())
+		for j := range query {
+			query[j] = int64(i)
+		}
+		rs := ResultSet{
+			entity.NewColumnInt64(ExportQueryField, query),
+			ids,
+			entity.NewColumnFloat(ExportScoreField, result.Scores),
+		}
+		for _, column := range result.Fields {
+			// primary key is output as ids already
+			if column.Name() == idName {
+				continue
+			}
+			rs = append(rs, column)
+		}
+		sets = append(sets, rs)
+	}
+	return sets, nil
+}
+
+// exportField is one field of exported rows.
+type exportField struct {
+	name   string // exported field name
+	column string // source column name
+	key    string // json key in source column when flattened
+	found  bool   // source column is in the result set
+}
+
+// exportFields plans exported fields from columns of all result sets in order of appearance,
+// flattened json keys are collected from all result sets in sorted order.
+func exportFields(sets []ResultSet, opt *exportOption) []exportField {
+	names := make(map[string]struct{})
+	var columns []entity.Column
+	for _, rs := range sets {
+		for _, column := range rs {
+			if _, ok := names[column.Name()]; ok {
+				continue
+			}
+			names[column.Name()] = struct{}{}
+			columns = append(columns, column)
+		}
+	}
+	var fields []exportField
+	for _, column := range columns {
+		jc, ok := column.(*entity.ColumnJSONBytes)
+		if !ok || !opt.flattenJSON {
+			fields = append(fields, exportField{name: column.Name(), column: column.Name(), found: true})
+			continue
+		}
+		for _, key := range jsonKeys(sets, jc.Name()) {
+			name := key
+			if !jc.IsDynamic() {
+				name = jc.Name() + "." + key
+			}
+			if _, ok := names[name]; ok {
+				continue
+			}
+			names[name] = struct{}{}
+			fields = append(fields, exportField{name: name, column: jc.Name(), key: key, found: true})
+		}
+	}
+	return fields
+}
+
+// exportFieldsByName resolves exported field names against columns of the result set,
+// names matching no column nor flattened json key are exported as missing values.
+func exportFieldsByName(rs ResultSet, names []string, opt *exportOption) []exportField {
+	fields := make([]exportField, 0, len(names))
+	for _, name := range names {
+		field := exportField{name: name}
+		if rs.GetColumn(name) != nil {
+			field.column, field.found = name, true
+		} else if opt.flattenJSON {
+			var dynamic string
+			var hasDynamic bool
+			for _, column := range rs {
+				jc, ok := column.(*entity.ColumnJSONBytes)
+				if !ok {
+					continue
+				}
+				if jc.IsDynamic() {
+					dynamic, hasDynamic = jc.Name(), true
+					continue
+				}
+				if strings.HasPrefix(name, jc.Name()+".") {
+					field.column, field.key, field.found = jc.Name(), strings.TrimPrefix(name, jc.Name()+"."), true
+					break
+				}
+			}
+			if !field.found && hasDynamic {
+				field.column, field.key, field.found = dynamic, name, true
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// checkExportFields returns error when fields are not all in names.
+func checkExportFields(fields []exportField, names []string) error {
+	known := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		known[name] = struct{}{}
+	}
+	for _, field := range fields {
+		if _, ok := known[field.name]; !ok {
+			return fmt.Errorf("field %s is not in the csv header planned from the first result set, set all fields with WithExportFields", field.name)
+		}
+	}
+	return nil
+}
+
+func exportNames(fields []exportField) []string {
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		names = append(names, field.name)
+	}
+	return names
+}
+
+func jsonKeys(sets []ResultSet, columnName string) []string {
+	seen := make(map[string]struct{})
+	var keys []string
+	for _, rs := range sets {
+		jc, ok := rs.GetColumn(columnName).(*entity.ColumnJSONBytes)
+		if !ok {
+			continue
+		}
+		for _, bs := range jc.Data() {
+			gjson.ParseBytes(bs).ForEach(func(key, _ gjson.Result) bool {
+				if _, ok := seen[key.String()]; !ok {
+					seen[key.String()] = struct{}{}
+					keys = append(keys, key.String())
+				}
+				return true
+			})
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// exportCell is a field value of one row, in both csv and json representations.
+type exportCell struct {
+	text    string
+	json    []byte
+	present bool
+}
+
+func exportValue(column entity.Column, field exportField, idx int, opt *exportOption) (exportCell, error) {
+	if field.key != "" {
+		bs, err := column.(*entity.ColumnJSONBytes).ValueByIdx(idx)
+		if err != nil {
+			return exportCell{}, err
+		}
+		return jsonCell(gjson.GetBytes(bs, gjsonEscape(field.key))), nil
+	}
+
+	switch c := column.(type) {
+	case *entity.ColumnDynamic:
+		bs, err := c.ValueByIdx(idx)
+		if err != nil {
+			return exportCell{}, err
+		}
+		return jsonCell(gjson.GetBytes(bs, gjsonEscape(c.Name()))), nil
+	case *entity.ColumnJSONBytes:
+		bs, err := c.ValueByIdx(idx)
+		if err != nil {
+			return exportCell{}, err
+		}
+		if len(bs) == 0 {
+			return exportCell{json: []byte("null")}, nil
+		}
+		return exportCell{text: string(bs), json: bs, present: true}, nil
+	case *entity.ColumnFloatVector:
+		v, err := c.ValueByIdx(idx)
+		if err != nil {
+			return exportCell{}, err
+		}
+		if opt.vectorEncoding == VectorEncodingBase64 {
+			return stringCell(base64.StdEncoding.EncodeToString(entity.FloatVector(v).Serialize())), nil
+		}
+		return marshalCell(v)
+	case *entity.ColumnBinaryVector:
+		v, err := c.ValueByIdx(idx)
+		if err != nil {
+			return exportCell{}, err
+		}
+		if opt.vectorEncoding == VectorEncodingBase64 {
+			return stringCell(base64.StdEncoding.EncodeToString(v)), nil
+		}
+		// json encodes []byte as base64 string, convert into number array
+		values := make([]int, 0, len(v))
+		for _, b := range v {
+			values = append(values, int(b))
+		}
+		return marshalCell(values)
+	}
+
+	v, err := column.Get(idx)
+	if err != nil {
+		return exportCell{}, err
+	}
+	cell, err := marshalCell(v)
+	if err != nil {
+		return exportCell{}, err
+	}
+	switch v := v.(type) {
+	case string:
+		cell.text = v
+	case float32:
+		cell.text = strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		cell.text = strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return cell, nil
+}
+
+func jsonCell(r gjson.Result) exportCell {
+	if !r.Exists() {
+		return exportCell{json: []byte("null")}
+	}
+	text := r.Raw
+	if r.Type == gjson.String {
+		text = r.Str
+	}
+	return exportCell{text: text, json: []byte(r.Raw), present: true}
+}
+
+func stringCell(s string) exportCell {
+	bs, _ := json.Marshal(s)
+	return exportCell{text: s, json: bs, present: true}
+}
+
+func marshalCell(v interface{}) (exportCell, error) {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return exportCell{}, err
+	}
+	return exportCell{text: string(bs), json: bs, present: true}, nil
+}
+
+func writeCSV(w io.Writer, sets []ResultSet, opt *exportOption) error {
+	cw := newCSVWriter(w, opt)
+	if cw.names == nil {
+		cw.names = exportNames(exportFields(sets, opt))
+	}
+	for _, rs := range sets {
+		if err := cw.Write(rs); err != nil {
+			return err
+		}
+	}
+	return cw.Flush()
+}
+
+func writeJSONL(w io.Writer, sets []ResultSet, opt *exportOption) error {
+	jw := newJSONLWriter(w, opt)
+	for _, rs := range sets {
+		if err := jw.Write(rs); err != nil {
+			return err
+		}
+	}
+	return jw.Flush()
+}
+
+// walkExportRows calls fn with cells of each row, cells slice is reused between calls.
+func walkExportRows(sets []ResultSet, fields []exportField, opt *exportOption, fn func([]exportCell) error) error {
+	cells := make([]exportCell, len(fields))
+	for _, rs := range sets {
+		columns := make([]entity.Column, 0, len(fields))
+		for _, field := range fields {
+			var column entity.Column
+			if field.found {
+				column = rs.GetColumn(field.column)
+			}
+			columns = append(columns, column)
+		}
+		for idx := 0; idx < rs.Len(); idx++ {
+			for i, field := range fields {
+				// field missing in the result set
+				if columns[i] == nil {
+					cells[i] = exportCell{}
+					continue
+				}
+				cell, err := exportValue(columns[i], field, idx, opt)
+				if err != nil {
+					return fmt.Errorf("row %d field %s: %w", idx, field.name, err)
+				}
+				cells[i] = cell
+			}
+			if err := fn(cells); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+func exportResultSet() ResultSet {
+	return ResultSet{
+		entity.NewColumnInt64("id", []int64{1, 2}),
+		entity.NewColumnVarChar("title", []string{"a,b", "c"}),
+		entity.NewColumnFloat("rating", []float32{1.5, 2}),
+		entity.NewColumnFloatVector("vector", 2, [][]float32{{1, 0}, {0.5, 0.25}}),
+		entity.NewColumnBinaryVector("bits", 8, [][]byte{{1}, {255}}),
+		entity.NewColumnJSONBytes("extra", [][]byte{[]byte(`{"a":1}`), []byte(`{"b":"x"}`)}),
+		entity.NewColumnJSONBytes("$meta", [][]byte{[]byte(`{"tag":"t1"}`), []byte(`{"year":2001}`)}).WithIsDynamic(true),
+	}
+}
+
+func TestResultSetWriteCSV(t *testing.T) {
+	rs := exportResultSet()
+
+	t.Run("default", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, rs.WriteCSV(buf))
+		assert.Equal(t, "id,title,rating,vector,bits,extra,$meta\n"+
+			`1,"a,b",1.5,"[1,0]",[1],"{""a"":1}","{""tag"":""t1""}"`+"\n"+
+			`2,c,2,"[0.5,0.25]",[255],"{""b"":""x""}","{""year"":2001}"`+"\n", buf.String())
+	})
+
+	t.Run("flatten_base64", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		projected, _ := rs.Project("id", "vector", "extra", "$meta")
+		err := projected.WriteCSV(buf, WithFlattenJSON(true), WithVectorEncoding(VectorEncodingBase64), WithCSVDelimiter('\t'))
+		require.NoError(t, err)
+		assert.Equal(t, "id\tvector\textra.a\textra.b\ttag\tyear\n"+
+			"1\tAACAPwAAAAA=\t1\t\tt1\t\n"+
+			"2\tAAAAPwAAgD4=\t\tx\t\t2001\n", buf.String())
+	})
+
+	t.Run("no_header", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		projected, _ := rs.Project("id")
+		require.NoError(t, projected.WriteCSV(buf, WithCSVHeader(false)))
+		assert.Equal(t, "1\n2\n", buf.String())
+	})
+}
+
+func TestResultSetWriteJSONL(t *testing.T) {
+	rs := exportResultSet()
+
+	t.Run("default", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, rs.WriteJSONL(buf))
+		assert.Equal(t, `{"id":1,"title":"a,b","rating":1.5,"vector":[1,0],"bits":[1],"extra":{"a":1},"$meta":{"tag":"t1"}}`+"\n"+
+			`{"id":2,"title":"c","rating":2,"vector":[0.5,0.25],"bits":[255],"extra":{"b":"x"},"$meta":{"year":2001}}`+"\n", buf.String())
+	})
+
+	t.Run("flatten", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		projected, _ := rs.Project("id", "bits", "$meta")
+		meta := projected[2].(*entity.ColumnJSONBytes)
+		projected = append(projected, entity.NewColumnDynamic(meta, "year"))
+		err := projected.WriteJSONL(buf, WithFlattenJSON(true), WithVectorEncoding(VectorEncodingBase64))
+		require.NoError(t, err)
+		assert.Equal(t, `{"id":1,"bits":"AQ==","tag":"t1","year":null}`+"\n"+
+			`{"id":2,"bits":"/w==","year":2001}`+"\n", buf.String())
+	})
+
+	t.Run("dynamic_key_escaped", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		meta := entity.NewColumnJSONBytes("$meta", [][]byte{[]byte(`{"a.b":1,"a":{"b":2}}`)}).WithIsDynamic(true)
+		rs := ResultSet{entity.NewColumnInt64("id", []int64{1}), entity.NewColumnDynamic(meta, "a.b")}
+		require.NoError(t, rs.WriteJSONL(buf))
+		assert.Equal(t, `{"id":1,"a.b":1}`+"\n", buf.String())
+	})
+}
+
+func TestWriteSearchResults(t *testing.T) {
+	results := []SearchResult{
+		{
+			ResultCount: 2,
+			IDs:         entity.NewColumnInt64("", []int64{1, 2}),
+			Fields:      ResultSet{entity.NewColumnVarChar("title", []string{"a", "b"})},
+			Scores:      []float32{0.5, 0.25},
+		},
+		{
+			ResultCount: 1,
+			IDs:         entity.NewColumnInt64("", []int64{3}),
+			Fields:      ResultSet{entity.NewColumnVarChar("title", []string{"c"})},
+			Scores:      []float32{0.75},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, WriteSearchResultsCSV(buf, results))
+	assert.Equal(t, "query,id,score,title\n0,1,0.5,a\n0,2,0.25,b\n1,3,0.75,c\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, WriteSearchResultsJSONL(buf, results[1:]))
+	assert.Equal(t, `{"query":0,"id":3,"score":0.75,"title":"c"}`+"\n", buf.String())
+
+	// fields first appearing in later results are exported too
+	results[1].Fields = append(results[1].Fields, entity.NewColumnInt64("year", []int64{2001}))
+	buf.Reset()
+	require.NoError(t, WriteSearchResultsCSV(buf, results))
+	assert.Equal(t, "query,id,score,title,year\n0,1,0.5,a,\n0,2,0.25,b,\n1,3,0.75,c,2001\n", buf.String())
+
+	results[1].Err = errors.New("mock error")
+	assert.Error(t, WriteSearchResultsCSV(buf, results))
+	assert.Error(t, WriteSearchResultsJSONL(buf, []SearchResult{{}}))
+}
+
+func TestCSVWriter(t *testing.T) {
+	batch := func(ids []int64, meta ...string) ResultSet {
+		values := make([][]byte, 0, len(meta))
+		for _, m := range meta {
+			values = append(values, []byte(m))
+		}
+		return ResultSet{
+			entity.NewColumnInt64("id", ids),
+			entity.NewColumnJSONBytes("$meta", values).WithIsDynamic(true),
+		}
+	}
+
+	t.Run("header_from_first_batch", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		w := NewCSVWriter(buf, WithFlattenJSON(true))
+		require.NoError(t, w.Write(batch([]int64{1}, `{"a":1}`)))
+		require.NoError(t, w.Write(batch([]int64{2}, `{}`)))
+		// keys out of the header are rejected
+		assert.Error(t, w.Write(batch([]int64{3}, `{"b":1}`)))
+		require.NoError(t, w.Flush())
+		assert.Equal(t, "id,a\n1,1\n2,\n", buf.String())
+	})
+
+	t.Run("export_fields", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		w := NewCSVWriter(buf, WithFlattenJSON(true), WithExportFields("id", "b", "missing"))
+		require.NoError(t, w.Write(batch([]int64{1}, `{"a":1}`)))
+		require.NoError(t, w.Write(batch([]int64{2}, `{"b":"x"}`)))
+		require.NoError(t, w.Flush())
+		assert.Equal(t, "id,b,missing\n1,,\n2,x,\n", buf.String())
+	})
+}
+
+func TestJSONLWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := NewJSONLWriter(buf)
+	require.NoError(t, w.Write(ResultSet{entity.NewColumnInt64("id", []int64{1})}))
+	require.NoError(t, w.Write(ResultSet{entity.NewColumnInt64("id", []int64{2}), entity.NewColumnVarChar("title", []string{"a"})}))
+	require.NoError(t, w.Flush())
+	assert.Equal(t, `{"id":1}`+"\n"+`{"id":2,"title":"a"}`+"\n", buf.String())
+
+	buf.Reset()
+	w = NewJSONLWriter(buf, WithExportFields("title", "missing"))
+	require.NoError(t, w.Write(ResultSet{entity.NewColumnInt64("id", []int64{2}), entity.NewColumnVarChar("title", []string{"a"})}))
+	require.NoError(t, w.Flush())
+	assert.Equal(t, `{"title":"a"}`+"\n", buf.String())
+}
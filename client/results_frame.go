@@ -0,0 +1,380 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/milvus-io/milvus-sdk-go/v2/expr"
+)
+
+// Len returns the row count of the result set.
+func (rs ResultSet) Len() int {
+	if len(rs) == 0 {
+		return 0
+	}
+	return rs[0].Len()
+}
+
+// Rows converts the result set into rows of field name to value.
+// Values are the same as Column.Get returns, except that dynamic values are decoded json values.
+// Keys of the dynamic field column are flattened into the row when not conflicting with other columns.
+func (rs ResultSet) Rows() []map[string]interface{} {
+	rows := make([]map[string]interface{}, 0, rs.Len())
+	for i := 0; i < rs.Len(); i++ {
+		rows = append(rows, rs.row(i))
+	}
+	return rows
+}
+
+func (rs ResultSet) row(idx int) map[string]interface{} {
+	row := make(map[string]interface{}, len(rs))
+	var metas []*entity.ColumnJSONBytes
+	for _, column := range rs {
+		switch c := column.(type) {
+		case *entity.ColumnDynamic:
+			raw, err := c.Get(idx)
+			if err != nil {
+				continue
+			}
+			row[c.Name()] = gjson.Parse(raw.(string)).Value()
+		case *entity.ColumnJSONBytes:
+			if c.IsDynamic() {
+				metas = append(metas, c)
+				continue
+			}
+			row[c.Name()], _ = c.Get(idx)
+		default:
+			row[c.Name()], _ = c.Get(idx)
+		}
+	}
+	for _, meta := range metas {
+		bs, err := meta.ValueByIdx(idx)
+		if err != nil {
+			continue
+		}
+		gjson.ParseBytes(bs).ForEach(func(key, value gjson.Result) bool {
+			if _, ok := row[key.String()]; !ok {
+				row[key.String()] = value.Value()
+			}
+			return true
+		})
+	}
+	return row
+}
+
+// Filter returns a new result set containing the rows which fn returns true for.
+// The row passed to fn is the same as Rows returns.
+func (rs ResultSet) Filter(fn func(row map[string]interface{}) bool) (ResultSet, error) {
+	indices := make([]int, 0, rs.Len())
+	for i := 0; i < rs.Len(); i++ {
+		if fn(rs.row(i)) {
+			indices = append(indices, i)
+		}
+	}
+	return rs.take(indices)
+}
+
+// Where returns a new result set containing the rows matching the boolean expression,
+// which is evaluated locally with the same semantics as the server filter.
+func (rs ResultSet) Where(expression string) (ResultSet, error) {
+	node, err := expr.Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+	indices := make([]int, 0, rs.Len())
+	for i := 0; i < rs.Len(); i++ {
+		ok, err := expr.Evaluate(node, rs.row(i))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			indices = append(indices, i)
+		}
+	}
+	return rs.take(indices)
+}
+
+// SortBy returns a new result set with rows sorted by the field in ascending order.
+// The field must be a scalar column, rows missing dynamic field value come first.
+func (rs ResultSet) SortBy(field string) (ResultSet, error) {
+	return rs.sortBy(field, false)
+}
+
+// SortByDesc returns a new result set with rows sorted by the field in descending order.
+func (rs ResultSet) SortByDesc(field string) (ResultSet, error) {
+	return rs.sortBy(field, true)
+}
+
+func (rs ResultSet) sortBy(field string, desc bool) (ResultSet, error) {
+	column := rs.GetColumn(field)
+	if column == nil {
+		return nil, fmt.Errorf("field %s not found in result set", field)
+	}
+	keys := make([]interface{}, column.Len())
+	for i := range keys {
+		key, err := sortKey(column, i)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = key
+	}
+	indices := make([]int, len(keys))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		if desc {
+			return compareKeys(keys[indices[j]], keys[indices[i]]) < 0
+		}
+		return compareKeys(keys[indices[i]], keys[indices[j]]) < 0
+	})
+	return rs.take(indices)
+}
+
+// Join returns the inner join result of two result sets on the field, like joining output fields
+// of two queries by primary key. Rows are ordered as in rs, columns of other which exist in rs are omitted.
+func (rs ResultSet) Join(other ResultSet, on string) (ResultSet, error) {
+	left, right := rs.GetColumn(on), other.GetColumn(on)
+	if left == nil || right == nil {
+		return nil, fmt.Errorf("join field %s not found in both result sets", on)
+	}
+	positions := make(map[interface{}][]int, right.Len())
+	for i := 0; i < right.Len(); i++ {
+		key, err := sortKey(right, i)
+		if err != nil {
+			return nil, err
+		}
+		if key == nil {
+			continue
+		}
+		positions[key] = append(positions[key], i)
+	}
+
+	var leftIndices, rightIndices []int
+	for i := 0; i < left.Len(); i++ {
+		key, err := sortKey(left, i)
+		if err != nil {
+			return nil, err
+		}
+		if key == nil {
+			continue
+		}
+		for _, j := range positions[key] {
+			leftIndices = append(leftIndices, i)
+			rightIndices = append(rightIndices, j)
+		}
+	}
+
+	result, err := rs.take(leftIndices)
+	if err != nil {
+		return nil, err
+	}
+	for _, column := range other {
+		if rs.GetColumn(column.Name()) != nil {
+			continue
+		}
+		taken, err := takeColumn(column, rightIndices)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, taken)
+	}
+	return result, nil
+}
+
+// Project returns a result set containing the provided fields only, in the provided order.
+// Columns are shared with the original result set.
+func (rs ResultSet) Project(fields ...string) (ResultSet, error) {
+	result := make(ResultSet, 0, len(fields))
+	for _, field := range fields {
+		column := rs.GetColumn(field)
+		if column == nil {
+			return nil, fmt.Errorf("field %s not found in result set", field)
+		}
+		result = append(result, column)
+	}
+	return result, nil
+}
+
+// take returns a new result set with rows at provided indices.
+func (rs ResultSet) take(indices []int) (ResultSet, error) {
+	result := make(ResultSet, 0, len(rs))
+	for _, column := range rs {
+		taken, err := takeColumn(column, indices)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, taken)
+	}
+	return result, nil
+}
+
+// slice returns a new result set with rows in [start, end).
+func (rs ResultSet) slice(start, end int) ResultSet {
+	result := make(ResultSet, 0, len(rs))
+	for _, column := range rs {
+		result = append(result, column.Slice(start, end))
+	}
+	return result
+}
+
+// concat returns a new result set with rows of other appended, columns are matched by name.
+// An empty result set is treated as having the columns of other.
+func (rs ResultSet) concat(other ResultSet) (ResultSet, error) {
+	if len(rs) == 0 {
+		return other, nil
+	}
+	result := make(ResultSet, 0, len(rs))
+	for _, column := range rs {
+		o := other.GetColumn(column.Name())
+		if o == nil {
+			return nil, fmt.Errorf("field %s not found in result set", column.Name())
+		}
+		merged, err := column.Concat(o)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, merged)
+	}
+	return result, nil
+}
+
+func takeColumn(column entity.Column, indices []int) (entity.Column, error) {
+	// dynamic column values are views of the underlying json column
+	if dc, ok := column.(*entity.ColumnDynamic); ok {
+		taken, err := takeColumn(dc.ColumnJSONBytes, indices)
+		if err != nil {
+			return nil, err
+		}
+		return entity.NewColumnDynamic(taken.(*entity.ColumnJSONBytes), dc.Name()), nil
+	}
+	taken := column.Slice(0, 0)
+	for _, idx := range indices {
+		v, err := column.Get(idx)
+		if err != nil {
+			return nil, err
+		}
+		if err := taken.AppendValue(v); err != nil {
+			return nil, fmt.Errorf("column %s: %w", column.Name(), err)
+		}
+	}
+	return taken, nil
+}
+
+// sortKey returns the comparable value of column at idx,
+// which is one of nil (missing dynamic value), bool, int64, float64 or string.
+func sortKey(column entity.Column, idx int) (interface{}, error) {
+	if dc, ok := column.(*entity.ColumnDynamic); ok {
+		raw, err := dc.Get(idx)
+		if err != nil {
+			return nil, nil
+		}
+		r := gjson.Parse(raw.(string))
+		switch r.Type {
+		case gjson.True, gjson.False:
+			return r.Bool(), nil
+		case gjson.Number:
+			return r.Float(), nil
+		case gjson.String:
+			return r.String(), nil
+		case gjson.Null:
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("dynamic field %s value %s is not comparable", dc.Name(), r.Raw)
+		}
+	}
+
+	v, err := column.Get(idx)
+	if err != nil {
+		return nil, err
+	}
+	switch v := v.(type) {
+	case bool:
+		return v, nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case string:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("field %s of type %s is not comparable", column.Name(), column.Type().Name())
+	}
+}
+
+// compareKeys compares sort keys, nil < bool < number < string.
+func compareKeys(a, b interface{}) int {
+	ra, rb := keyRank(a), keyRank(b)
+	if ra != rb {
+		return ra - rb
+	}
+	switch a := a.(type) {
+	case bool:
+		b := b.(bool)
+		if a == b {
+			return 0
+		}
+		if !a {
+			return -1
+		}
+		return 1
+	case int64:
+		if b, ok := b.(int64); ok {
+			return compareOrdered(a, b)
+		}
+		return compareOrdered(float64(a), b.(float64))
+	case float64:
+		if b, ok := b.(int64); ok {
+			return compareOrdered(a, float64(b))
+		}
+		return compareOrdered(a, b.(float64))
+	case string:
+		return compareOrdered(a, b.(string))
+	}
+	return 0
+}
+
+func keyRank(v interface{}) int {
+	switch v.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case int64, float64:
+		return 2
+	default:
+		return 3
+	}
+}
+
+func compareOrdered[T int64 | float64 | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,148 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+func frameResultSet() ResultSet {
+	meta := entity.NewColumnJSONBytes("$meta", [][]byte{
+		[]byte(`{"year": 2001, "tag": "x"}`),
+		[]byte(`{"tag": "y"}`),
+		[]byte(`{"year": 1999, "tag": "z"}`),
+	}).WithIsDynamic(true)
+	return ResultSet{
+		entity.NewColumnInt64("id", []int64{3, 1, 2}),
+		entity.NewColumnVarChar("title", []string{"c", "a", "b"}),
+		entity.NewColumnFloatVector("vector", 2, [][]float32{{3, 3}, {1, 1}, {2, 2}}),
+		entity.NewColumnDynamic(meta, "year"),
+		meta,
+	}
+}
+
+func TestResultSetRows(t *testing.T) {
+	rs := frameResultSet()
+	assert.Equal(t, 3, rs.Len())
+	assert.Equal(t, 0, ResultSet{}.Len())
+
+	rows := rs.Rows()
+	require.Len(t, rows, 3)
+	assert.EqualValues(t, 3, rows[0]["id"])
+	assert.Equal(t, "c", rows[0]["title"])
+	assert.Equal(t, []float32{3, 3}, rows[0]["vector"])
+	assert.EqualValues(t, 2001, rows[0]["year"])
+	assert.Equal(t, "x", rows[0]["tag"])
+	_, ok := rows[1]["year"]
+	assert.False(t, ok)
+	assert.Equal(t, "y", rows[1]["tag"])
+}
+
+func TestResultSetFilter(t *testing.T) {
+	rs := frameResultSet()
+	filtered, err := rs.Filter(func(row map[string]interface{}) bool {
+		return row["tag"] != "y"
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, filtered.Len())
+	assert.Equal(t, []int64{3, 2}, filtered.GetColumn("id").(*entity.ColumnInt64).Data())
+	assert.Equal(t, [][]float32{{3, 3}, {2, 2}}, filtered.GetColumn("vector").(*entity.ColumnFloatVector).Data())
+	year, err := filtered.GetColumn("year").GetAsInt64(1)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1999, year)
+	assert.True(t, filtered.GetColumn("$meta").(*entity.ColumnJSONBytes).IsDynamic())
+
+	// original result set is untouched
+	assert.Equal(t, 3, rs.Len())
+}
+
+func TestResultSetWhere(t *testing.T) {
+	rs := frameResultSet()
+	filtered, err := rs.Where(`year > 2000 or title like "b%"`)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 2}, filtered.GetColumn("id").(*entity.ColumnInt64).Data())
+
+	filtered, err = rs.Where(`$meta["tag"] in ["y", "z"] && id != 2`)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1}, filtered.GetColumn("id").(*entity.ColumnInt64).Data())
+
+	filtered, err = rs.Where(`not exists year`)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1}, filtered.GetColumn("id").(*entity.ColumnInt64).Data())
+
+	_, err = rs.Where(`id >`)
+	assert.Error(t, err)
+	_, err = rs.Where(`title`)
+	assert.Error(t, err)
+}
+
+func TestResultSetSortBy(t *testing.T) {
+	rs := frameResultSet()
+
+	sorted, err := rs.SortBy("id")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, sorted.GetColumn("id").(*entity.ColumnInt64).Data())
+	assert.Equal(t, []string{"a", "b", "c"}, sorted.GetColumn("title").(*entity.ColumnVarChar).Data())
+
+	sorted, err = rs.SortByDesc("title")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 2, 1}, sorted.GetColumn("id").(*entity.ColumnInt64).Data())
+
+	// missing dynamic value comes first
+	sorted, err = rs.SortBy("year")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, sorted.GetColumn("id").(*entity.ColumnInt64).Data())
+
+	_, err = rs.SortBy("vector")
+	assert.Error(t, err)
+	_, err = rs.SortBy("not_exist")
+	assert.Error(t, err)
+}
+
+func TestResultSetJoin(t *testing.T) {
+	rs := frameResultSet()
+	other := ResultSet{
+		entity.NewColumnInt64("id", []int64{1, 3, 4}),
+		entity.NewColumnVarChar("title", []string{"other_a", "other_c", "other_d"}),
+		entity.NewColumnFloat("price", []float32{1.5, 3.5, 4.5}),
+	}
+
+	joined, err := rs.Join(other, "id")
+	require.NoError(t, err)
+	require.Equal(t, 2, joined.Len())
+	assert.Len(t, joined, 6)
+	assert.Equal(t, []int64{3, 1}, joined.GetColumn("id").(*entity.ColumnInt64).Data())
+	assert.Equal(t, []string{"c", "a"}, joined.GetColumn("title").(*entity.ColumnVarChar).Data())
+	assert.Equal(t, []float32{3.5, 1.5}, joined.GetColumn("price").(*entity.ColumnFloat).Data())
+
+	_, err = rs.Join(other, "year")
+	assert.Error(t, err)
+	_, err = rs.Join(ResultSet{entity.NewColumnFloatVector("vector", 2, nil)}, "vector")
+	assert.Error(t, err)
+}
+
+func TestResultSetProject(t *testing.T) {
+	rs := frameResultSet()
+	projected, err := rs.Project("title", "id")
+	require.NoError(t, err)
+	require.Len(t, projected, 2)
+	assert.Equal(t, "title", projected[0].Name())
+	assert.Equal(t, "id", projected[1].Name())
+
+	_, err = rs.Project("not_exist")
+	assert.Error(t, err)
+}
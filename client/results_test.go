@@ -0,0 +1,131 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+type resultRow struct {
+	ID      int64                  `milvus:"name:id;primary_key"`
+	Title   string                 `milvus:"name:title"`
+	Vector  []float32              `milvus:"name:vector"`
+	Year    int32                  `milvus:"name:year"`
+	Rating  *float64               `milvus:"name:rating"`
+	Score   float32                `milvus:"score"`
+	Dynamic map[string]interface{} `milvus:"dynamic"`
+	Ignored string                 `milvus:"-"`
+}
+
+func TestResultSetUnmarshal(t *testing.T) {
+	rs := ResultSet{
+		entity.NewColumnInt64("id", []int64{1, 2}),
+		entity.NewColumnVarChar("title", []string{"a", "b"}),
+		entity.NewColumnFloatVector("vector", 2, [][]float32{{0.1, 0.2}, {0.3, 0.4}}),
+		entity.NewColumnJSONBytes("$meta", [][]byte{
+			[]byte(`{"year": 1999, "rating": 8.5, "tag": "x"}`),
+			[]byte(`{"tag": "y"}`),
+		}).WithIsDynamic(true),
+	}
+
+	t.Run("slice_of_struct", func(t *testing.T) {
+		var rows []resultRow
+		require.NoError(t, rs.Unmarshal(&rows))
+		require.Len(t, rows, 2)
+
+		assert.EqualValues(t, 1, rows[0].ID)
+		assert.Equal(t, "a", rows[0].Title)
+		assert.Equal(t, []float32{0.1, 0.2}, rows[0].Vector)
+		assert.EqualValues(t, 1999, rows[0].Year)
+		require.NotNil(t, rows[0].Rating)
+		assert.Equal(t, 8.5, *rows[0].Rating)
+		assert.Equal(t, "x", rows[0].Dynamic["tag"])
+		assert.EqualValues(t, 0, rows[0].Score)
+
+		assert.EqualValues(t, 2, rows[1].ID)
+		assert.EqualValues(t, 0, rows[1].Year)
+		assert.Nil(t, rows[1].Rating)
+		assert.Equal(t, "y", rows[1].Dynamic["tag"])
+	})
+
+	t.Run("slice_of_pointer", func(t *testing.T) {
+		var rows []*resultRow
+		require.NoError(t, rs.Unmarshal(&rows))
+		require.Len(t, rows, 2)
+		assert.Equal(t, "b", rows[1].Title)
+	})
+
+	t.Run("dynamic_column", func(t *testing.T) {
+		meta := entity.NewColumnJSONBytes("$meta", [][]byte{[]byte(`{"year": 2001}`)}).WithIsDynamic(true)
+		var rows []resultRow
+		require.NoError(t, ResultSet{entity.NewColumnDynamic(meta, "year")}.Unmarshal(&rows))
+		require.Len(t, rows, 1)
+		assert.EqualValues(t, 2001, rows[0].Year)
+	})
+
+	t.Run("invalid_receiver", func(t *testing.T) {
+		var rows []resultRow
+		assert.Error(t, rs.Unmarshal(rows))
+		assert.Error(t, rs.Unmarshal(nil))
+		var ints []int64
+		assert.Error(t, rs.Unmarshal(&ints))
+	})
+
+	t.Run("type_not_match", func(t *testing.T) {
+		type badRow struct {
+			Title int64 `milvus:"name:title"`
+		}
+		var rows []badRow
+		err := rs.Unmarshal(&rows)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrFieldTypeNotMatch)
+		assert.Contains(t, err.Error(), "row 0 field title")
+	})
+
+	t.Run("length_not_match", func(t *testing.T) {
+		var rows []resultRow
+		err := ResultSet{
+			entity.NewColumnInt64("id", []int64{1, 2}),
+			entity.NewColumnVarChar("title", []string{"a"}),
+		}.Unmarshal(&rows)
+		assert.Error(t, err)
+	})
+}
+
+func TestSearchResultUnmarshal(t *testing.T) {
+	sr := &SearchResult{
+		ResultCount: 2,
+		IDs:         entity.NewColumnInt64("", []int64{10, 20}),
+		Fields: ResultSet{
+			entity.NewColumnVarChar("title", []string{"a", "b"}),
+		},
+		Scores: []float32{0.5, 0.25},
+	}
+
+	var rows []resultRow
+	require.NoError(t, sr.Unmarshal(&rows))
+	require.Len(t, rows, 2)
+	assert.EqualValues(t, 10, rows[0].ID)
+	assert.Equal(t, "a", rows[0].Title)
+	assert.EqualValues(t, 0.5, rows[0].Score)
+	assert.EqualValues(t, 20, rows[1].ID)
+	assert.EqualValues(t, 0.25, rows[1].Score)
+
+	sr.Err = errors.New("mock error")
+	assert.Error(t, sr.Unmarshal(&rows))
+}
@@ -76,52 +76,82 @@ func (c *GrpcClient) InsertRows(ctx context.Context, collName string, partitionN
 	if c.Service == nil {
 		return nil, ErrClientNotReady
 	}
-	if len(rows) == 0 {
-		return nil, errors.New("empty rows provided")
+	columns, err := c.rowsToColumns(ctx, collName, partitionName, rows)
+	if err != nil {
+		return nil, err
 	}
+	return c.Insert(ctx, collName, partitionName, columns...)
+}
 
-	if err := c.checkCollectionExists(ctx, collName); err != nil {
+// UpsertRows allows upsert with row based data, rows are converted the same as InsertRows.
+func (c *GrpcClient) UpsertRows(ctx context.Context, collName string, partitionName string,
+	rows []interface{}) (entity.Column, error) {
+	if c.Service == nil {
+		return nil, ErrClientNotReady
+	}
+	columns, err := c.rowsToColumns(ctx, collName, partitionName, rows)
+	if err != nil {
 		return nil, err
 	}
-	if partitionName != "" {
-		if err := c.checkPartitionExists(ctx, collName, partitionName); err != nil {
-			return nil, err
-		}
+	return c.Upsert(ctx, collName, partitionName, columns...)
+}
+
+// DeleteRows deletes the entities of rows by primary key.
+// The primary key is read from the field mapped to the primary key of collection,
+// by go field name or the name in tag, like other fields of InsertRows.
+func (c *GrpcClient) DeleteRows(ctx context.Context, collName string, partitionName string, rows []interface{}) error {
+	if c.Service == nil {
+		return ErrClientNotReady
+	}
+	if len(rows) == 0 {
+		return errors.New("empty rows provided")
 	}
 	coll, err := c.DescribeCollection(ctx, collName)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	// 1. convert rows to columns
-	columns, err := entity.AnyToColumns(rows, coll.Schema)
-	if err != nil {
-		return nil, err
+	pk := getPKField(coll.Schema)
+	if pk == nil {
+		return fmt.Errorf("collection %s has no primary key", collName)
 	}
-	//fieldData
-	// 2. do insert request
-	req := &milvuspb.InsertRequest{
-		DbName:         "", // reserved
-		CollectionName: collName,
-		PartitionName:  partitionName,
+	// rows are converted with schema of primary key only, other fields are ignored
+	field := entity.NewField().WithName(pk.Name).WithDataType(pk.DataType).WithIsPrimaryKey(true)
+	columns, err := entity.AnyToColumns(rows, entity.NewSchema().WithName(collName).WithField(field))
+	if err != nil {
+		return err
 	}
-	//c.Service.
-	if req.PartitionName == "" {
-		req.PartitionName = "_default" // use default partition
+
+	var ids entity.Column
+	switch column := columns[0].(type) {
+	case *entity.ColumnInt64:
+		ids = entity.NewColumnInt64(pk.Name, column.Data())
+	case *entity.ColumnVarChar:
+		ids = entity.NewColumnVarChar(pk.Name, column.Data())
+	default:
+		return fmt.Errorf("unsupported primary key type %s", pk.DataType.Name())
 	}
-	req.NumRows = uint32(len(rows))
-	for _, column := range columns {
-		req.FieldsData = append(req.FieldsData, column.FieldData())
+	return c.DeleteByPks(ctx, collName, partitionName, ids)
+}
+
+// rowsToColumns validates collection and partition, then converts rows to columns with collection schema.
+func (c *GrpcClient) rowsToColumns(ctx context.Context, collName string, partitionName string, rows []interface{}) ([]entity.Column, error) {
+	if len(rows) == 0 {
+		return nil, errors.New("empty rows provided")
 	}
-	resp, err := c.Service.Insert(ctx, req)
-	if err != nil {
+
+	if err := c.checkCollectionExists(ctx, collName); err != nil {
 		return nil, err
 	}
-	if err := handleRespStatus(resp.GetStatus()); err != nil {
+	if partitionName != "" {
+		if err := c.checkPartitionExists(ctx, collName, partitionName); err != nil {
+			return nil, err
+		}
+	}
+	coll, err := c.DescribeCollection(ctx, collName)
+	if err != nil {
 		return nil, err
 	}
-	MetaCache.setSessionTs(collName, resp.Timestamp)
-	// 3. parse id column
-	return entity.IDColumns(resp.GetIDs(), 0, -1)
+	return entity.AnyToColumns(rows, coll.Schema)
 }
 
 // SearchResultByRows search result for row-based Search
@@ -133,6 +163,8 @@ type SearchResultByRows struct {
 }
 
 // SearchResultToRows converts search result proto to rows
+//
+// Deprecated: fields are mapped by go field name only, use SearchResult.Unmarshal instead.
 func SearchResultToRows(sch *entity.Schema, results *schemapb.SearchResultData, t reflect.Type, _ map[string]struct{}) ([]SearchResultByRows, error) {
 	var err error
 	offset := 0
@@ -316,6 +316,125 @@ func TestInsertByRows(t *testing.T) {
 	suite.Run(t, new(InsertByRowsSuite))
 }
 
+type UpsertDeleteRowsSuite struct {
+	MockSuiteBase
+}
+
+func (s *UpsertDeleteRowsSuite) TestUpsertRows() {
+	partName := "part_1"
+	c := s.client
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.Run("dynamic", func() {
+		defer s.resetMock()
+		s.setupHasCollection(testCollectionName)
+		s.setupHasPartition(testCollectionName, partName)
+		s.setupDescribeCollection(testCollectionName, entity.NewSchema().
+			WithName(testCollectionName).WithDynamicFieldEnabled(true).
+			WithField(entity.NewField().WithName("ID").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true)).
+			WithField(entity.NewField().WithName("Vector").WithDataType(entity.FieldTypeFloatVector).WithTypeParams(entity.TypeParamDim, "4")),
+		)
+
+		s.mock.EXPECT().Upsert(mock.Anything, mock.AnythingOfType("*milvuspb.UpsertRequest")).
+			Run(func(_ context.Context, req *milvuspb.UpsertRequest) {
+				s.Equal(testCollectionName, req.GetCollectionName())
+				s.Equal(partName, req.GetPartitionName())
+				s.EqualValues(2, req.GetNumRows())
+				s.Equal(3, len(req.GetFieldsData()))
+				for _, fd := range req.GetFieldsData() {
+					if fd.GetIsDynamic() {
+						s.JSONEq(`{"Extra":"a"}`, string(fd.GetScalars().GetJsonData().GetData()[0]))
+					}
+				}
+			}).Return(&milvuspb.MutationResult{
+			Status: &commonpb.Status{ErrorCode: commonpb.ErrorCode_Success},
+			IDs:    &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1, 2}}}},
+		}, nil)
+		type row struct {
+			ID     int64 `milvus:"primary_key"`
+			Vector []float32
+			Extra  string
+		}
+		ids, err := c.UpsertRows(ctx, testCollectionName, partName, []interface{}{
+			row{ID: 1, Vector: make([]float32, 4), Extra: "a"},
+			&row{ID: 2, Vector: make([]float32, 4), Extra: "b"},
+		})
+		s.NoError(err)
+		s.Equal(2, ids.Len())
+	})
+
+	s.Run("fail", func() {
+		defer s.resetMock()
+		_, err := c.UpsertRows(ctx, testCollectionName, partName, nil)
+		s.Error(err)
+
+		s.setupHasCollection()
+		_, err = c.UpsertRows(ctx, testCollectionName, "", []interface{}{struct{ ID int64 }{ID: 1}})
+		s.Error(err)
+	})
+}
+
+func (s *UpsertDeleteRowsSuite) TestDeleteRows() {
+	c := s.client
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	setup := func(pk *entity.Field) {
+		s.setupHasCollection(testCollectionName)
+		s.setupDescribeCollection(testCollectionName, entity.NewSchema().WithName(testCollectionName).
+			WithField(pk).
+			WithField(entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithTypeParams(entity.TypeParamDim, "4")),
+		)
+	}
+
+	s.Run("tagged_pk", func() {
+		defer s.resetMock()
+		setup(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true).WithIsAutoID(true))
+		s.mock.EXPECT().Delete(mock.Anything, mock.AnythingOfType("*milvuspb.DeleteRequest")).
+			Run(func(_ context.Context, req *milvuspb.DeleteRequest) {
+				s.Equal(testCollectionName, req.GetCollectionName())
+				s.Equal("id in [1,2]", req.GetExpr())
+			}).Return(&milvuspb.MutationResult{Status: getSuccessStatus()}, nil)
+
+		// primary key field mapped by tag name, vector is not required
+		type row struct {
+			Key    int64 `milvus:"name:id;primary_key"`
+			Vector []float32
+		}
+		err := c.DeleteRows(ctx, testCollectionName, "", []interface{}{row{Key: 1}, &row{Key: 2}})
+		s.NoError(err)
+	})
+
+	s.Run("map_rows", func() {
+		defer s.resetMock()
+		setup(entity.NewField().WithName("pk").WithDataType(entity.FieldTypeVarChar).WithIsPrimaryKey(true).WithMaxLength(16))
+		s.mock.EXPECT().Delete(mock.Anything, mock.AnythingOfType("*milvuspb.DeleteRequest")).
+			Run(func(_ context.Context, req *milvuspb.DeleteRequest) {
+				s.Equal(`pk in ["a","b"]`, req.GetExpr())
+			}).Return(&milvuspb.MutationResult{Status: getSuccessStatus()}, nil)
+
+		err := c.DeleteRows(ctx, testCollectionName, "", []interface{}{
+			map[string]interface{}{"pk": "a"},
+			map[string]interface{}{"pk": "b", "other": 1},
+		})
+		s.NoError(err)
+	})
+
+	s.Run("fail", func() {
+		defer s.resetMock()
+		s.Error(c.DeleteRows(ctx, testCollectionName, "", nil))
+
+		setup(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true))
+		s.Error(c.DeleteRows(ctx, testCollectionName, "", []interface{}{map[string]interface{}{"other": 1}}), "pk missing")
+		s.Error(c.DeleteRows(ctx, testCollectionName, "", []interface{}{map[string]interface{}{"id": "a"}}), "pk type mismatch")
+	})
+}
+
+func TestUpsertDeleteRows(t *testing.T) {
+	suite.Run(t, new(UpsertDeleteRowsSuite))
+}
+
 func TestSearchResultToRows(t *testing.T) {
 	t.Run("successful test cases", func(t *testing.T) {
 		sr := &schemapb.SearchResultData{
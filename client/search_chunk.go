@@ -0,0 +1,130 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+const (
+	defaultSearchChunkNq          = 16384
+	defaultSearchChunkBytes       = 64 << 20
+	defaultSearchChunkConcurrency = 4
+)
+
+// searchChunk is the vectors in [start, end) searched in one request.
+type searchChunk struct {
+	start, end int
+}
+
+// searchChunks splits vectors into chunks by option and searches them with bounded concurrency,
+// results are reassembled in the order of vectors.
+// Results of vectors in failed chunks carry the chunk error, error is returned only if all chunks fail.
+func searchChunks(ctx context.Context, vectors []entity.Vector, option *SearchQueryOption,
+	search func(vectors []entity.Vector) ([]SearchResult, error)) ([]SearchResult, error) {
+	maxNq, maxBytes, concurrency := option.ChunkNq, option.ChunkBytes, option.ChunkConcurrency
+	if maxNq <= 0 {
+		maxNq = defaultSearchChunkNq
+	}
+	if maxBytes <= 0 {
+		maxBytes = defaultSearchChunkBytes
+	}
+	if concurrency <= 0 {
+		concurrency = defaultSearchChunkConcurrency
+	}
+	chunks := splitSearchVectors(vectors, maxNq, maxBytes)
+	if len(chunks) <= 1 {
+		return search(vectors)
+	}
+
+	results := make([]SearchResult, len(vectors))
+	errs := make([]error, len(chunks))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(i int, chunk searchChunk) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			rs, err := search(vectors[chunk.start:chunk.end])
+			if err == nil && len(rs) != chunk.end-chunk.start {
+				err = fmt.Errorf("expected %d results, got %d", chunk.end-chunk.start, len(rs))
+			}
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			copy(results[chunk.start:chunk.end], rs)
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	failed := 0
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		failed++
+		chunk := chunks[i]
+		err = fmt.Errorf("search chunk %d of vectors [%d, %d) failed: %w", i, chunk.start, chunk.end, err)
+		if failed == len(chunks) {
+			return nil, err
+		}
+		for j := chunk.start; j < chunk.end; j++ {
+			results[j] = SearchResult{Err: err}
+		}
+	}
+	return results, nil
+}
+
+// splitSearchVectors splits vectors into chunks of at most maxNq vectors and maxBytes payload,
+// a vector larger than maxBytes is searched alone.
+func splitSearchVectors(vectors []entity.Vector, maxNq, maxBytes int) []searchChunk {
+	var chunks []searchChunk
+	start, size := 0, 0
+	for i, vector := range vectors {
+		n := vectorPayloadSize(vector)
+		if i > start && (i-start >= maxNq || size+n > maxBytes) {
+			chunks = append(chunks, searchChunk{start: start, end: i})
+			start, size = i, 0
+		}
+		size += n
+	}
+	if start < len(vectors) {
+		chunks = append(chunks, searchChunk{start: start, end: len(vectors)})
+	}
+	return chunks
+}
+
+// vectorPayloadSize returns the serialized size of vector in search request.
+func vectorPayloadSize(vector entity.Vector) int {
+	switch v := vector.(type) {
+	case entity.FloatVector:
+		return len(v) * 4
+	case entity.BinaryVector:
+		return len(v)
+	default:
+		return len(vector.Serialize())
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"sync/atomic"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+func TestSplitSearchVectors(t *testing.T) {
+	vectors := make([]entity.Vector, 0, 5)
+	for i := 0; i < 5; i++ {
+		vectors = append(vectors, entity.FloatVector([]float32{float32(i), 0}))
+	}
+	assert.Equal(t, []searchChunk{{0, 5}}, splitSearchVectors(vectors, 10, 1024))
+	assert.Equal(t, []searchChunk{{0, 2}, {2, 4}, {4, 5}}, splitSearchVectors(vectors, 2, 1024))
+	// 8 bytes each vector
+	assert.Equal(t, []searchChunk{{0, 3}, {3, 5}}, splitSearchVectors(vectors, 10, 24))
+	assert.Equal(t, []searchChunk{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 5}}, splitSearchVectors(vectors, 10, 4))
+	assert.Nil(t, splitSearchVectors(nil, 10, 1024))
+}
+
+type SearchChunkSuite struct {
+	MockSuiteBase
+	sch *entity.Schema
+}
+
+func (s *SearchChunkSuite) SetupSuite() {
+	s.MockSuiteBase.SetupSuite()
+
+	s.sch = entity.NewSchema().WithName(testCollectionName).
+		WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true)).
+		WithField(entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithDim(2))
+}
+
+// setupSearch mocks search returning one hit for each query, whose id is the first element of the query vector.
+// Requests fail if fail returns true for any vector in them, the max nq of requests is recorded in maxNq.
+func (s *SearchChunkSuite) setupSearch(fail func(id int64) bool, maxNq *int64) {
+	s.mock.EXPECT().Search(mock.Anything, mock.AnythingOfType("*milvuspb.SearchRequest")).
+		Call.Return(func(_ context.Context, req *milvuspb.SearchRequest) *milvuspb.SearchResults {
+		for {
+			current := atomic.LoadInt64(maxNq)
+			if req.GetNq() <= current || atomic.CompareAndSwapInt64(maxNq, current, req.GetNq()) {
+				break
+			}
+		}
+		phg := &commonpb.PlaceholderGroup{}
+		s.Require().NoError(proto.Unmarshal(req.GetPlaceholderGroup(), phg))
+		var ids, topks []int64
+		var scores []float32
+		for _, value := range phg.GetPlaceholders()[0].GetValues() {
+			id := int64(math.Float32frombits(binary.LittleEndian.Uint32(value)))
+			if fail(id) {
+				return &milvuspb.SearchResults{Status: &commonpb.Status{ErrorCode: commonpb.ErrorCode_UnexpectedError}}
+			}
+			ids = append(ids, id)
+			scores = append(scores, float32(id))
+			topks = append(topks, 1)
+		}
+		return &milvuspb.SearchResults{
+			Status: getSuccessStatus(),
+			Results: &schemapb.SearchResultData{
+				NumQueries: req.GetNq(),
+				Ids:        &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids}}},
+				Scores:     scores,
+				Topks:      topks,
+			},
+		}
+	}, nil)
+}
+
+func (s *SearchChunkSuite) TestSearchChunks() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sp, err := entity.NewIndexFlatSearchParam()
+	s.Require().NoError(err)
+	vectors := make([]entity.Vector, 0, 7)
+	for i := 0; i < 7; i++ {
+		vectors = append(vectors, entity.FloatVector([]float32{float32(i), 0}))
+	}
+
+	s.Run("reassemble", func() {
+		defer s.resetMock()
+		var maxNq int64
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupSearch(func(int64) bool { return false }, &maxNq)
+
+		results, err := s.client.Search(ctx, testCollectionName, nil, "", nil, vectors, "vector", entity.L2, 1, sp,
+			WithSearchChunk(2, 0), WithSearchConcurrency(2))
+		s.Require().NoError(err)
+		s.Require().Len(results, 7)
+		for i, result := range results {
+			s.Require().NoError(result.Err)
+			s.Equal([]int64{int64(i)}, result.IDs.(*entity.ColumnInt64).Data())
+		}
+		s.EqualValues(2, maxNq)
+	})
+
+	s.Run("chunk_fail", func() {
+		defer s.resetMock()
+		var maxNq int64
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupSearch(func(id int64) bool { return id == 3 }, &maxNq)
+
+		// chunks of 3 vectors by payload size
+		results, err := s.client.Search(ctx, testCollectionName, nil, "", nil, vectors, "vector", entity.L2, 1, sp,
+			WithSearchChunk(0, 24))
+		s.Require().NoError(err)
+		s.Require().Len(results, 7)
+		for i, result := range results {
+			if i >= 3 && i < 6 {
+				s.Error(result.Err)
+				continue
+			}
+			s.Require().NoError(result.Err)
+			s.Equal([]int64{int64(i)}, result.IDs.(*entity.ColumnInt64).Data())
+		}
+		s.EqualValues(3, maxNq)
+	})
+
+	s.Run("all_fail", func() {
+		defer s.resetMock()
+		var maxNq int64
+		s.setupDescribeCollection(testCollectionName, s.sch)
+		s.setupSearch(func(int64) bool { return true }, &maxNq)
+
+		_, err := s.client.Search(ctx, testCollectionName, nil, "", nil, vectors[:2], "vector", entity.L2, 1, sp,
+			WithSearchChunk(1, 0))
+		s.Error(err)
+	})
+}
+
+func TestSearchChunks(t *testing.T) {
+	suite.Run(t, new(SearchChunkSuite))
+}
@@ -0,0 +1,191 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+// Hit is one search result entry of TypedCollection.Search.
+type Hit[T any] struct {
+	Score  float32 // distance to the target vector
+	Entity T       // the entity with output fields filled
+}
+
+// TypedCollection is a collection handle bound to row struct type T.
+// T must be a struct type, fields are mapped to collection fields with `milvus` tag,
+// the same as `InsertRows` does.
+type TypedCollection[T any] struct {
+	client    Client
+	collName  string
+	partition string
+	schema    *entity.Schema
+	decoder   *rowDecoder
+}
+
+// NewTypedCollection creates a TypedCollection for provided collection name.
+// The collection must exist, its schema is fetched once and cached in the handle.
+func NewTypedCollection[T any](ctx context.Context, c Client, collName string) (*TypedCollection[T], error) {
+	if c == nil {
+		return nil, ErrClientNotReady
+	}
+	decoder, err := newRowDecoder(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+	coll, err := c.DescribeCollection(ctx, collName)
+	if err != nil {
+		return nil, err
+	}
+	// primary key field could be mapped by name without primary_key tag
+	if decoder.pkIdx < 0 {
+		if idx, ok := decoder.fields[coll.Schema.PKFieldName()]; ok {
+			decoder.pkIdx = idx
+		}
+	}
+	return &TypedCollection[T]{
+		client:   c,
+		collName: collName,
+		schema:   coll.Schema,
+		decoder:  decoder,
+	}, nil
+}
+
+// Name returns the collection name.
+func (tc *TypedCollection[T]) Name() string {
+	return tc.collName
+}
+
+// Schema returns the cached collection schema.
+func (tc *TypedCollection[T]) Schema() *entity.Schema {
+	return tc.schema
+}
+
+// Partition returns a copy of TypedCollection operating on provided partition only.
+func (tc *TypedCollection[T]) Partition(partitionName string) *TypedCollection[T] {
+	cp := *tc
+	cp.partition = partitionName
+	return &cp
+}
+
+// Insert inserts rows into the collection, returns id column values.
+func (tc *TypedCollection[T]) Insert(ctx context.Context, rows []T) (entity.Column, error) {
+	return tc.client.InsertRows(ctx, tc.collName, tc.partition, tc.anyRows(rows))
+}
+
+// Upsert upserts rows into the collection, returns id column values.
+func (tc *TypedCollection[T]) Upsert(ctx context.Context, rows []T) (entity.Column, error) {
+	return tc.client.UpsertRows(ctx, tc.collName, tc.partition, tc.anyRows(rows))
+}
+
+// Delete deletes the entities of rows by primary key.
+func (tc *TypedCollection[T]) Delete(ctx context.Context, rows []T) error {
+	return tc.client.DeleteRows(ctx, tc.collName, tc.partition, tc.anyRows(rows))
+}
+
+// Get fetches the entities with provided primary keys.
+func (tc *TypedCollection[T]) Get(ctx context.Context, ids entity.Column) ([]T, error) {
+	opts := []GetOption{GetWithOutputFields(tc.outputFields()...)}
+	if tc.partition != "" {
+		opts = append(opts, GetWithPartitions(tc.partition))
+	}
+	rs, err := tc.client.Get(ctx, tc.collName, ids, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return tc.decode(rs, nil, nil)
+}
+
+// Query returns the entities matching the boolean expression.
+func (tc *TypedCollection[T]) Query(ctx context.Context, expr string, opts ...SearchQueryOptionFunc) ([]T, error) {
+	rs, err := tc.client.Query(ctx, tc.collName, tc.partitions(), expr, tc.outputFields(), opts...)
+	if err != nil {
+		return nil, err
+	}
+	return tc.decode(rs, nil, nil)
+}
+
+// Search performs ANN search on vectorField, returns one hit list per search vector.
+func (tc *TypedCollection[T]) Search(ctx context.Context, expr string, vectors []entity.Vector, vectorField string,
+	metricType entity.MetricType, topK int, sp entity.SearchParam, opts ...SearchQueryOptionFunc) ([][]Hit[T], error) {
+	results, err := tc.client.Search(ctx, tc.collName, tc.partitions(), expr, tc.outputFields(), vectors, vectorField, metricType, topK, sp, opts...)
+	if err != nil {
+		return nil, err
+	}
+	hits := make([][]Hit[T], 0, len(results))
+	for i, result := range results {
+		if result.Err != nil {
+			return nil, fmt.Errorf("result %d: %w", i, result.Err)
+		}
+		rows, err := tc.decode(result.Fields, result.IDs, result.Scores)
+		if err != nil {
+			return nil, fmt.Errorf("result %d: %w", i, err)
+		}
+		entry := make([]Hit[T], 0, len(rows))
+		for j, row := range rows {
+			entry = append(entry, Hit[T]{Score: result.Scores[j], Entity: row})
+		}
+		hits = append(hits, entry)
+	}
+	return hits, nil
+}
+
+func (tc *TypedCollection[T]) anyRows(rows []T) []interface{} {
+	anys := make([]interface{}, 0, len(rows))
+	for i := range rows {
+		anys = append(anys, &rows[i])
+	}
+	return anys
+}
+
+func (tc *TypedCollection[T]) partitions() []string {
+	if tc.partition == "" {
+		return nil
+	}
+	return []string{tc.partition}
+}
+
+// outputFields returns the struct mapped field names which could be output by server.
+func (tc *TypedCollection[T]) outputFields() []string {
+	fields := make(map[string]struct{}, len(tc.schema.Fields))
+	for _, field := range tc.schema.Fields {
+		fields[field.Name] = struct{}{}
+	}
+	names := make([]string, 0, len(fields))
+	for _, name := range tc.decoder.fieldNames() {
+		if _, ok := fields[name]; ok || tc.schema.EnableDynamicField {
+			names = append(names, name)
+		}
+	}
+	// request whole dynamic field data for dynamic tagged field
+	if tc.decoder.dynamicIdx >= 0 {
+		for _, field := range tc.schema.Fields {
+			if field.IsDynamic {
+				names = append(names, field.Name)
+			}
+		}
+	}
+	return names
+}
+
+// decode converts result columns into rows, primary key and score are filled for search result.
+func (tc *TypedCollection[T]) decode(rs ResultSet, ids entity.Column, scores []float32) ([]T, error) {
+	var rows []T
+	if err := unmarshalRowsWith(tc.decoder, &rows, rs, ids, scores); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,243 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+type typedRow struct {
+	ID     int64           `milvus:"name:id;primary_key"`
+	Title  string          `milvus:"name:title"`
+	Vector [2]float32      `milvus:"name:vector"`
+	Extra  json.RawMessage `milvus:"name:extra"`
+	Ignore string          `milvus:"-"`
+}
+
+type TypedCollectionSuite struct {
+	MockSuiteBase
+	sch *entity.Schema
+}
+
+func (s *TypedCollectionSuite) SetupSuite() {
+	s.MockSuiteBase.SetupSuite()
+
+	s.sch = entity.NewSchema().WithName(testCollectionName).
+		WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true)).
+		WithField(entity.NewField().WithName("title").WithDataType(entity.FieldTypeVarChar).WithMaxLength(64)).
+		WithField(entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithDim(2)).
+		WithField(entity.NewField().WithName("extra").WithDataType(entity.FieldTypeJSON))
+}
+
+func (s *TypedCollectionSuite) newCollection(ctx context.Context) *TypedCollection[typedRow] {
+	s.setupDescribeCollection(testCollectionName, s.sch)
+	coll, err := NewTypedCollection[typedRow](ctx, s.client, testCollectionName)
+	s.Require().NoError(err)
+	return coll
+}
+
+func (s *TypedCollectionSuite) TestNewTypedCollection() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.Run("normal_case", func() {
+		defer s.resetMock()
+		coll := s.newCollection(ctx)
+		s.Equal(testCollectionName, coll.Name())
+		s.Equal(s.sch.CollectionName, coll.Schema().CollectionName)
+		s.ElementsMatch([]string{"id", "title", "vector", "extra"}, coll.outputFields())
+
+		part := coll.Partition("part_1")
+		s.Equal("part_1", part.partition)
+		s.Equal("", coll.partition)
+	})
+
+	s.Run("non_struct_type", func() {
+		defer s.resetMock()
+		_, err := NewTypedCollection[int64](ctx, s.client, testCollectionName)
+		s.Error(err)
+	})
+
+	s.Run("describe_fail", func() {
+		defer s.resetMock()
+		s.setupDescribeCollectionError(commonpb.ErrorCode_UnexpectedError, errors.New("mock"))
+		_, err := NewTypedCollection[typedRow](ctx, s.client, testCollectionName)
+		s.Error(err)
+	})
+}
+
+func (s *TypedCollectionSuite) TestInsert() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer s.resetMock()
+
+	coll := s.newCollection(ctx)
+	s.setupHasCollection(testCollectionName)
+	s.mock.EXPECT().Insert(mock.Anything, mock.AnythingOfType("*milvuspb.InsertRequest")).
+		Run(func(_ context.Context, req *milvuspb.InsertRequest) {
+			s.EqualValues(2, req.GetNumRows())
+			// default partition is chosen by server
+			s.Empty(req.GetPartitionName())
+			for _, fd := range req.GetFieldsData() {
+				if fd.GetFieldName() == "vector" {
+					s.Equal([]float32{1, 2, 3, 4}, fd.GetVectors().GetFloatVector().GetData())
+				}
+				if fd.GetFieldName() == "title" {
+					s.Equal(schemapb.DataType_VarChar, fd.GetType())
+				}
+			}
+		}).
+		Return(&milvuspb.MutationResult{
+			Status: getSuccessStatus(),
+			IDs: &schemapb.IDs{
+				IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1, 2}}},
+			},
+		}, nil)
+
+	ids, err := coll.Insert(ctx, []typedRow{
+		{ID: 1, Title: "a", Vector: [2]float32{1, 2}, Extra: json.RawMessage(`{}`)},
+		{ID: 2, Title: "b", Vector: [2]float32{3, 4}, Extra: json.RawMessage(`{}`)},
+	})
+	s.Require().NoError(err)
+	s.Equal(2, ids.Len())
+}
+
+func (s *TypedCollectionSuite) TestUpsert() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer s.resetMock()
+
+	coll := s.newCollection(ctx)
+	s.setupHasCollection(testCollectionName)
+	s.mock.EXPECT().Upsert(mock.Anything, mock.AnythingOfType("*milvuspb.UpsertRequest")).
+		Run(func(_ context.Context, req *milvuspb.UpsertRequest) {
+			s.EqualValues(1, req.GetNumRows())
+		}).
+		Return(&milvuspb.MutationResult{
+			Status: getSuccessStatus(),
+			IDs: &schemapb.IDs{
+				IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1}}},
+			},
+		}, nil)
+
+	ids, err := coll.Upsert(ctx, []typedRow{
+		{ID: 1, Title: "a", Vector: [2]float32{1, 2}, Extra: json.RawMessage(`{}`)},
+	})
+	s.Require().NoError(err)
+	s.Equal(1, ids.Len())
+
+	_, err = coll.Upsert(ctx, nil)
+	s.Error(err)
+}
+
+func (s *TypedCollectionSuite) TestQuery() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer s.resetMock()
+
+	coll := s.newCollection(ctx)
+	s.mock.EXPECT().Query(mock.Anything, mock.AnythingOfType("*milvuspb.QueryRequest")).
+		Run(func(_ context.Context, req *milvuspb.QueryRequest) {
+			s.Equal("id > 0", req.GetExpr())
+			s.ElementsMatch([]string{"part_1"}, req.GetPartitionNames())
+		}).
+		Return(&milvuspb.QueryResults{
+			Status: getSuccessStatus(),
+			FieldsData: []*schemapb.FieldData{
+				s.getInt64FieldData("id", []int64{1, 2}),
+				s.getVarcharFieldData("title", []string{"a", "b"}),
+				s.getFloatVectorFieldData("vector", 2, []float32{1, 2, 3, 4}),
+				s.getJSONBytesFieldData("extra", [][]byte{[]byte(`{"a":1}`), []byte(`{"b":2}`)}, false),
+			},
+		}, nil)
+
+	rows, err := coll.Partition("part_1").Query(ctx, "id > 0", WithSearchQueryConsistencyLevel(entity.ClStrong))
+	s.Require().NoError(err)
+	s.Require().Len(rows, 2)
+	s.Equal(typedRow{ID: 1, Title: "a", Vector: [2]float32{1, 2}, Extra: json.RawMessage(`{"a":1}`)}, rows[0])
+	s.Equal(typedRow{ID: 2, Title: "b", Vector: [2]float32{3, 4}, Extra: json.RawMessage(`{"b":2}`)}, rows[1])
+}
+
+func (s *TypedCollectionSuite) TestSearch() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer s.resetMock()
+
+	sp, err := entity.NewIndexFlatSearchParam()
+	s.Require().NoError(err)
+
+	coll := s.newCollection(ctx)
+	s.mock.EXPECT().Search(mock.Anything, mock.AnythingOfType("*milvuspb.SearchRequest")).
+		Return(&milvuspb.SearchResults{
+			Status: getSuccessStatus(),
+			Results: &schemapb.SearchResultData{
+				NumQueries: 2,
+				TopK:       1,
+				FieldsData: []*schemapb.FieldData{
+					s.getInt64FieldData("id", []int64{1, 2}),
+					s.getVarcharFieldData("title", []string{"a", "b"}),
+					s.getFloatVectorFieldData("vector", 2, []float32{1, 2, 3, 4}),
+					s.getJSONBytesFieldData("extra", [][]byte{[]byte(`{}`), []byte(`{}`)}, false),
+				},
+				Ids: &schemapb.IDs{
+					IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1, 2}}},
+				},
+				Scores: []float32{0.1, 0.2},
+				Topks:  []int64{1, 1},
+			},
+		}, nil)
+
+	hits, err := coll.Search(ctx, "", []entity.Vector{entity.FloatVector{1, 2}, entity.FloatVector{3, 4}}, "vector", entity.L2, 1, sp)
+	s.Require().NoError(err)
+	s.Require().Len(hits, 2)
+	s.Require().Len(hits[0], 1)
+	s.Equal(Hit[typedRow]{Score: 0.1, Entity: typedRow{ID: 1, Title: "a", Vector: [2]float32{1, 2}, Extra: json.RawMessage(`{}`)}}, hits[0][0])
+	s.Require().Len(hits[1], 1)
+	s.Equal(Hit[typedRow]{Score: 0.2, Entity: typedRow{ID: 2, Title: "b", Vector: [2]float32{3, 4}, Extra: json.RawMessage(`{}`)}}, hits[1][0])
+}
+
+func (s *TypedCollectionSuite) TestDecodeFail() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer s.resetMock()
+
+	coll := s.newCollection(ctx)
+	s.mock.EXPECT().Query(mock.Anything, mock.AnythingOfType("*milvuspb.QueryRequest")).
+		Return(&milvuspb.QueryResults{
+			Status: getSuccessStatus(),
+			FieldsData: []*schemapb.FieldData{
+				s.getVarcharFieldData("id", []string{"1"}),
+				s.getVarcharFieldData("title", []string{"a"}),
+				s.getFloatVectorFieldData("vector", 2, []float32{1, 2}),
+				s.getJSONBytesFieldData("extra", [][]byte{[]byte(`{}`)}, false),
+			},
+		}, nil)
+
+	_, err := coll.Query(ctx, "id > 0")
+	s.Error(err)
+	s.ErrorIs(err, ErrFieldTypeNotMatch)
+}
+
+func TestTypedCollection(t *testing.T) {
+	suite.Run(t, new(TypedCollectionSuite))
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+// Rowgen generates typed column batches for row structs annotated with `milvus` tags.
+//
+// For each struct type `Xxx` provided, a `XxxBatch` type is generated with `Append`, `Columns`,
+// `Schema`, `Rows` and `FromResultSet` methods, so inserting and reading rows needs no reflection.
+//
+// Usage, in the file declaring the row struct:
+//
+//	//go:generate go run github.com/milvus-io/milvus-sdk-go/v2/cmd/rowgen -type=Film
+//
+// Supported tag settings are the same as entity.ParseSchemaAny (name, primary_key, auto_id, dim)
+// plus max_length for string fields, partition_key and json.
+// Unlike entity.ParseSchemaAny, string fields are generated as VarChar with max_length 65535
+// by default, since the String type is not supported by the server.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	typeNames  = flag.String("type", "", "comma-separated list of struct type names; must be set")
+	output     = flag.String("output", "", "output file name; default <type>_milvus_gen.go")
+	collection = flag.String("collection", "", "collection name used in schema; default struct type name, only valid with single type")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("rowgen: ")
+	flag.Parse()
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	types := strings.Split(*typeNames, ",")
+	if *collection != "" && len(types) > 1 {
+		log.Fatal("-collection could only be used with single type")
+	}
+
+	dir := "."
+	if args := flag.Args(); len(args) > 0 {
+		dir = args[0]
+	}
+
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(pkgs) != 1 {
+		log.Fatalf("expected exactly one package in %s, got %d", dir, len(pkgs))
+	}
+
+	var pkgName string
+	var files []*ast.File
+	for name, pkg := range pkgs {
+		pkgName = name
+		for _, file := range pkg.Files {
+			files = append(files, file)
+		}
+	}
+
+	params := templateParams{
+		Package: pkgName,
+	}
+	for _, typeName := range types {
+		desc, err := parseBatchDesc(files, strings.TrimSpace(typeName))
+		if err != nil {
+			log.Fatal(err)
+		}
+		if *collection != "" {
+			desc.CollectionName = *collection
+		}
+		params.Batches = append(params.Batches, desc)
+	}
+
+	src, err := generate(params)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	outputName := *output
+	if outputName == "" {
+		outputName = fmt.Sprintf("%s_milvus_gen.go", strings.ToLower(types[0]))
+	}
+	if err := os.WriteFile(filepath.Join(dir, outputName), src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
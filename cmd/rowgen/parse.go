@@ -0,0 +1,320 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"reflect"
+	"strconv"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+const (
+	// tagMaxLength struct tag setting for varchar max length
+	tagMaxLength = `MAX_LENGTH`
+	// tagPartitionKey struct tag setting for partition key indicator
+	tagPartitionKey = `PARTITION_KEY`
+	// tagJSON struct tag setting forcing field to be stored as JSON
+	tagJSON = `JSON`
+
+	// defaultMaxLength is used for string fields without max_length setting
+	defaultMaxLength = 65535
+)
+
+// jsonTypes are the types from other packages stored as JSON without json tag setting.
+var jsonTypes = map[string]struct{}{
+	"json.RawMessage": {},
+}
+
+// batchDesc describes the batch type to generate for one row struct.
+type batchDesc struct {
+	TypeName       string
+	CollectionName string
+	Fields         []*fieldDesc
+}
+
+// AutoID returns whether the primary key is auto generated.
+func (b *batchDesc) AutoID() bool {
+	for _, f := range b.Fields {
+		if f.PrimaryKey {
+			return f.AutoID
+		}
+	}
+	return false
+}
+
+// fieldDesc describes one struct field mapped to a collection field.
+type fieldDesc struct {
+	GoName       string // struct field name
+	Name         string // collection field name
+	GoType       string // struct field type expression
+	DataType     entity.FieldType
+	PrimaryKey   bool
+	AutoID       bool
+	PartitionKey bool
+	Dim          int64
+	MaxLength    int64
+	ArrayLen     int64 // fixed-size array length for vector fields, 0 if slice
+}
+
+// Storage returns the go type of the per-row element kept in batch.
+func (f *fieldDesc) Storage() string {
+	switch f.DataType {
+	case entity.FieldTypeFloatVector:
+		return "[]float32"
+	case entity.FieldTypeBinaryVector, entity.FieldTypeJSON:
+		return "[]byte"
+	default:
+		return f.DataType.String()
+	}
+}
+
+// ColumnType returns the entity column type name.
+func (f *fieldDesc) ColumnType() string {
+	if f.DataType == entity.FieldTypeJSON {
+		return "ColumnJSONBytes"
+	}
+	return "Column" + f.DataType.Name()
+}
+
+// IsVector returns whether the field is a vector field.
+func (f *fieldDesc) IsVector() bool {
+	return f.DataType == entity.FieldTypeFloatVector || f.DataType == entity.FieldTypeBinaryVector
+}
+
+// IsJSON returns whether the field is stored as JSON.
+func (f *fieldDesc) IsJSON() bool {
+	return f.DataType == entity.FieldTypeJSON
+}
+
+// DataTypeName returns the entity FieldType constant name.
+func (f *fieldDesc) DataTypeName() string {
+	return "FieldType" + f.DataType.Name()
+}
+
+// Skipped returns whether the field is omitted when inserting.
+func (f *fieldDesc) Skipped() bool {
+	return f.PrimaryKey && f.AutoID
+}
+
+// parseBatchDesc finds struct type `typeName` in provided files and parses field mappings.
+func parseBatchDesc(files []*ast.File, typeName string) (*batchDesc, error) {
+	var st *ast.StructType
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			ts, ok := n.(*ast.TypeSpec)
+			if !ok || ts.Name.Name != typeName {
+				return st == nil
+			}
+			if s, ok := ts.Type.(*ast.StructType); ok {
+				st = s
+			}
+			return false
+		})
+		if st != nil {
+			break
+		}
+	}
+	if st == nil {
+		return nil, fmt.Errorf("struct type %s not found", typeName)
+	}
+
+	desc := &batchDesc{
+		TypeName:       typeName,
+		CollectionName: typeName,
+	}
+	names := make(map[string]struct{})
+	pkCount := 0
+	for _, field := range st.Fields.List {
+		// ignore anonymous field for now, same as entity.ParseSchemaAny
+		if len(field.Names) == 0 {
+			continue
+		}
+		var tag string
+		if field.Tag != nil {
+			raw, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			tag = reflect.StructTag(raw).Get(entity.MilvusTag)
+		}
+		if tag == entity.MilvusSkipTagValue {
+			continue
+		}
+		for _, ident := range field.Names {
+			if !ident.IsExported() {
+				continue
+			}
+			fd, err := parseFieldDesc(ident.Name, field.Type, tag)
+			if err != nil {
+				return nil, err
+			}
+			if _, dup := names[fd.Name]; dup {
+				return nil, fmt.Errorf("duplicated field name %s in struct %s", fd.Name, typeName)
+			}
+			names[fd.Name] = struct{}{}
+			if fd.PrimaryKey {
+				pkCount++
+			}
+			desc.Fields = append(desc.Fields, fd)
+		}
+	}
+	if pkCount != 1 {
+		return nil, fmt.Errorf("struct %s shall have exactly one primary key field, got %d", typeName, pkCount)
+	}
+	return desc, nil
+}
+
+func parseFieldDesc(goName string, expr ast.Expr, tag string) (*fieldDesc, error) {
+	settings := entity.ParseTagSetting(tag, entity.MilvusTagSep)
+	fd := &fieldDesc{
+		GoName: goName,
+		Name:   goName,
+		GoType: exprString(expr),
+	}
+	if name, has := settings[entity.MilvusTagName]; has {
+		fd.Name = name
+	}
+	_, fd.PrimaryKey = settings[entity.MilvusPrimaryKey]
+	_, fd.AutoID = settings[entity.MilvusAutoID]
+	_, fd.PartitionKey = settings[tagPartitionKey]
+
+	if _, has := settings[tagJSON]; has {
+		if fd.PrimaryKey {
+			return nil, fmt.Errorf("primary key field %s must be int64 or string", goName)
+		}
+		fd.DataType = entity.FieldTypeJSON
+		return fd, nil
+	}
+
+	switch t := expr.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "bool":
+			fd.DataType = entity.FieldTypeBool
+		case "int8":
+			fd.DataType = entity.FieldTypeInt8
+		case "int16":
+			fd.DataType = entity.FieldTypeInt16
+		case "int32":
+			fd.DataType = entity.FieldTypeInt32
+		case "int64":
+			fd.DataType = entity.FieldTypeInt64
+		case "float32":
+			fd.DataType = entity.FieldTypeFloat
+		case "float64":
+			fd.DataType = entity.FieldTypeDouble
+		case "string":
+			fd.DataType = entity.FieldTypeVarChar
+			fd.MaxLength = defaultMaxLength
+			if v, has := settings[tagMaxLength]; has {
+				maxLength, err := strconv.ParseInt(v, 10, 64)
+				if err != nil || maxLength < 1 {
+					return nil, fmt.Errorf("field %s max_length value %s is not valid", goName, v)
+				}
+				fd.MaxLength = maxLength
+			}
+		default:
+			return nil, fmt.Errorf("field %s is %s, which is not supported", goName, t.Name)
+		}
+	case *ast.ArrayType:
+		elem, ok := t.Elt.(*ast.Ident)
+		if !ok || (elem.Name != "float32" && elem.Name != "byte" && elem.Name != "uint8") {
+			return nil, fmt.Errorf("field %s is %s, which is not supported", goName, fd.GoType)
+		}
+		var dim int64
+		if t.Len != nil {
+			lit, ok := t.Len.(*ast.BasicLit)
+			if !ok || lit.Kind != token.INT {
+				return nil, fmt.Errorf("field %s array length must be an integer literal", goName)
+			}
+			l, err := strconv.ParseInt(lit.Value, 0, 64)
+			if err != nil {
+				return nil, err
+			}
+			fd.ArrayLen = l
+			dim = l
+			if elem.Name != "float32" {
+				dim = l * 8
+			}
+		} else {
+			dimStr, has := settings[entity.VectorDimTag]
+			if !has {
+				return nil, fmt.Errorf("field %s is slice but dim not provided", goName)
+			}
+			d, err := strconv.ParseInt(dimStr, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("dim value %s is not valid", dimStr)
+			}
+			dim = d
+		}
+		if dim < 1 || dim > entity.DimMax {
+			return nil, fmt.Errorf("dim value %d is out of range", dim)
+		}
+		fd.Dim = dim
+		if elem.Name == "float32" {
+			fd.DataType = entity.FieldTypeFloatVector
+		} else {
+			if dim%8 != 0 {
+				return nil, fmt.Errorf("binary vector field %s dim %d is not multiple of 8", goName, dim)
+			}
+			fd.DataType = entity.FieldTypeBinaryVector
+		}
+	case *ast.MapType:
+		fd.DataType = entity.FieldTypeJSON
+	case *ast.SelectorExpr:
+		// types from other packages are stored as json only when known to be json,
+		// others (time.Time for instance) need the json tag setting explicitly
+		if _, ok := jsonTypes[fd.GoType]; !ok {
+			return nil, fmt.Errorf("field %s is %s, which is not supported, use json tag setting to store it as JSON", goName, fd.GoType)
+		}
+		fd.DataType = entity.FieldTypeJSON
+	default:
+		return nil, fmt.Errorf("field %s is %s, which is not supported", goName, fd.GoType)
+	}
+
+	if fd.PrimaryKey && fd.DataType != entity.FieldTypeInt64 && fd.DataType != entity.FieldTypeVarChar {
+		return nil, fmt.Errorf("primary key field %s must be int64 or string", goName)
+	}
+	if fd.AutoID && !fd.PrimaryKey {
+		return nil, fmt.Errorf("field %s is auto id but not primary key", goName)
+	}
+	return fd, nil
+}
+
+// exprString prints type expression in source form.
+func exprString(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return "[]" + exprString(t.Elt)
+		}
+		return "[" + exprString(t.Len) + "]" + exprString(t.Elt)
+	case *ast.BasicLit:
+		return t.Value
+	case *ast.StarExpr:
+		return "*" + exprString(t.X)
+	case *ast.SelectorExpr:
+		return exprString(t.X) + "." + t.Sel.Name
+	case *ast.MapType:
+		return "map[" + exprString(t.Key) + "]" + exprString(t.Value)
+	case *ast.InterfaceType:
+		return "interface{}"
+	default:
+		return fmt.Sprintf("%T", expr)
+	}
+}
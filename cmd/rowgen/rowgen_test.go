@@ -0,0 +1,195 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+func parseSource(t *testing.T, src string) []*ast.File {
+	t.Helper()
+	f, err := parser.ParseFile(token.NewFileSet(), "src.go", src, parser.ParseComments)
+	require.NoError(t, err)
+	return []*ast.File{f}
+}
+
+const validSource = `package sample
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Item struct {
+	ID      int64             ` + "`milvus:\"name:id;primary_key;auto_id\"`" + `
+	Title   string            ` + "`milvus:\"name:title;max_length:128\"`" + `
+	Tenant  string            ` + "`milvus:\"name:tenant;partition_key\"`" + `
+	Score   float64
+	Vector  [16]float32       ` + "`milvus:\"name:vector\"`" + `
+	Dyn     []float32         ` + "`milvus:\"dim:4\"`" + `
+	Bits    [4]byte
+	Meta    map[string]string
+	Raw     json.RawMessage
+	Created time.Time         ` + "`milvus:\"json\"`" + `
+	Ignored int               ` + "`milvus:\"-\"`" + `
+	hidden  int
+}
+`
+
+func TestParseBatchDesc(t *testing.T) {
+	desc, err := parseBatchDesc(parseSource(t, validSource), "Item")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Item", desc.TypeName)
+	assert.Equal(t, "Item", desc.CollectionName)
+	assert.True(t, desc.AutoID())
+	require.Len(t, desc.Fields, 10)
+
+	fields := make(map[string]*fieldDesc)
+	for _, f := range desc.Fields {
+		fields[f.Name] = f
+	}
+
+	id := fields["id"]
+	require.NotNil(t, id)
+	assert.Equal(t, entity.FieldTypeInt64, id.DataType)
+	assert.True(t, id.PrimaryKey)
+	assert.True(t, id.Skipped())
+
+	title := fields["title"]
+	require.NotNil(t, title)
+	assert.Equal(t, entity.FieldTypeVarChar, title.DataType)
+	assert.EqualValues(t, 128, title.MaxLength)
+
+	tenant := fields["tenant"]
+	require.NotNil(t, tenant)
+	assert.True(t, tenant.PartitionKey)
+	assert.EqualValues(t, defaultMaxLength, tenant.MaxLength)
+
+	assert.Equal(t, entity.FieldTypeDouble, fields["Score"].DataType)
+
+	vector := fields["vector"]
+	require.NotNil(t, vector)
+	assert.Equal(t, entity.FieldTypeFloatVector, vector.DataType)
+	assert.EqualValues(t, 16, vector.Dim)
+	assert.EqualValues(t, 16, vector.ArrayLen)
+
+	dyn := fields["Dyn"]
+	require.NotNil(t, dyn)
+	assert.EqualValues(t, 4, dyn.Dim)
+	assert.EqualValues(t, 0, dyn.ArrayLen)
+
+	bits := fields["Bits"]
+	require.NotNil(t, bits)
+	assert.Equal(t, entity.FieldTypeBinaryVector, bits.DataType)
+	assert.EqualValues(t, 32, bits.Dim)
+
+	assert.Equal(t, entity.FieldTypeJSON, fields["Meta"].DataType)
+	assert.Equal(t, entity.FieldTypeJSON, fields["Raw"].DataType)
+	assert.Equal(t, entity.FieldTypeJSON, fields["Created"].DataType)
+	assert.Nil(t, fields["Ignored"])
+}
+
+func TestParseBatchDescFail(t *testing.T) {
+	cases := map[string]string{
+		"type_not_found": `package sample
+type Other struct{}`,
+		"no_primary_key": `package sample
+type Item struct {
+	ID int64
+}`,
+		"multiple_primary_keys": `package sample
+type Item struct {
+	ID  int64 ` + "`milvus:\"primary_key\"`" + `
+	ID2 int64 ` + "`milvus:\"primary_key\"`" + `
+}`,
+		"invalid_primary_key_type": `package sample
+type Item struct {
+	ID float32 ` + "`milvus:\"primary_key\"`" + `
+}`,
+		"auto_id_not_primary_key": `package sample
+type Item struct {
+	ID  int64 ` + "`milvus:\"primary_key\"`" + `
+	Val int64 ` + "`milvus:\"auto_id\"`" + `
+}`,
+		"slice_without_dim": `package sample
+type Item struct {
+	ID  int64 ` + "`milvus:\"primary_key\"`" + `
+	Vec []float32
+}`,
+		"unsupported_type": `package sample
+type Item struct {
+	ID  int64 ` + "`milvus:\"primary_key\"`" + `
+	Val uint64
+}`,
+		"selector_without_json_tag": `package sample
+type Item struct {
+	ID      int64     ` + "`milvus:\"primary_key\"`" + `
+	Created time.Time
+}`,
+		"duplicated_name": `package sample
+type Item struct {
+	ID  int64 ` + "`milvus:\"primary_key\"`" + `
+	Val int64 ` + "`milvus:\"name:ID\"`" + `
+}`,
+		"invalid_max_length": `package sample
+type Item struct {
+	ID  int64  ` + "`milvus:\"primary_key\"`" + `
+	Val string ` + "`milvus:\"max_length:abc\"`" + `
+}`,
+		"binary_dim_not_multiple_of_8": `package sample
+type Item struct {
+	ID  int64  ` + "`milvus:\"primary_key\"`" + `
+	Val []byte ` + "`milvus:\"dim:12\"`" + `
+}`,
+	}
+
+	for name, src := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseBatchDesc(parseSource(t, src), "Item")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	desc, err := parseBatchDesc(parseSource(t, validSource), "Item")
+	require.NoError(t, err)
+
+	src, err := generate(templateParams{Package: "sample", Batches: []*batchDesc{desc}})
+	require.NoError(t, err)
+
+	// generated source shall be valid go code
+	_, err = parser.ParseFile(token.NewFileSet(), "gen.go", src, 0)
+	require.NoError(t, err)
+
+	code := string(src)
+	assert.True(t, strings.HasPrefix(code, "// Code generated by rowgen; DO NOT EDIT."))
+	assert.Contains(t, code, "type ItemBatch struct")
+	assert.Contains(t, code, "func NewItemBatch(capacity int) *ItemBatch")
+	assert.Contains(t, code, `"encoding/json"`)
+	assert.Contains(t, code, `entity.NewColumnFloatVector("vector", 16, b.colVector)`)
+	assert.Contains(t, code, `entity.NewColumnBinaryVector("Bits", 32, b.colBits)`)
+	assert.Contains(t, code, `entity.NewColumnJSONBytes("Meta", b.colMeta)`)
+	assert.Contains(t, code, ".WithIsPartitionKey(true)")
+	// auto id primary key is not inserted
+	assert.NotContains(t, code, `entity.NewColumnInt64("id"`)
+}
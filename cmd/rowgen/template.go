@@ -0,0 +1,241 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"text/template"
+)
+
+var batchTemplate = template.Must(template.New("").Parse(`// Code generated by rowgen; DO NOT EDIT.
+// This file is generated by go generate
+
+package {{.Package}}
+
+import (
+	{{- if .HasJSON}}
+	"encoding/json"
+	{{- end}}
+	"fmt"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+{{range .Batches}}{{with .}}
+// {{.TypeName}}Batch is the column-based batch of {{.TypeName}} rows.
+type {{.TypeName}}Batch struct {
+	{{- range .Fields}}
+	col{{.GoName}} []{{.Storage}}
+	{{- end}}
+}
+
+// New{{.TypeName}}Batch creates a {{.TypeName}}Batch with provided capacity.
+func New{{.TypeName}}Batch(capacity int) *{{.TypeName}}Batch {
+	return &{{.TypeName}}Batch{
+		{{- range .Fields}}
+		col{{.GoName}}: make([]{{.Storage}}, 0, capacity),
+		{{- end}}
+	}
+}
+
+// Len returns the row count of the batch.
+func (b *{{.TypeName}}Batch) Len() int {
+	return len(b.col{{(index .Fields 0).GoName}})
+}
+
+// Reset clears the batch data and keeps allocated memory.
+func (b *{{.TypeName}}Batch) Reset() {
+	b.truncate(0)
+}
+
+// Append appends rows into the batch.
+// Batch is not modified if any row is invalid.
+func (b *{{.TypeName}}Batch) Append(rows ...{{.TypeName}}) error {
+	n := b.Len()
+	for i := range rows {
+		if err := b.appendRow(&rows[i]); err != nil {
+			b.truncate(n)
+			return fmt.Errorf("row %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (b *{{.TypeName}}Batch) appendRow(row *{{.TypeName}}) error {
+	{{- range .Fields}}
+	{{- if .IsJSON}}
+	bs{{.GoName}}, err := json.Marshal(row.{{.GoName}})
+	if err != nil {
+		return fmt.Errorf("field {{.Name}}: %w", err)
+	}
+	{{- else if and .IsVector (eq .ArrayLen 0)}}
+	{{- if eq .DataTypeName "FieldTypeFloatVector"}}
+	if len(row.{{.GoName}}) != {{.Dim}} {
+		return fmt.Errorf("field {{.Name}} has dim %d, expected {{.Dim}}", len(row.{{.GoName}}))
+	}
+	{{- else}}
+	if len(row.{{.GoName}})*8 != {{.Dim}} {
+		return fmt.Errorf("field {{.Name}} has dim %d, expected {{.Dim}}", len(row.{{.GoName}})*8)
+	}
+	{{- end}}
+	{{- end}}
+	{{- end}}
+	{{- range .Fields}}
+	{{- if .IsJSON}}
+	b.col{{.GoName}} = append(b.col{{.GoName}}, bs{{.GoName}})
+	{{- else if gt .ArrayLen 0}}
+	b.col{{.GoName}} = append(b.col{{.GoName}}, row.{{.GoName}}[:])
+	{{- else}}
+	b.col{{.GoName}} = append(b.col{{.GoName}}, row.{{.GoName}})
+	{{- end}}
+	{{- end}}
+	return nil
+}
+
+func (b *{{.TypeName}}Batch) truncate(n int) {
+	{{- range .Fields}}
+	b.col{{.GoName}} = b.col{{.GoName}}[:n]
+	{{- end}}
+}
+
+// Columns returns batch data as columns, which could be passed to Insert/Upsert directly.
+// The auto id primary key field is omitted.
+func (b *{{.TypeName}}Batch) Columns() []entity.Column {
+	return []entity.Column{
+		{{- range .Fields}}{{if not .Skipped}}
+		{{- if .IsVector}}
+		entity.New{{.ColumnType}}("{{.Name}}", {{.Dim}}, b.col{{.GoName}}),
+		{{- else}}
+		entity.New{{.ColumnType}}("{{.Name}}", b.col{{.GoName}}),
+		{{- end}}
+		{{- end}}{{end}}
+	}
+}
+
+// Schema returns the collection schema of {{.TypeName}}.
+func (b *{{.TypeName}}Batch) Schema() *entity.Schema {
+	return entity.NewSchema().WithName("{{.CollectionName}}").WithAutoID({{.AutoID}}).
+		{{- range $i, $f := .Fields}}{{with $f}}{{if $i}}.{{end}}
+		WithField(entity.NewField().WithName("{{.Name}}").WithDataType(entity.{{.DataTypeName}})
+		{{- if .PrimaryKey}}.WithIsPrimaryKey(true).WithIsAutoID({{.AutoID}}){{end}}
+		{{- if .PartitionKey}}.WithIsPartitionKey(true){{end}}
+		{{- if .IsVector}}.WithDim({{.Dim}}){{end}}
+		{{- if gt .MaxLength 0}}.WithMaxLength({{.MaxLength}}){{end}})
+		{{- end}}{{end}}
+}
+
+// OutputFields returns the field names of {{.TypeName}}, which could be used as output fields in Search/Query.
+func (b *{{.TypeName}}Batch) OutputFields() []string {
+	return []string{ {{- range $i, $f := .Fields}}{{if $i}}, {{end}}"{{$f.Name}}"{{end}} }
+}
+
+// Rows returns batch data as {{.TypeName}} rows.
+func (b *{{.TypeName}}Batch) Rows() ([]{{.TypeName}}, error) {
+	rows := make([]{{.TypeName}}, b.Len())
+	for i := range rows {
+		{{- range .Fields}}
+		{{- if .IsJSON}}
+		if err := json.Unmarshal(b.col{{.GoName}}[i], &rows[i].{{.GoName}}); err != nil {
+			return nil, fmt.Errorf("row %d field {{.Name}}: %w", i, err)
+		}
+		{{- else if gt .ArrayLen 0}}
+		copy(rows[i].{{.GoName}}[:], b.col{{.GoName}}[i])
+		{{- else}}
+		rows[i].{{.GoName}} = b.col{{.GoName}}[i]
+		{{- end}}
+		{{- end}}
+	}
+	return rows, nil
+}
+
+// FromResultSet resets the batch and fills it with columns from Query/Search result.
+// Fields not present in the result are filled with zero values.
+func (b *{{.TypeName}}Batch) FromResultSet(rs []entity.Column) error {
+	columns := make(map[string]entity.Column, len(rs))
+	rowCount := -1
+	for _, column := range rs {
+		columns[column.Name()] = column
+		if rowCount >= 0 && column.Len() != rowCount {
+			return fmt.Errorf("column %s length %d not match %d", column.Name(), column.Len(), rowCount)
+		}
+		rowCount = column.Len()
+	}
+	if rowCount < 0 {
+		rowCount = 0
+	}
+	b.Reset()
+	{{- range .Fields}}
+	if column, ok := columns["{{.Name}}"]; ok {
+		switch column := column.(type) {
+		{{- if .IsJSON}}
+		case *entity.ColumnJSONBytes:
+			b.col{{.GoName}} = append(b.col{{.GoName}}, column.Data()...)
+		case *entity.ColumnDynamic:
+			for i := 0; i < column.Len(); i++ {
+				raw, err := column.Get(i)
+				if err != nil {
+					raw = "null"
+				}
+				b.col{{.GoName}} = append(b.col{{.GoName}}, []byte(fmt.Sprint(raw)))
+			}
+		{{- else if eq .DataTypeName "FieldTypeVarChar"}}
+		case *entity.ColumnVarChar:
+			b.col{{.GoName}} = append(b.col{{.GoName}}, column.Data()...)
+		case *entity.ColumnString:
+			b.col{{.GoName}} = append(b.col{{.GoName}}, column.Data()...)
+		{{- else if .IsVector}}
+		case *entity.{{.ColumnType}}:
+			if column.Dim() != {{.Dim}} {
+				return fmt.Errorf("field {{.Name}} has dim %d, expected {{.Dim}}", column.Dim())
+			}
+			b.col{{.GoName}} = append(b.col{{.GoName}}, column.Data()...)
+		{{- else}}
+		case *entity.{{.ColumnType}}:
+			b.col{{.GoName}} = append(b.col{{.GoName}}, column.Data()...)
+		{{- end}}
+		default:
+			return fmt.Errorf("field {{.Name}} expects {{.DataType.Name}} column, got %T", column)
+		}
+	} else {
+		b.col{{.GoName}} = append(b.col{{.GoName}}, make([]{{.Storage}}, rowCount)...)
+	}
+	{{- end}}
+	return nil
+}
+{{end}}{{end}}`))
+
+// templateParams is the data passed to batchTemplate.
+type templateParams struct {
+	Package string
+	Batches []*batchDesc
+}
+
+// HasJSON returns whether any field is stored as JSON.
+func (p templateParams) HasJSON() bool {
+	for _, b := range p.Batches {
+		for _, f := range b.Fields {
+			if f.IsJSON() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// generate renders the batch source and formats it.
+func generate(params templateParams) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := batchTemplate.Execute(buf, params); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
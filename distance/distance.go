@@ -0,0 +1,292 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+// Package distance computes vector distances on client side, using the same metric types as index and search.
+//
+// Results of batch computation are row-major matrices with len(left) rows and len(right) columns,
+// the distance between left[i] and right[j] is at position i*len(right)+j.
+package distance
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+// ColumnName is the name of result column returned by Columns.
+const ColumnName = "distance"
+
+// IsFloatMetric returns whether metric type applies to float vectors.
+func IsFloatMetric(metric entity.MetricType) bool {
+	switch normalize(metric) {
+	case entity.L2, entity.IP, entity.COSINE:
+		return true
+	}
+	return false
+}
+
+// IsBinaryMetric returns whether metric type applies to binary vectors.
+func IsBinaryMetric(metric entity.MetricType) bool {
+	switch normalize(metric) {
+	case entity.HAMMING, entity.JACCARD, entity.TANIMOTO, entity.SUBSTRUCTURE, entity.SUPERSTRUCTURE:
+		return true
+	}
+	return false
+}
+
+func normalize(metric entity.MetricType) entity.MetricType {
+	return entity.MetricType(strings.ToUpper(string(metric)))
+}
+
+// L2 returns the squared euclidean distance, same as the search score of L2 metric.
+// Vectors must have the same dimension.
+func L2(a, b entity.FloatVector) float32 {
+	var sum float32
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return sum
+}
+
+// IP returns the inner product of two vectors.
+// Vectors must have the same dimension.
+func IP(a, b entity.FloatVector) float32 {
+	var sum float32
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+// Cosine returns the cosine similarity of two vectors, 0 if any of them is zero vector.
+// Vectors must have the same dimension.
+func Cosine(a, b entity.FloatVector) float32 {
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return float32(dot / (math.Sqrt(na) * math.Sqrt(nb)))
+}
+
+// Hamming returns the count of different bits.
+// Vectors must have the same byte length.
+func Hamming(a, b entity.BinaryVector) int32 {
+	var count int
+	for i := range a {
+		count += bits.OnesCount8(a[i] ^ b[i])
+	}
+	return int32(count)
+}
+
+// Jaccard returns the jaccard distance, which is 1 - |a & b| / |a | b|.
+// Distance between two zero vectors is 0.
+func Jaccard(a, b entity.BinaryVector) float32 {
+	inter, union := interUnion(a, b)
+	if union == 0 {
+		return 0
+	}
+	return 1 - float32(inter)/float32(union)
+}
+
+// Tanimoto returns the tanimoto distance, which is -log2(1 - jaccard distance).
+// +Inf is returned when two vectors have no common bit.
+func Tanimoto(a, b entity.BinaryVector) float32 {
+	inter, union := interUnion(a, b)
+	if union == 0 {
+		return 0
+	}
+	return float32(-math.Log2(float64(inter) / float64(union)))
+}
+
+// Substructure returns 0 if a is substructure of b, which means all bits set in a are set in b, otherwise 1.
+func Substructure(a, b entity.BinaryVector) float32 {
+	for i := range a {
+		if a[i]&b[i] != a[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// Superstructure returns 0 if a is superstructure of b, which means all bits set in b are set in a, otherwise 1.
+func Superstructure(a, b entity.BinaryVector) float32 {
+	return Substructure(b, a)
+}
+
+func interUnion(a, b entity.BinaryVector) (int, int) {
+	var inter, union int
+	for i := range a {
+		inter += bits.OnesCount8(a[i] & b[i])
+		union += bits.OnesCount8(a[i] | b[i])
+	}
+	return inter, union
+}
+
+// Float computes distance matrix of float vectors with L2, IP or COSINE metric.
+func Float(metric entity.MetricType, left, right []entity.FloatVector) ([]float32, error) {
+	var fn func(a, b entity.FloatVector) float32
+	switch normalize(metric) {
+	case entity.L2:
+		fn = L2
+	case entity.IP:
+		fn = IP
+	case entity.COSINE:
+		fn = Cosine
+	default:
+		return nil, fmt.Errorf("metric type %s not supported for float vector", metric)
+	}
+	if err := checkDim(len(left), len(right), func(i int) int { return left[i].Dim() }, func(i int) int { return right[i].Dim() }); err != nil {
+		return nil, err
+	}
+
+	result := make([]float32, 0, len(left)*len(right))
+	for _, l := range left {
+		for _, r := range right {
+			result = append(result, fn(l, r))
+		}
+	}
+	return result, nil
+}
+
+// Binary computes distance matrix of binary vectors with JACCARD, TANIMOTO, SUBSTRUCTURE or SUPERSTRUCTURE metric.
+// HAMMING distance is supported as well, use HammingMatrix for integral result.
+func Binary(metric entity.MetricType, left, right []entity.BinaryVector) ([]float32, error) {
+	var fn func(a, b entity.BinaryVector) float32
+	switch normalize(metric) {
+	case entity.HAMMING:
+		fn = func(a, b entity.BinaryVector) float32 { return float32(Hamming(a, b)) }
+	case entity.JACCARD:
+		fn = Jaccard
+	case entity.TANIMOTO:
+		fn = Tanimoto
+	case entity.SUBSTRUCTURE:
+		fn = Substructure
+	case entity.SUPERSTRUCTURE:
+		fn = Superstructure
+	default:
+		return nil, fmt.Errorf("metric type %s not supported for binary vector", metric)
+	}
+	if err := checkDim(len(left), len(right), func(i int) int { return len(left[i]) }, func(i int) int { return len(right[i]) }); err != nil {
+		return nil, err
+	}
+
+	result := make([]float32, 0, len(left)*len(right))
+	for _, l := range left {
+		for _, r := range right {
+			result = append(result, fn(l, r))
+		}
+	}
+	return result, nil
+}
+
+// HammingMatrix computes hamming distance matrix of binary vectors.
+func HammingMatrix(left, right []entity.BinaryVector) ([]int32, error) {
+	if err := checkDim(len(left), len(right), func(i int) int { return len(left[i]) }, func(i int) int { return len(right[i]) }); err != nil {
+		return nil, err
+	}
+	result := make([]int32, 0, len(left)*len(right))
+	for _, l := range left {
+		for _, r := range right {
+			result = append(result, Hamming(l, r))
+		}
+	}
+	return result, nil
+}
+
+// Columns computes distance matrix between two vector columns.
+// The result is a float column named "distance", or int32 column for HAMMING metric,
+// which is the same as the server side CalcDistance returns.
+func Columns(metric entity.MetricType, left, right entity.Column) (entity.Column, error) {
+	if left == nil || right == nil {
+		return nil, errors.New("operators cannot be nil")
+	}
+	switch l := left.(type) {
+	case *entity.ColumnFloatVector:
+		r, ok := right.(*entity.ColumnFloatVector)
+		if !ok {
+			return nil, fmt.Errorf("right column is %s, expected FloatVector", right.Type().Name())
+		}
+		result, err := Float(metric, floatVectors(l), floatVectors(r))
+		if err != nil {
+			return nil, err
+		}
+		return entity.NewColumnFloat(ColumnName, result), nil
+	case *entity.ColumnBinaryVector:
+		r, ok := right.(*entity.ColumnBinaryVector)
+		if !ok {
+			return nil, fmt.Errorf("right column is %s, expected BinaryVector", right.Type().Name())
+		}
+		if normalize(metric) == entity.HAMMING {
+			result, err := HammingMatrix(binaryVectors(l), binaryVectors(r))
+			if err != nil {
+				return nil, err
+			}
+			return entity.NewColumnInt32(ColumnName, result), nil
+		}
+		result, err := Binary(metric, binaryVectors(l), binaryVectors(r))
+		if err != nil {
+			return nil, err
+		}
+		return entity.NewColumnFloat(ColumnName, result), nil
+	default:
+		return nil, fmt.Errorf("left column is %s, which is not vector column", left.Type().Name())
+	}
+}
+
+func floatVectors(column *entity.ColumnFloatVector) []entity.FloatVector {
+	data := column.Data()
+	vectors := make([]entity.FloatVector, 0, len(data))
+	for _, v := range data {
+		vectors = append(vectors, entity.FloatVector(v))
+	}
+	return vectors
+}
+
+func binaryVectors(column *entity.ColumnBinaryVector) []entity.BinaryVector {
+	data := column.Data()
+	vectors := make([]entity.BinaryVector, 0, len(data))
+	for _, v := range data {
+		vectors = append(vectors, entity.BinaryVector(v))
+	}
+	return vectors
+}
+
+// checkDim checks all vectors of both sides have the same dimension.
+func checkDim(ln, rn int, ldim, rdim func(int) int) error {
+	dim := -1
+	check := func(side string, n int, dimOf func(int) int) error {
+		for i := 0; i < n; i++ {
+			d := dimOf(i)
+			if dim < 0 {
+				dim = d
+			}
+			if d != dim {
+				return fmt.Errorf("%s vector %d has dim %d, expected %d", side, i, d, dim)
+			}
+		}
+		return nil
+	}
+	if err := check("left", ln, ldim); err != nil {
+		return err
+	}
+	return check("right", rn, rdim)
+}
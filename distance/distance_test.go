@@ -0,0 +1,135 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package distance
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+func TestFloatMetrics(t *testing.T) {
+	a := entity.FloatVector{1, 2, 3}
+	b := entity.FloatVector{4, 5, 6}
+
+	assert.InDelta(t, 27, L2(a, b), 1e-6)
+	assert.InDelta(t, 32, IP(a, b), 1e-6)
+	assert.InDelta(t, 32/(math.Sqrt(14)*math.Sqrt(77)), Cosine(a, b), 1e-6)
+	assert.EqualValues(t, 0, Cosine(a, entity.FloatVector{0, 0, 0}))
+}
+
+func TestBinaryMetrics(t *testing.T) {
+	a := entity.BinaryVector{0b1100_0000}
+	b := entity.BinaryVector{0b1010_0000}
+	sub := entity.BinaryVector{0b1000_0000}
+
+	assert.EqualValues(t, 2, Hamming(a, b))
+	// inter 1, union 3
+	assert.InDelta(t, 1-1.0/3, Jaccard(a, b), 1e-6)
+	assert.InDelta(t, -math.Log2(1.0/3), Tanimoto(a, b), 1e-6)
+	assert.EqualValues(t, 0, Jaccard(entity.BinaryVector{0}, entity.BinaryVector{0}))
+	assert.True(t, math.IsInf(float64(Tanimoto(a, entity.BinaryVector{0b0011_0000})), 1))
+
+	assert.EqualValues(t, 0, Substructure(sub, a))
+	assert.EqualValues(t, 1, Substructure(a, sub))
+	assert.EqualValues(t, 0, Superstructure(a, sub))
+	assert.EqualValues(t, 1, Superstructure(sub, a))
+}
+
+func TestMetricKind(t *testing.T) {
+	assert.True(t, IsFloatMetric(entity.L2))
+	assert.True(t, IsFloatMetric("cosine"))
+	assert.False(t, IsFloatMetric(entity.HAMMING))
+	assert.True(t, IsBinaryMetric(entity.TANIMOTO))
+	assert.False(t, IsBinaryMetric(entity.IP))
+}
+
+func TestMatrix(t *testing.T) {
+	t.Run("float", func(t *testing.T) {
+		left := []entity.FloatVector{{0, 0}, {1, 1}}
+		right := []entity.FloatVector{{1, 0}, {0, 2}, {1, 1}}
+		result, err := Float(entity.L2, left, right)
+		require.NoError(t, err)
+		assert.Equal(t, []float32{1, 4, 2, 1, 2, 0}, result)
+
+		_, err = Float(entity.HAMMING, left, right)
+		assert.Error(t, err)
+
+		_, err = Float(entity.L2, left, []entity.FloatVector{{1, 2, 3}})
+		assert.Error(t, err)
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		left := []entity.BinaryVector{{0b1111_0000}}
+		right := []entity.BinaryVector{{0b1111_0000}, {0b0000_1111}}
+		result, err := Binary(entity.JACCARD, left, right)
+		require.NoError(t, err)
+		assert.Equal(t, []float32{0, 1}, result)
+
+		hamming, err := HammingMatrix(left, right)
+		require.NoError(t, err)
+		assert.Equal(t, []int32{0, 8}, hamming)
+
+		_, err = Binary(entity.L2, left, right)
+		assert.Error(t, err)
+
+		_, err = Binary(entity.JACCARD, left, []entity.BinaryVector{{1, 2}})
+		assert.Error(t, err)
+	})
+}
+
+func TestColumns(t *testing.T) {
+	t.Run("float_columns", func(t *testing.T) {
+		left := entity.NewColumnFloatVector("vector", 2, [][]float32{{1, 0}})
+		right := entity.NewColumnFloatVector("vector", 2, [][]float32{{1, 0}, {0, 1}})
+		column, err := Columns(entity.IP, left, right)
+		require.NoError(t, err)
+		fc, ok := column.(*entity.ColumnFloat)
+		require.True(t, ok)
+		assert.Equal(t, ColumnName, fc.Name())
+		assert.Equal(t, []float32{1, 0}, fc.Data())
+	})
+
+	t.Run("hamming_columns", func(t *testing.T) {
+		left := entity.NewColumnBinaryVector("vector", 8, [][]byte{{0b1111_0000}})
+		right := entity.NewColumnBinaryVector("vector", 8, [][]byte{{0b1111_1111}})
+		column, err := Columns(entity.HAMMING, left, right)
+		require.NoError(t, err)
+		ic, ok := column.(*entity.ColumnInt32)
+		require.True(t, ok)
+		assert.Equal(t, []int32{4}, ic.Data())
+
+		column, err = Columns(entity.SUBSTRUCTURE, left, right)
+		require.NoError(t, err)
+		fc, ok := column.(*entity.ColumnFloat)
+		require.True(t, ok)
+		assert.Equal(t, []float32{0}, fc.Data())
+	})
+
+	t.Run("invalid_columns", func(t *testing.T) {
+		fv := entity.NewColumnFloatVector("vector", 2, [][]float32{{1, 0}})
+		bv := entity.NewColumnBinaryVector("vector", 8, [][]byte{{0}})
+
+		_, err := Columns(entity.L2, nil, fv)
+		assert.Error(t, err)
+		_, err = Columns(entity.L2, fv, bv)
+		assert.Error(t, err)
+		_, err = Columns(entity.HAMMING, bv, fv)
+		assert.Error(t, err)
+		_, err = Columns(entity.L2, entity.NewColumnInt64("id", []int64{1}), fv)
+		assert.Error(t, err)
+	})
+}
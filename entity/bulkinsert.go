@@ -12,7 +12,8 @@ const (
 	BulkInsertCompleted        BulkInsertState = 6 // all indexes are successfully built and segments are able to be compacted as normal.
 	BulkInsertFailedAndCleaned BulkInsertState = 7 // the task failed and all segments it generated are cleaned up.
 
-	ImportProgress = "progress_percent"
+	ImportProgress     = "progress_percent"
+	ImportFailedReason = "failed_reason"
 )
 
 type BulkInsertTaskState struct {
@@ -36,3 +37,8 @@ func (state BulkInsertTaskState) Progress() int {
 	}
 	return 0
 }
+
+// FailedReason returns the failure reason reported by server, empty if not failed.
+func (state BulkInsertTaskState) FailedReason() string {
+	return state.Infos[ImportFailedReason]
+}
@@ -28,6 +28,7 @@ type Collection struct {
 	Loaded           bool
 	ConsistencyLevel ConsistencyLevel
 	ShardNum         int32
+	Aliases          []string
 }
 
 // Partition represent partition meta in Milvus
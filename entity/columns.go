@@ -35,6 +35,26 @@ type Column interface {
 	GetAsString(int) (string, error)
 	GetAsDouble(int) (float64, error)
 	GetAsBool(int) (bool, error)
+	// Slice returns a column with values in range [start, end), sharing underlying data.
+	Slice(start, end int) Column
+	// Concat returns a new column with values of the column followed by other column values.
+	Concat(Column) (Column, error)
+	// Clone returns a deep copy of the column.
+	Clone() Column
+}
+
+// sliceBounds clamps slice range into [0, l], end < 0 means to the end.
+func sliceBounds(start, end, l int) (int, int) {
+	if end < 0 || end > l {
+		end = l
+	}
+	if start < 0 {
+		start = 0
+	}
+	if start > end {
+		start = end
+	}
+	return start, end
 }
 
 // ColumnBase adds conversion methods support for fixed-type columns.
@@ -261,13 +281,7 @@ func FieldDataColumn(fd *schema.FieldData, begin, end int) (Column, error) {
 		if end < 0 {
 			end = int(len(data) / dim)
 		}
-		vector := make([][]float32, 0, end-begin) // shall not have remanunt
-		for i := begin; i < end; i++ {
-			v := make([]float32, dim)
-			copy(v, data[i*dim:(i+1)*dim])
-			vector = append(vector, v)
-		}
-		return NewColumnFloatVector(fd.GetFieldName(), dim, vector), nil
+		return NewColumnFloatVectorFlat(fd.GetFieldName(), dim, data[begin*dim:end*dim]), nil
 
 	case schema.DataType_BinaryVector:
 		vectors := fd.GetVectors()
@@ -284,13 +298,7 @@ func FieldDataColumn(fd *schema.FieldData, begin, end int) (Column, error) {
 		if end < 0 {
 			end = int(len(data) / blen)
 		}
-		vector := make([][]byte, 0, end-begin)
-		for i := begin; i < end; i++ {
-			v := make([]byte, blen)
-			copy(v, data[i*blen:(i+1)*blen])
-			vector = append(vector, v)
-		}
-		return NewColumnBinaryVector(fd.GetFieldName(), dim, vector), nil
+		return NewColumnBinaryVectorFlat(fd.GetFieldName(), dim, data[begin*blen:end*blen]), nil
 
 	default:
 		return nil, fmt.Errorf("unsupported data type %s", fd.GetType())
@@ -327,13 +335,7 @@ func FieldDataVector(fd *schema.FieldData) (Column, error) {
 		}
 		data := x.FloatVector.GetData()
 		dim := int(vectors.GetDim())
-		vector := make([][]float32, 0, len(data)/dim) // shall not have remanunt
-		for i := 0; i < len(data)/dim; i++ {
-			v := make([]float32, dim)
-			copy(v, data[i*dim:(i+1)*dim])
-			vector = append(vector, v)
-		}
-		return NewColumnFloatVector(fd.GetFieldName(), dim, vector), nil
+		return NewColumnFloatVectorFlat(fd.GetFieldName(), dim, data), nil
 	case schema.DataType_BinaryVector:
 		vectors := fd.GetVectors()
 		x, ok := vectors.GetData().(*schema.VectorField_BinaryVector)
@@ -345,14 +347,7 @@ func FieldDataVector(fd *schema.FieldData) (Column, error) {
 			return nil, errFieldDataTypeNotMatch
 		}
 		dim := int(vectors.GetDim())
-		blen := dim / 8
-		vector := make([][]byte, 0, len(data)/blen)
-		for i := 0; i < len(data)/blen; i++ {
-			v := make([]byte, blen)
-			copy(v, data[i*blen:(i+1)*blen])
-			vector = append(vector, v)
-		}
-		return NewColumnBinaryVector(fd.GetFieldName(), dim, vector), nil
+		return NewColumnBinaryVectorFlat(fd.GetFieldName(), dim, data), nil
 	default:
 		return nil, errors.New("unsupported data type")
 	}
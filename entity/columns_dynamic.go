@@ -12,6 +12,8 @@
 package entity
 
 import (
+	"fmt"
+
 	"github.com/cockroachdb/errors"
 	"github.com/tidwall/gjson"
 )
@@ -33,6 +35,29 @@ func (c *ColumnDynamic) Name() string {
 	return c.outputField
 }
 
+// Slice returns a dynamic column of the same output field with values in range [start, end).
+func (c *ColumnDynamic) Slice(start, end int) Column {
+	return NewColumnDynamic(c.ColumnJSONBytes.slice(start, end), c.outputField)
+}
+
+// Concat returns a new dynamic column with values of the column followed by values of other column.
+// Other column must be a dynamic column with the same output field.
+func (c *ColumnDynamic) Concat(other Column) (Column, error) {
+	o, ok := other.(*ColumnDynamic)
+	if !ok {
+		return nil, fmt.Errorf("cannot concat dynamic column with %T", other)
+	}
+	if o.outputField != c.outputField {
+		return nil, fmt.Errorf("cannot concat dynamic column %s with %s", c.outputField, o.outputField)
+	}
+	return NewColumnDynamic(c.ColumnJSONBytes.concat(o.ColumnJSONBytes), c.outputField), nil
+}
+
+// Clone returns a deep copy of the column.
+func (c *ColumnDynamic) Clone() Column {
+	return NewColumnDynamic(c.ColumnJSONBytes.clone(), c.outputField)
+}
+
 // Get returns element at idx as interface{}.
 // Overrides internal json column behavior, returns raw json data.
 func (c *ColumnDynamic) Get(idx int) (interface{}, error) {
@@ -142,6 +142,37 @@ func (s *ColumnDynamicSuite) TestIndexOutOfRange() {
 	s.Error(err)
 }
 
+func (s *ColumnDynamicSuite) TestSliceConcatClone() {
+	column := NewColumnDynamic(NewColumnJSONBytes("$meta", [][]byte{
+		[]byte(`{"field": 1}`),
+		[]byte(`{"field": 2}`),
+	}).WithIsDynamic(true), "field")
+
+	sliced := column.Slice(1, -1)
+	s.Equal("field", sliced.Name())
+	s.Equal(1, sliced.Len())
+	v, err := sliced.GetAsInt64(0)
+	s.NoError(err)
+	s.EqualValues(2, v)
+
+	concated, err := sliced.Concat(column)
+	s.Require().NoError(err)
+	s.Equal("field", concated.Name())
+	s.Equal(3, concated.Len())
+	v, err = concated.GetAsInt64(2)
+	s.NoError(err)
+	s.EqualValues(2, v)
+
+	_, err = column.Concat(NewColumnDynamic(column.ColumnJSONBytes, "other"))
+	s.Error(err)
+	_, err = column.Concat(column.ColumnJSONBytes)
+	s.Error(err)
+
+	cloned := column.Clone()
+	s.Equal("field", cloned.Name())
+	s.Equal(2, cloned.Len())
+}
+
 func TestColumnDynamic(t *testing.T) {
 	suite.Run(t, new(ColumnDynamicSuite))
 }
@@ -86,6 +86,8 @@ func (c *ColumnJSONBytes) AppendValue(i interface{}) error {
 	switch raw := i.(type) {
 	case []byte:
 		v = raw
+	case json.RawMessage:
+		v = raw
 	default:
 		k := reflect.TypeOf(i).Kind()
 		if k == reflect.Ptr {
@@ -114,11 +116,68 @@ func (c *ColumnJSONBytes) Data() [][]byte {
 	return c.values
 }
 
+// Slice returns a column with values in range [start, end), which shares underlying data with the column.
+// end < 0 means to the end of the column.
+func (c *ColumnJSONBytes) Slice(start, end int) Column {
+	return c.slice(start, end)
+}
+
+func (c *ColumnJSONBytes) slice(start, end int) *ColumnJSONBytes {
+	start, end = sliceBounds(start, end, c.Len())
+	return &ColumnJSONBytes{
+		name:      c.name,
+		values:    c.values[start:end:end],
+		isDynamic: c.isDynamic,
+	}
+}
+
+// Concat returns a new column with values of the column followed by values of other column.
+func (c *ColumnJSONBytes) Concat(other Column) (Column, error) {
+	o, ok := other.(*ColumnJSONBytes)
+	if !ok {
+		return nil, fmt.Errorf("cannot concat JSON column with %T", other)
+	}
+	return c.concat(o), nil
+}
+
+func (c *ColumnJSONBytes) concat(o *ColumnJSONBytes) *ColumnJSONBytes {
+	values := make([][]byte, 0, c.Len()+o.Len())
+	values = append(values, c.values...)
+	values = append(values, o.values...)
+	return &ColumnJSONBytes{
+		name:      c.name,
+		values:    values,
+		isDynamic: c.isDynamic,
+	}
+}
+
+// Clone returns a deep copy of the column.
+func (c *ColumnJSONBytes) Clone() Column {
+	return c.clone()
+}
+
+func (c *ColumnJSONBytes) clone() *ColumnJSONBytes {
+	values := make([][]byte, 0, len(c.values))
+	for _, v := range c.values {
+		values = append(values, append([]byte(nil), v...))
+	}
+	return &ColumnJSONBytes{
+		name:      c.name,
+		values:    values,
+		isDynamic: c.isDynamic,
+	}
+}
+
 func (c *ColumnJSONBytes) WithIsDynamic(isDynamic bool) *ColumnJSONBytes {
 	c.isDynamic = isDynamic
 	return c
 }
 
+// IsDynamic returns whether column is the dynamic field column.
+func (c *ColumnJSONBytes) IsDynamic() bool {
+	return c.isDynamic
+}
+
 // NewColumnJSONBytes composes a Column with json bytes.
 func NewColumnJSONBytes(name string, values [][]byte) *ColumnJSONBytes {
 	return &ColumnJSONBytes{
@@ -0,0 +1,366 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/tidwall/gjson"
+)
+
+// ErrJSONPathNotFound is returned when the json path does not exist in the value.
+var ErrJSONPathNotFound = errors.New("json path not found")
+
+// jsonRows returns the json value of row idx, non-existing result for missing value.
+type jsonRows func(idx int) (gjson.Result, error)
+
+// jsonPath converts path like `a.b[0]` into gjson path, special characters in keys are escaped.
+func jsonPath(path string) string {
+	var sb strings.Builder
+	for i := 0; i < len(path); i++ {
+		ch := path[i]
+		switch ch {
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				sb.WriteByte('\\')
+				sb.WriteByte(ch)
+				continue
+			}
+			if sb.Len() > 0 {
+				sb.WriteByte('.')
+			}
+			sb.WriteString(path[i+1 : i+end])
+			i += end
+		case '*', '?', '|', '#', '@', '!', '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(ch)
+		default:
+			sb.WriteByte(ch)
+		}
+	}
+	return sb.String()
+}
+
+func (f jsonRows) path(idx int, path string) (gjson.Result, error) {
+	r, err := f(idx)
+	if err != nil {
+		return r, err
+	}
+	if path != "" {
+		r = r.Get(jsonPath(path))
+	}
+	if !r.Exists() {
+		return r, fmt.Errorf("%w: %s", ErrJSONPathNotFound, path)
+	}
+	return r, nil
+}
+
+func (f jsonRows) getPath(idx int, path string) (interface{}, error) {
+	r, err := f.path(idx, path)
+	if err != nil {
+		return nil, err
+	}
+	return r.Value(), nil
+}
+
+func (f jsonRows) getPathString(idx int, path string) (string, error) {
+	r, err := f.path(idx, path)
+	if err != nil {
+		return "", err
+	}
+	if r.Type != gjson.String {
+		return "", fmt.Errorf("value of path %s is not string", path)
+	}
+	return r.Str, nil
+}
+
+func (f jsonRows) getPathInt64(idx int, path string) (int64, error) {
+	r, err := f.path(idx, path)
+	if err != nil {
+		return 0, err
+	}
+	if r.Type != gjson.Number {
+		return 0, fmt.Errorf("value of path %s is not number", path)
+	}
+	return r.Int(), nil
+}
+
+func (f jsonRows) getPathFloat64(idx int, path string) (float64, error) {
+	r, err := f.path(idx, path)
+	if err != nil {
+		return 0, err
+	}
+	if r.Type != gjson.Number {
+		return 0, fmt.Errorf("value of path %s is not number", path)
+	}
+	return r.Float(), nil
+}
+
+func (f jsonRows) getPathBool(idx int, path string) (bool, error) {
+	r, err := f.path(idx, path)
+	if err != nil {
+		return false, err
+	}
+	if !r.IsBool() {
+		return false, fmt.Errorf("value of path %s is not bool", path)
+	}
+	return r.Bool(), nil
+}
+
+func (f jsonRows) getPathTime(idx int, path string) (time.Time, error) {
+	r, err := f.path(idx, path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	switch r.Type {
+	case gjson.String:
+		return time.Parse(time.RFC3339Nano, r.Str)
+	case gjson.Number:
+		return time.Unix(r.Int(), 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("value of path %s is not time", path)
+	}
+}
+
+func (f jsonRows) getPathArray(idx int, path string) ([]gjson.Result, error) {
+	r, err := f.path(idx, path)
+	if err != nil {
+		return nil, err
+	}
+	if !r.IsArray() {
+		return nil, fmt.Errorf("value of path %s is not array", path)
+	}
+	return r.Array(), nil
+}
+
+func (f jsonRows) getPathStrings(idx int, path string) ([]string, error) {
+	items, err := f.getPathArray(idx, path)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, 0, len(items))
+	for i, item := range items {
+		if item.Type != gjson.String {
+			return nil, fmt.Errorf("element %d of path %s is not string", i, path)
+		}
+		values = append(values, item.Str)
+	}
+	return values, nil
+}
+
+func (f jsonRows) getPathInt64s(idx int, path string) ([]int64, error) {
+	items, err := f.getPathArray(idx, path)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]int64, 0, len(items))
+	for i, item := range items {
+		if item.Type != gjson.Number {
+			return nil, fmt.Errorf("element %d of path %s is not number", i, path)
+		}
+		values = append(values, item.Int())
+	}
+	return values, nil
+}
+
+func (f jsonRows) getPathFloat64s(idx int, path string) ([]float64, error) {
+	items, err := f.getPathArray(idx, path)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]float64, 0, len(items))
+	for i, item := range items {
+		if item.Type != gjson.Number {
+			return nil, fmt.Errorf("element %d of path %s is not number", i, path)
+		}
+		values = append(values, item.Float())
+	}
+	return values, nil
+}
+
+func (f jsonRows) decodeInto(idx int, v interface{}) error {
+	r, err := f(idx)
+	if err != nil {
+		return err
+	}
+	if !r.Exists() {
+		return fmt.Errorf("%w: row %d has no value", ErrJSONPathNotFound, idx)
+	}
+	return json.Unmarshal([]byte(r.Raw), v)
+}
+
+// decodeAll decodes all rows as a json array into v, missing values are decoded as null.
+func (f jsonRows) decodeAll(n int, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("receiver must be a non-nil pointer to slice, got %T", v)
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i := 0; i < n; i++ {
+		r, err := f(i)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if !r.Exists() {
+			buf.WriteString("null")
+			continue
+		}
+		buf.WriteString(r.Raw)
+	}
+	buf.WriteByte(']')
+	return json.Unmarshal(buf.Bytes(), v)
+}
+
+func (c *ColumnJSONBytes) jsonRows() jsonRows {
+	return func(idx int) (gjson.Result, error) {
+		bs, err := c.ValueByIdx(idx)
+		if err != nil {
+			return gjson.Result{}, err
+		}
+		return gjson.ParseBytes(bs), nil
+	}
+}
+
+// GetPath returns the decoded value at json path of row idx, path is like `a.b[0]`.
+// Objects are returned as map[string]interface{}, arrays as []interface{} and numbers as float64.
+func (c *ColumnJSONBytes) GetPath(idx int, path string) (interface{}, error) {
+	return c.jsonRows().getPath(idx, path)
+}
+
+// GetPathString returns the string value at json path of row idx.
+func (c *ColumnJSONBytes) GetPathString(idx int, path string) (string, error) {
+	return c.jsonRows().getPathString(idx, path)
+}
+
+// GetPathInt64 returns the integer value at json path of row idx.
+func (c *ColumnJSONBytes) GetPathInt64(idx int, path string) (int64, error) {
+	return c.jsonRows().getPathInt64(idx, path)
+}
+
+// GetPathFloat64 returns the number value at json path of row idx.
+func (c *ColumnJSONBytes) GetPathFloat64(idx int, path string) (float64, error) {
+	return c.jsonRows().getPathFloat64(idx, path)
+}
+
+// GetPathBool returns the bool value at json path of row idx.
+func (c *ColumnJSONBytes) GetPathBool(idx int, path string) (bool, error) {
+	return c.jsonRows().getPathBool(idx, path)
+}
+
+// GetPathTime returns the time value at json path of row idx,
+// which is either RFC3339 string or unix timestamp in seconds.
+func (c *ColumnJSONBytes) GetPathTime(idx int, path string) (time.Time, error) {
+	return c.jsonRows().getPathTime(idx, path)
+}
+
+// GetPathStrings returns the string array at json path of row idx.
+func (c *ColumnJSONBytes) GetPathStrings(idx int, path string) ([]string, error) {
+	return c.jsonRows().getPathStrings(idx, path)
+}
+
+// GetPathInt64s returns the integer array at json path of row idx.
+func (c *ColumnJSONBytes) GetPathInt64s(idx int, path string) ([]int64, error) {
+	return c.jsonRows().getPathInt64s(idx, path)
+}
+
+// GetPathFloat64s returns the number array at json path of row idx.
+func (c *ColumnJSONBytes) GetPathFloat64s(idx int, path string) ([]float64, error) {
+	return c.jsonRows().getPathFloat64s(idx, path)
+}
+
+// DecodeInto unmarshals json value of row idx into v.
+func (c *ColumnJSONBytes) DecodeInto(idx int, v interface{}) error {
+	return c.jsonRows().decodeInto(idx, v)
+}
+
+// DecodeAll unmarshals json values of all rows into v, which shall be a pointer to slice.
+func (c *ColumnJSONBytes) DecodeAll(v interface{}) error {
+	return c.jsonRows().decodeAll(c.Len(), v)
+}
+
+// jsonRows of dynamic column returns the value of output field.
+func (c *ColumnDynamic) jsonRows() jsonRows {
+	return func(idx int) (gjson.Result, error) {
+		bs, err := c.ValueByIdx(idx)
+		if err != nil {
+			return gjson.Result{}, err
+		}
+		return gjson.GetBytes(bs, c.outputField), nil
+	}
+}
+
+// GetPath returns the decoded value at json path of output field value in row idx.
+func (c *ColumnDynamic) GetPath(idx int, path string) (interface{}, error) {
+	return c.jsonRows().getPath(idx, path)
+}
+
+// GetPathString returns the string value at json path of output field value in row idx.
+func (c *ColumnDynamic) GetPathString(idx int, path string) (string, error) {
+	return c.jsonRows().getPathString(idx, path)
+}
+
+// GetPathInt64 returns the integer value at json path of output field value in row idx.
+func (c *ColumnDynamic) GetPathInt64(idx int, path string) (int64, error) {
+	return c.jsonRows().getPathInt64(idx, path)
+}
+
+// GetPathFloat64 returns the number value at json path of output field value in row idx.
+func (c *ColumnDynamic) GetPathFloat64(idx int, path string) (float64, error) {
+	return c.jsonRows().getPathFloat64(idx, path)
+}
+
+// GetPathBool returns the bool value at json path of output field value in row idx.
+func (c *ColumnDynamic) GetPathBool(idx int, path string) (bool, error) {
+	return c.jsonRows().getPathBool(idx, path)
+}
+
+// GetPathTime returns the time value at json path of output field value in row idx.
+func (c *ColumnDynamic) GetPathTime(idx int, path string) (time.Time, error) {
+	return c.jsonRows().getPathTime(idx, path)
+}
+
+// GetPathStrings returns the string array at json path of output field value in row idx.
+func (c *ColumnDynamic) GetPathStrings(idx int, path string) ([]string, error) {
+	return c.jsonRows().getPathStrings(idx, path)
+}
+
+// GetPathInt64s returns the integer array at json path of output field value in row idx.
+func (c *ColumnDynamic) GetPathInt64s(idx int, path string) ([]int64, error) {
+	return c.jsonRows().getPathInt64s(idx, path)
+}
+
+// GetPathFloat64s returns the number array at json path of output field value in row idx.
+func (c *ColumnDynamic) GetPathFloat64s(idx int, path string) ([]float64, error) {
+	return c.jsonRows().getPathFloat64s(idx, path)
+}
+
+// DecodeInto unmarshals output field value of row idx into v.
+func (c *ColumnDynamic) DecodeInto(idx int, v interface{}) error {
+	return c.jsonRows().decodeInto(idx, v)
+}
+
+// DecodeAll unmarshals output field values of all rows into v, which shall be a pointer to slice.
+// Rows missing the output field are decoded from null.
+func (c *ColumnDynamic) DecodeAll(v interface{}) error {
+	return c.jsonRows().decodeAll(c.Len(), v)
+}
@@ -0,0 +1,165 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ColumnJSONPathSuite struct {
+	suite.Suite
+	column *ColumnJSONBytes
+}
+
+func (s *ColumnJSONPathSuite) SetupTest() {
+	s.column = NewColumnJSONBytes("json", [][]byte{
+		[]byte(`{"a": {"b": [1, 2, 3], "c": "str", "d": true, "e": 1.5}, "tags": ["x", "y"], "ts": "2023-01-02T03:04:05Z", "unix": 1672628645, "k*": 1}`),
+		[]byte(`{"a": {"b": [4]}, "nested": [[1, 2], [3, 4]]}`),
+	})
+}
+
+func (s *ColumnJSONPathSuite) TestJSONPath() {
+	s.Equal("a.b.0", jsonPath("a.b[0]"))
+	s.Equal("0.a", jsonPath("[0].a"))
+	s.Equal("a.0.1", jsonPath("a[0][1]"))
+	s.Equal(`k\*`, jsonPath("k*"))
+}
+
+func (s *ColumnJSONPathSuite) TestGetPath() {
+	v, err := s.column.GetPath(0, "a.b[1]")
+	s.NoError(err)
+	s.EqualValues(2, v)
+
+	v, err = s.column.GetPath(0, "a")
+	s.NoError(err)
+	s.IsType(map[string]interface{}{}, v)
+
+	v, err = s.column.GetPath(1, "nested[1][0]")
+	s.NoError(err)
+	s.EqualValues(3, v)
+
+	v, err = s.column.GetPath(0, "k*")
+	s.NoError(err)
+	s.EqualValues(1, v)
+
+	_, err = s.column.GetPath(1, "a.c")
+	s.ErrorIs(err, ErrJSONPathNotFound)
+	_, err = s.column.GetPath(2, "a")
+	s.Error(err)
+}
+
+func (s *ColumnJSONPathSuite) TestTyped() {
+	str, err := s.column.GetPathString(0, "a.c")
+	s.NoError(err)
+	s.Equal("str", str)
+	_, err = s.column.GetPathString(0, "a.d")
+	s.Error(err)
+
+	i, err := s.column.GetPathInt64(0, "a.b[2]")
+	s.NoError(err)
+	s.EqualValues(3, i)
+	_, err = s.column.GetPathInt64(0, "a.c")
+	s.Error(err)
+
+	f, err := s.column.GetPathFloat64(0, "a.e")
+	s.NoError(err)
+	s.Equal(1.5, f)
+
+	b, err := s.column.GetPathBool(0, "a.d")
+	s.NoError(err)
+	s.True(b)
+	_, err = s.column.GetPathBool(0, "a.e")
+	s.Error(err)
+
+	expected := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	ts, err := s.column.GetPathTime(0, "ts")
+	s.NoError(err)
+	s.True(expected.Equal(ts))
+	ts, err = s.column.GetPathTime(0, "unix")
+	s.NoError(err)
+	s.True(expected.Equal(ts))
+	_, err = s.column.GetPathTime(0, "a.d")
+	s.Error(err)
+}
+
+func (s *ColumnJSONPathSuite) TestSlices() {
+	strs, err := s.column.GetPathStrings(0, "tags")
+	s.NoError(err)
+	s.Equal([]string{"x", "y"}, strs)
+	_, err = s.column.GetPathStrings(0, "a.b")
+	s.Error(err)
+
+	ints, err := s.column.GetPathInt64s(0, "a.b")
+	s.NoError(err)
+	s.Equal([]int64{1, 2, 3}, ints)
+	_, err = s.column.GetPathInt64s(0, "a.c")
+	s.Error(err)
+
+	floats, err := s.column.GetPathFloat64s(1, "nested[0]")
+	s.NoError(err)
+	s.Equal([]float64{1, 2}, floats)
+	_, err = s.column.GetPathFloat64s(0, "tags")
+	s.Error(err)
+}
+
+func (s *ColumnJSONPathSuite) TestDecode() {
+	type inner struct {
+		B []int `json:"b"`
+	}
+	type row struct {
+		A inner `json:"a"`
+	}
+
+	var r row
+	s.NoError(s.column.DecodeInto(1, &r))
+	s.Equal([]int{4}, r.A.B)
+	s.Error(s.column.DecodeInto(2, &r))
+
+	var rows []row
+	s.NoError(s.column.DecodeAll(&rows))
+	s.Equal([]row{{A: inner{B: []int{1, 2, 3}}}, {A: inner{B: []int{4}}}}, rows)
+	s.Error(s.column.DecodeAll(rows))
+}
+
+func (s *ColumnJSONPathSuite) TestDynamic() {
+	column := NewColumnDynamic(s.column, "a")
+
+	v, err := column.GetPathInt64(0, "b[0]")
+	s.NoError(err)
+	s.EqualValues(1, v)
+	strs, err := NewColumnDynamic(s.column, "tags").GetPathStrings(0, "")
+	s.NoError(err)
+	s.Equal([]string{"x", "y"}, strs)
+
+	var values []*struct {
+		C string `json:"c"`
+	}
+	s.NoError(NewColumnDynamic(s.column, "a").DecodeAll(&values))
+	s.Require().Len(values, 2)
+	s.Equal("str", values[0].C)
+	s.Equal("", values[1].C)
+
+	var ts []*string
+	s.NoError(NewColumnDynamic(s.column, "ts").DecodeAll(&ts))
+	s.Require().Len(ts, 2)
+	s.Nil(ts[1])
+
+	var m map[string]interface{}
+	s.Error(NewColumnDynamic(s.column, "ts").DecodeInto(1, &m))
+}
+
+func TestColumnJSONPath(t *testing.T) {
+	suite.Run(t, new(ColumnJSONPathSuite))
+}
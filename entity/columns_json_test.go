@@ -78,6 +78,26 @@ func (s *ColumnJSONBytesSuite) TestAttrMethods() {
 	})
 }
 
+func (s *ColumnJSONBytesSuite) TestSliceConcatClone() {
+	column := NewColumnJSONBytes("json", [][]byte{[]byte(`{"a":1}`), []byte(`{"a":2}`), []byte(`{"a":3}`)}).WithIsDynamic(true)
+
+	sliced := column.Slice(1, 3).(*ColumnJSONBytes)
+	s.Equal(2, sliced.Len())
+	s.True(sliced.IsDynamic())
+	s.Equal([]byte(`{"a":2}`), sliced.Data()[0])
+
+	concated, err := sliced.Concat(column.Slice(0, 1))
+	s.Require().NoError(err)
+	s.Equal([][]byte{[]byte(`{"a":2}`), []byte(`{"a":3}`), []byte(`{"a":1}`)}, concated.(*ColumnJSONBytes).Data())
+	_, err = column.Concat(NewColumnVarChar("json", nil))
+	s.Error(err)
+
+	cloned := column.Clone().(*ColumnJSONBytes)
+	s.True(cloned.IsDynamic())
+	cloned.Data()[0][1] = 'b'
+	s.Equal([]byte(`{"a":1}`), column.Data()[0])
+}
+
 func TestColumnJSONBytes(t *testing.T) {
 	suite.Run(t, new(ColumnJSONBytesSuite))
 }
@@ -89,6 +89,41 @@ func (c *ColumnBool) Data() []bool {
 	return c.values
 }
 
+// Slice returns a column with values in range [start, end), which shares underlying data with the column.
+// end < 0 means to the end of the column.
+func (c *ColumnBool) Slice(start, end int) Column {
+	start, end = sliceBounds(start, end, c.Len())
+	return &ColumnBool{
+		name:   c.name,
+		values: c.values[start:end:end],
+	}
+}
+
+// Concat returns a new column with values of the column followed by values of other column.
+func (c *ColumnBool) Concat(other Column) (Column, error) {
+	o, ok := other.(*ColumnBool)
+	if !ok {
+		return nil, fmt.Errorf("cannot concat Bool column with %T", other)
+	}
+	values := make([]bool, 0, c.Len()+o.Len())
+	values = append(values, c.values...)
+	values = append(values, o.values...)
+	return &ColumnBool{
+		name:   c.name,
+		values: values,
+	}, nil
+}
+
+// Clone returns a deep copy of the column.
+func (c *ColumnBool) Clone() Column {
+	values := make([]bool, len(c.values))
+	copy(values, c.values)
+	return &ColumnBool{
+		name:   c.name,
+		values: values,
+	}
+}
+
 // NewColumnBool auto generated constructor
 func NewColumnBool(name string, values []bool) *ColumnBool {
 	return &ColumnBool{
@@ -176,6 +211,41 @@ func (c *ColumnInt8) Data() []int8 {
 	return c.values
 }
 
+// Slice returns a column with values in range [start, end), which shares underlying data with the column.
+// end < 0 means to the end of the column.
+func (c *ColumnInt8) Slice(start, end int) Column {
+	start, end = sliceBounds(start, end, c.Len())
+	return &ColumnInt8{
+		name:   c.name,
+		values: c.values[start:end:end],
+	}
+}
+
+// Concat returns a new column with values of the column followed by values of other column.
+func (c *ColumnInt8) Concat(other Column) (Column, error) {
+	o, ok := other.(*ColumnInt8)
+	if !ok {
+		return nil, fmt.Errorf("cannot concat Int8 column with %T", other)
+	}
+	values := make([]int8, 0, c.Len()+o.Len())
+	values = append(values, c.values...)
+	values = append(values, o.values...)
+	return &ColumnInt8{
+		name:   c.name,
+		values: values,
+	}, nil
+}
+
+// Clone returns a deep copy of the column.
+func (c *ColumnInt8) Clone() Column {
+	values := make([]int8, len(c.values))
+	copy(values, c.values)
+	return &ColumnInt8{
+		name:   c.name,
+		values: values,
+	}
+}
+
 // NewColumnInt8 auto generated constructor
 func NewColumnInt8(name string, values []int8) *ColumnInt8 {
 	return &ColumnInt8{
@@ -263,6 +333,41 @@ func (c *ColumnInt16) Data() []int16 {
 	return c.values
 }
 
+// Slice returns a column with values in range [start, end), which shares underlying data with the column.
+// end < 0 means to the end of the column.
+func (c *ColumnInt16) Slice(start, end int) Column {
+	start, end = sliceBounds(start, end, c.Len())
+	return &ColumnInt16{
+		name:   c.name,
+		values: c.values[start:end:end],
+	}
+}
+
+// Concat returns a new column with values of the column followed by values of other column.
+func (c *ColumnInt16) Concat(other Column) (Column, error) {
+	o, ok := other.(*ColumnInt16)
+	if !ok {
+		return nil, fmt.Errorf("cannot concat Int16 column with %T", other)
+	}
+	values := make([]int16, 0, c.Len()+o.Len())
+	values = append(values, c.values...)
+	values = append(values, o.values...)
+	return &ColumnInt16{
+		name:   c.name,
+		values: values,
+	}, nil
+}
+
+// Clone returns a deep copy of the column.
+func (c *ColumnInt16) Clone() Column {
+	values := make([]int16, len(c.values))
+	copy(values, c.values)
+	return &ColumnInt16{
+		name:   c.name,
+		values: values,
+	}
+}
+
 // NewColumnInt16 auto generated constructor
 func NewColumnInt16(name string, values []int16) *ColumnInt16 {
 	return &ColumnInt16{
@@ -350,6 +455,41 @@ func (c *ColumnInt32) Data() []int32 {
 	return c.values
 }
 
+// Slice returns a column with values in range [start, end), which shares underlying data with the column.
+// end < 0 means to the end of the column.
+func (c *ColumnInt32) Slice(start, end int) Column {
+	start, end = sliceBounds(start, end, c.Len())
+	return &ColumnInt32{
+		name:   c.name,
+		values: c.values[start:end:end],
+	}
+}
+
+// Concat returns a new column with values of the column followed by values of other column.
+func (c *ColumnInt32) Concat(other Column) (Column, error) {
+	o, ok := other.(*ColumnInt32)
+	if !ok {
+		return nil, fmt.Errorf("cannot concat Int32 column with %T", other)
+	}
+	values := make([]int32, 0, c.Len()+o.Len())
+	values = append(values, c.values...)
+	values = append(values, o.values...)
+	return &ColumnInt32{
+		name:   c.name,
+		values: values,
+	}, nil
+}
+
+// Clone returns a deep copy of the column.
+func (c *ColumnInt32) Clone() Column {
+	values := make([]int32, len(c.values))
+	copy(values, c.values)
+	return &ColumnInt32{
+		name:   c.name,
+		values: values,
+	}
+}
+
 // NewColumnInt32 auto generated constructor
 func NewColumnInt32(name string, values []int32) *ColumnInt32 {
 	return &ColumnInt32{
@@ -437,6 +577,41 @@ func (c *ColumnInt64) Data() []int64 {
 	return c.values
 }
 
+// Slice returns a column with values in range [start, end), which shares underlying data with the column.
+// end < 0 means to the end of the column.
+func (c *ColumnInt64) Slice(start, end int) Column {
+	start, end = sliceBounds(start, end, c.Len())
+	return &ColumnInt64{
+		name:   c.name,
+		values: c.values[start:end:end],
+	}
+}
+
+// Concat returns a new column with values of the column followed by values of other column.
+func (c *ColumnInt64) Concat(other Column) (Column, error) {
+	o, ok := other.(*ColumnInt64)
+	if !ok {
+		return nil, fmt.Errorf("cannot concat Int64 column with %T", other)
+	}
+	values := make([]int64, 0, c.Len()+o.Len())
+	values = append(values, c.values...)
+	values = append(values, o.values...)
+	return &ColumnInt64{
+		name:   c.name,
+		values: values,
+	}, nil
+}
+
+// Clone returns a deep copy of the column.
+func (c *ColumnInt64) Clone() Column {
+	values := make([]int64, len(c.values))
+	copy(values, c.values)
+	return &ColumnInt64{
+		name:   c.name,
+		values: values,
+	}
+}
+
 // NewColumnInt64 auto generated constructor
 func NewColumnInt64(name string, values []int64) *ColumnInt64 {
 	return &ColumnInt64{
@@ -524,6 +699,41 @@ func (c *ColumnFloat) Data() []float32 {
 	return c.values
 }
 
+// Slice returns a column with values in range [start, end), which shares underlying data with the column.
+// end < 0 means to the end of the column.
+func (c *ColumnFloat) Slice(start, end int) Column {
+	start, end = sliceBounds(start, end, c.Len())
+	return &ColumnFloat{
+		name:   c.name,
+		values: c.values[start:end:end],
+	}
+}
+
+// Concat returns a new column with values of the column followed by values of other column.
+func (c *ColumnFloat) Concat(other Column) (Column, error) {
+	o, ok := other.(*ColumnFloat)
+	if !ok {
+		return nil, fmt.Errorf("cannot concat Float column with %T", other)
+	}
+	values := make([]float32, 0, c.Len()+o.Len())
+	values = append(values, c.values...)
+	values = append(values, o.values...)
+	return &ColumnFloat{
+		name:   c.name,
+		values: values,
+	}, nil
+}
+
+// Clone returns a deep copy of the column.
+func (c *ColumnFloat) Clone() Column {
+	values := make([]float32, len(c.values))
+	copy(values, c.values)
+	return &ColumnFloat{
+		name:   c.name,
+		values: values,
+	}
+}
+
 // NewColumnFloat auto generated constructor
 func NewColumnFloat(name string, values []float32) *ColumnFloat {
 	return &ColumnFloat{
@@ -611,6 +821,41 @@ func (c *ColumnDouble) Data() []float64 {
 	return c.values
 }
 
+// Slice returns a column with values in range [start, end), which shares underlying data with the column.
+// end < 0 means to the end of the column.
+func (c *ColumnDouble) Slice(start, end int) Column {
+	start, end = sliceBounds(start, end, c.Len())
+	return &ColumnDouble{
+		name:   c.name,
+		values: c.values[start:end:end],
+	}
+}
+
+// Concat returns a new column with values of the column followed by values of other column.
+func (c *ColumnDouble) Concat(other Column) (Column, error) {
+	o, ok := other.(*ColumnDouble)
+	if !ok {
+		return nil, fmt.Errorf("cannot concat Double column with %T", other)
+	}
+	values := make([]float64, 0, c.Len()+o.Len())
+	values = append(values, c.values...)
+	values = append(values, o.values...)
+	return &ColumnDouble{
+		name:   c.name,
+		values: values,
+	}, nil
+}
+
+// Clone returns a deep copy of the column.
+func (c *ColumnDouble) Clone() Column {
+	values := make([]float64, len(c.values))
+	copy(values, c.values)
+	return &ColumnDouble{
+		name:   c.name,
+		values: values,
+	}
+}
+
 // NewColumnDouble auto generated constructor
 func NewColumnDouble(name string, values []float64) *ColumnDouble {
 	return &ColumnDouble{
@@ -698,6 +943,41 @@ func (c *ColumnString) Data() []string {
 	return c.values
 }
 
+// Slice returns a column with values in range [start, end), which shares underlying data with the column.
+// end < 0 means to the end of the column.
+func (c *ColumnString) Slice(start, end int) Column {
+	start, end = sliceBounds(start, end, c.Len())
+	return &ColumnString{
+		name:   c.name,
+		values: c.values[start:end:end],
+	}
+}
+
+// Concat returns a new column with values of the column followed by values of other column.
+func (c *ColumnString) Concat(other Column) (Column, error) {
+	o, ok := other.(*ColumnString)
+	if !ok {
+		return nil, fmt.Errorf("cannot concat String column with %T", other)
+	}
+	values := make([]string, 0, c.Len()+o.Len())
+	values = append(values, c.values...)
+	values = append(values, o.values...)
+	return &ColumnString{
+		name:   c.name,
+		values: values,
+	}, nil
+}
+
+// Clone returns a deep copy of the column.
+func (c *ColumnString) Clone() Column {
+	values := make([]string, len(c.values))
+	copy(values, c.values)
+	return &ColumnString{
+		name:   c.name,
+		values: values,
+	}
+}
+
 // NewColumnString auto generated constructor
 func NewColumnString(name string, values []string) *ColumnString {
 	return &ColumnString{
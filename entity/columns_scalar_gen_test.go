@@ -54,6 +54,26 @@ func TestColumnBool(t *testing.T) {
 			assert.Equal(t, column.values[i], v)
 		}
 	})
+
+	t.Run("test column slice concat clone", func(t *testing.T) {
+		sliced := column.Slice(2, 5)
+		assert.Equal(t, columnName, sliced.Name())
+		assert.Equal(t, 3, sliced.Len())
+		assert.EqualValues(t, v[2:5], sliced.(*ColumnBool).Data())
+		assert.Equal(t, columnLen-2, column.Slice(2, -1).Len())
+		assert.Equal(t, 0, column.Slice(5, 2).Len())
+		assert.Equal(t, columnLen, column.Slice(-1, columnLen+10).Len())
+
+		concated, err := column.Slice(0, 2).Concat(column.Slice(2, -1))
+		assert.NoError(t, err)
+		assert.EqualValues(t, v, concated.(*ColumnBool).Data())
+		_, err = column.Concat(&ColumnJSONBytes{})
+		assert.Error(t, err)
+
+		cloned := column.Clone()
+		assert.Equal(t, columnName, cloned.Name())
+		assert.EqualValues(t, v, cloned.(*ColumnBool).Data())
+	})
 }
 
 func TestFieldDataBoolColumn(t *testing.T) {
@@ -159,6 +179,26 @@ func TestColumnInt8(t *testing.T) {
 			assert.Equal(t, column.values[i], v)
 		}
 	})
+
+	t.Run("test column slice concat clone", func(t *testing.T) {
+		sliced := column.Slice(2, 5)
+		assert.Equal(t, columnName, sliced.Name())
+		assert.Equal(t, 3, sliced.Len())
+		assert.EqualValues(t, v[2:5], sliced.(*ColumnInt8).Data())
+		assert.Equal(t, columnLen-2, column.Slice(2, -1).Len())
+		assert.Equal(t, 0, column.Slice(5, 2).Len())
+		assert.Equal(t, columnLen, column.Slice(-1, columnLen+10).Len())
+
+		concated, err := column.Slice(0, 2).Concat(column.Slice(2, -1))
+		assert.NoError(t, err)
+		assert.EqualValues(t, v, concated.(*ColumnInt8).Data())
+		_, err = column.Concat(&ColumnJSONBytes{})
+		assert.Error(t, err)
+
+		cloned := column.Clone()
+		assert.Equal(t, columnName, cloned.Name())
+		assert.EqualValues(t, v, cloned.(*ColumnInt8).Data())
+	})
 }
 
 func TestFieldDataInt8Column(t *testing.T) {
@@ -264,6 +304,26 @@ func TestColumnInt16(t *testing.T) {
 			assert.Equal(t, column.values[i], v)
 		}
 	})
+
+	t.Run("test column slice concat clone", func(t *testing.T) {
+		sliced := column.Slice(2, 5)
+		assert.Equal(t, columnName, sliced.Name())
+		assert.Equal(t, 3, sliced.Len())
+		assert.EqualValues(t, v[2:5], sliced.(*ColumnInt16).Data())
+		assert.Equal(t, columnLen-2, column.Slice(2, -1).Len())
+		assert.Equal(t, 0, column.Slice(5, 2).Len())
+		assert.Equal(t, columnLen, column.Slice(-1, columnLen+10).Len())
+
+		concated, err := column.Slice(0, 2).Concat(column.Slice(2, -1))
+		assert.NoError(t, err)
+		assert.EqualValues(t, v, concated.(*ColumnInt16).Data())
+		_, err = column.Concat(&ColumnJSONBytes{})
+		assert.Error(t, err)
+
+		cloned := column.Clone()
+		assert.Equal(t, columnName, cloned.Name())
+		assert.EqualValues(t, v, cloned.(*ColumnInt16).Data())
+	})
 }
 
 func TestFieldDataInt16Column(t *testing.T) {
@@ -369,6 +429,26 @@ func TestColumnInt32(t *testing.T) {
 			assert.Equal(t, column.values[i], v)
 		}
 	})
+
+	t.Run("test column slice concat clone", func(t *testing.T) {
+		sliced := column.Slice(2, 5)
+		assert.Equal(t, columnName, sliced.Name())
+		assert.Equal(t, 3, sliced.Len())
+		assert.EqualValues(t, v[2:5], sliced.(*ColumnInt32).Data())
+		assert.Equal(t, columnLen-2, column.Slice(2, -1).Len())
+		assert.Equal(t, 0, column.Slice(5, 2).Len())
+		assert.Equal(t, columnLen, column.Slice(-1, columnLen+10).Len())
+
+		concated, err := column.Slice(0, 2).Concat(column.Slice(2, -1))
+		assert.NoError(t, err)
+		assert.EqualValues(t, v, concated.(*ColumnInt32).Data())
+		_, err = column.Concat(&ColumnJSONBytes{})
+		assert.Error(t, err)
+
+		cloned := column.Clone()
+		assert.Equal(t, columnName, cloned.Name())
+		assert.EqualValues(t, v, cloned.(*ColumnInt32).Data())
+	})
 }
 
 func TestFieldDataInt32Column(t *testing.T) {
@@ -474,6 +554,26 @@ func TestColumnInt64(t *testing.T) {
 			assert.Equal(t, column.values[i], v)
 		}
 	})
+
+	t.Run("test column slice concat clone", func(t *testing.T) {
+		sliced := column.Slice(2, 5)
+		assert.Equal(t, columnName, sliced.Name())
+		assert.Equal(t, 3, sliced.Len())
+		assert.EqualValues(t, v[2:5], sliced.(*ColumnInt64).Data())
+		assert.Equal(t, columnLen-2, column.Slice(2, -1).Len())
+		assert.Equal(t, 0, column.Slice(5, 2).Len())
+		assert.Equal(t, columnLen, column.Slice(-1, columnLen+10).Len())
+
+		concated, err := column.Slice(0, 2).Concat(column.Slice(2, -1))
+		assert.NoError(t, err)
+		assert.EqualValues(t, v, concated.(*ColumnInt64).Data())
+		_, err = column.Concat(&ColumnJSONBytes{})
+		assert.Error(t, err)
+
+		cloned := column.Clone()
+		assert.Equal(t, columnName, cloned.Name())
+		assert.EqualValues(t, v, cloned.(*ColumnInt64).Data())
+	})
 }
 
 func TestFieldDataInt64Column(t *testing.T) {
@@ -579,6 +679,26 @@ func TestColumnFloat(t *testing.T) {
 			assert.Equal(t, column.values[i], v)
 		}
 	})
+
+	t.Run("test column slice concat clone", func(t *testing.T) {
+		sliced := column.Slice(2, 5)
+		assert.Equal(t, columnName, sliced.Name())
+		assert.Equal(t, 3, sliced.Len())
+		assert.EqualValues(t, v[2:5], sliced.(*ColumnFloat).Data())
+		assert.Equal(t, columnLen-2, column.Slice(2, -1).Len())
+		assert.Equal(t, 0, column.Slice(5, 2).Len())
+		assert.Equal(t, columnLen, column.Slice(-1, columnLen+10).Len())
+
+		concated, err := column.Slice(0, 2).Concat(column.Slice(2, -1))
+		assert.NoError(t, err)
+		assert.EqualValues(t, v, concated.(*ColumnFloat).Data())
+		_, err = column.Concat(&ColumnJSONBytes{})
+		assert.Error(t, err)
+
+		cloned := column.Clone()
+		assert.Equal(t, columnName, cloned.Name())
+		assert.EqualValues(t, v, cloned.(*ColumnFloat).Data())
+	})
 }
 
 func TestFieldDataFloatColumn(t *testing.T) {
@@ -684,6 +804,26 @@ func TestColumnDouble(t *testing.T) {
 			assert.Equal(t, column.values[i], v)
 		}
 	})
+
+	t.Run("test column slice concat clone", func(t *testing.T) {
+		sliced := column.Slice(2, 5)
+		assert.Equal(t, columnName, sliced.Name())
+		assert.Equal(t, 3, sliced.Len())
+		assert.EqualValues(t, v[2:5], sliced.(*ColumnDouble).Data())
+		assert.Equal(t, columnLen-2, column.Slice(2, -1).Len())
+		assert.Equal(t, 0, column.Slice(5, 2).Len())
+		assert.Equal(t, columnLen, column.Slice(-1, columnLen+10).Len())
+
+		concated, err := column.Slice(0, 2).Concat(column.Slice(2, -1))
+		assert.NoError(t, err)
+		assert.EqualValues(t, v, concated.(*ColumnDouble).Data())
+		_, err = column.Concat(&ColumnJSONBytes{})
+		assert.Error(t, err)
+
+		cloned := column.Clone()
+		assert.Equal(t, columnName, cloned.Name())
+		assert.EqualValues(t, v, cloned.(*ColumnDouble).Data())
+	})
 }
 
 func TestFieldDataDoubleColumn(t *testing.T) {
@@ -789,6 +929,26 @@ func TestColumnString(t *testing.T) {
 			assert.Equal(t, column.values[i], v)
 		}
 	})
+
+	t.Run("test column slice concat clone", func(t *testing.T) {
+		sliced := column.Slice(2, 5)
+		assert.Equal(t, columnName, sliced.Name())
+		assert.Equal(t, 3, sliced.Len())
+		assert.EqualValues(t, v[2:5], sliced.(*ColumnString).Data())
+		assert.Equal(t, columnLen-2, column.Slice(2, -1).Len())
+		assert.Equal(t, 0, column.Slice(5, 2).Len())
+		assert.Equal(t, columnLen, column.Slice(-1, columnLen+10).Len())
+
+		concated, err := column.Slice(0, 2).Concat(column.Slice(2, -1))
+		assert.NoError(t, err)
+		assert.EqualValues(t, v, concated.(*ColumnString).Data())
+		_, err = column.Concat(&ColumnJSONBytes{})
+		assert.Error(t, err)
+
+		cloned := column.Clone()
+		assert.Equal(t, columnName, cloned.Name())
+		assert.EqualValues(t, v, cloned.(*ColumnString).Data())
+	})
 }
 
 func TestFieldDataStringColumn(t *testing.T) {
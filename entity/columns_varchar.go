@@ -93,6 +93,41 @@ func (c *ColumnVarChar) Data() []string {
 	return c.values
 }
 
+// Slice returns a column with values in range [start, end), which shares underlying data with the column.
+// end < 0 means to the end of the column.
+func (c *ColumnVarChar) Slice(start, end int) Column {
+	start, end = sliceBounds(start, end, c.Len())
+	return &ColumnVarChar{
+		name:   c.name,
+		values: c.values[start:end:end],
+	}
+}
+
+// Concat returns a new column with values of the column followed by values of other column.
+func (c *ColumnVarChar) Concat(other Column) (Column, error) {
+	o, ok := other.(*ColumnVarChar)
+	if !ok {
+		return nil, fmt.Errorf("cannot concat VarChar column with %T", other)
+	}
+	values := make([]string, 0, c.Len()+o.Len())
+	values = append(values, c.values...)
+	values = append(values, o.values...)
+	return &ColumnVarChar{
+		name:   c.name,
+		values: values,
+	}, nil
+}
+
+// Clone returns a deep copy of the column.
+func (c *ColumnVarChar) Clone() Column {
+	values := make([]string, len(c.values))
+	copy(values, c.values)
+	return &ColumnVarChar{
+		name:   c.name,
+		values: values,
+	}
+}
+
 // NewColumnVarChar auto generated constructor
 func NewColumnVarChar(name string, values []string) *ColumnVarChar {
 	return &ColumnVarChar{
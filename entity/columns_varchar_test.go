@@ -51,6 +51,26 @@ func TestColumnVarChar(t *testing.T) {
 			assert.Equal(t, column.values[i], v)
 		}
 	})
+
+	t.Run("test column slice concat clone", func(t *testing.T) {
+		column := NewColumnVarChar(columnName, []string{"a", "b", "c"})
+		sliced := column.Slice(1, -1)
+		assert.Equal(t, []string{"b", "c"}, sliced.(*ColumnVarChar).Data())
+
+		concated, err := sliced.Concat(column.Slice(0, 1))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"b", "c", "a"}, concated.(*ColumnVarChar).Data())
+		_, err = column.Concat(NewColumnString(columnName, nil))
+		assert.Error(t, err)
+
+		// append on sliced column shall not overwrite original data
+		assert.NoError(t, column.Slice(0, 1).AppendValue("x"))
+		assert.Equal(t, "b", column.Data()[1])
+
+		cloned := column.Clone().(*ColumnVarChar)
+		cloned.Data()[0] = "x"
+		assert.Equal(t, "a", column.Data()[0])
+	})
 }
 
 func TestFieldDataVarCharColumn(t *testing.T) {
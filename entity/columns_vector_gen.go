@@ -11,11 +11,13 @@ import (
 )
 
 // ColumnBinaryVector generated columns type for BinaryVector
+// vectors are stored in a contiguous buffer with the vector length as stride
 type ColumnBinaryVector struct {
 	ColumnBase
-	name   string
-	dim    int
-	values [][]byte
+	name string
+	dim  int
+	data []byte
+	err  error
 }
 
 // Name returns column name
@@ -30,7 +32,11 @@ func (c *ColumnBinaryVector) Type() FieldType {
 
 // Len returns column data length
 func (c *ColumnBinaryVector) Len() int {
-	return len(c.values)
+	stride := c.stride()
+	if stride == 0 {
+		return 0
+	}
+	return len(c.data) / stride
 }
 
 // Dim returns vector dimension
@@ -38,12 +44,30 @@ func (c *ColumnBinaryVector) Dim() int {
 	return c.dim
 }
 
+// Err returns the error occurred when the column was constructed,
+// a column with error holds no data and cannot be appended, concatenated or inserted.
+func (c *ColumnBinaryVector) Err() error {
+	return c.err
+}
+
+// stride returns the element count of one vector in data buffer
+func (c *ColumnBinaryVector) stride() int {
+	return (c.dim + 7) / 8
+}
+
 // Get returns values at index as interface{}.
 func (c *ColumnBinaryVector) Get(idx int) (interface{}, error) {
+	return c.ValueByIdx(idx)
+}
+
+// ValueByIdx returns vector at index, which is a view of the underlying buffer.
+// error occurs when index out of range
+func (c *ColumnBinaryVector) ValueByIdx(idx int) ([]byte, error) {
 	if idx < 0 || idx >= c.Len() {
 		return nil, errors.New("index out of range")
 	}
-	return c.values[idx], nil
+	stride := c.stride()
+	return c.data[idx*stride : (idx+1)*stride : (idx+1)*stride], nil
 }
 
 // AppendValue append value into column
@@ -52,35 +76,94 @@ func (c *ColumnBinaryVector) AppendValue(i interface{}) error {
 	if !ok {
 		return fmt.Errorf("invalid type, expected []byte, got %T", i)
 	}
-	c.values = append(c.values, v)
+	if c.err != nil {
+		return c.err
+	}
+	if len(v) != c.stride() {
+		return fmt.Errorf("invalid vector length %d, expected %d", len(v), c.stride())
+	}
+	c.data = append(c.data, v...)
 
 	return nil
 }
 
-// Data returns column data
+// Data returns column data as vectors, each vector is a view of the underlying buffer
 func (c *ColumnBinaryVector) Data() [][]byte {
-	return c.values
+	values := make([][]byte, 0, c.Len())
+	for i := 0; i < c.Len(); i++ {
+		v, _ := c.ValueByIdx(i)
+		values = append(values, v)
+	}
+	return values
+}
+
+// FlatData returns the underlying buffer of all vectors
+func (c *ColumnBinaryVector) FlatData() []byte {
+	return c.data[:c.Len()*c.stride()]
+}
+
+// Slice returns a column with vectors in range [start, end), which shares underlying data with the column.
+// end < 0 means to the end of the column.
+func (c *ColumnBinaryVector) Slice(start, end int) Column {
+	start, end = sliceBounds(start, end, c.Len())
+	stride := c.stride()
+	return &ColumnBinaryVector{
+		name: c.name,
+		dim:  c.dim,
+		data: c.data[start*stride : end*stride : end*stride],
+		err:  c.err,
+	}
+}
+
+// Concat returns a new column with vectors of the column followed by vectors of other column.
+func (c *ColumnBinaryVector) Concat(other Column) (Column, error) {
+	o, ok := other.(*ColumnBinaryVector)
+	if !ok {
+		return nil, fmt.Errorf("cannot concat BinaryVector column with %T", other)
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	if o.err != nil {
+		return nil, o.err
+	}
+	if c.dim != o.dim {
+		return nil, fmt.Errorf("cannot concat BinaryVector column with dim %d and %d", c.dim, o.dim)
+	}
+	data := make([]byte, 0, len(c.FlatData())+len(o.FlatData()))
+	data = append(data, c.FlatData()...)
+	data = append(data, o.FlatData()...)
+	return &ColumnBinaryVector{
+		name: c.name,
+		dim:  c.dim,
+		data: data,
+	}, nil
+}
+
+// Clone returns a deep copy of the column.
+func (c *ColumnBinaryVector) Clone() Column {
+	return &ColumnBinaryVector{
+		name: c.name,
+		dim:  c.dim,
+		data: append([]byte(nil), c.FlatData()...),
+		err:  c.err,
+	}
 }
 
 // FieldData return column data mapped to schema.FieldData
+// the returned field data references the column buffer without copy
 func (c *ColumnBinaryVector) FieldData() *schema.FieldData {
 	fd := &schema.FieldData{
 		Type:      schema.DataType_BinaryVector,
 		FieldName: c.name,
 	}
 
-	data := make([]byte, 0, len(c.values)*c.dim)
-
-	for _, vector := range c.values {
-		data = append(data, vector...)
-	}
-
 	fd.Field = &schema.FieldData_Vectors{
 		Vectors: &schema.VectorField{
 			Dim: int64(c.dim),
 
 			Data: &schema.VectorField_BinaryVector{
-				BinaryVector: data,
+				BinaryVector: c.FlatData(),
 			},
 		},
 	}
@@ -88,20 +171,57 @@ func (c *ColumnBinaryVector) FieldData() *schema.FieldData {
 }
 
 // NewColumnBinaryVector auto generated constructor
+// vectors are copied into a contiguous buffer, all of them shall have the length of dim.
+// When dim is not positive or any vector has other length, the column holds no data
+// and reports the error from Err, AppendValue and Concat, inserting it fails.
+// Use NewColumnBinaryVectorChecked to get the error on construction.
 func NewColumnBinaryVector(name string, dim int, values [][]byte) *ColumnBinaryVector {
+	c, err := NewColumnBinaryVectorChecked(name, dim, values)
+	if err != nil {
+		return &ColumnBinaryVector{
+			name: name,
+			dim:  dim,
+			err:  err,
+		}
+	}
+	return c
+}
+
+// NewColumnBinaryVectorChecked creates column like NewColumnBinaryVector,
+// error occurs when dim is not positive or any vector does not have the length of dim.
+func NewColumnBinaryVectorChecked(name string, dim int, values [][]byte) (*ColumnBinaryVector, error) {
+	if dim <= 0 {
+		return nil, fmt.Errorf("invalid dim %d for vector column %s", dim, name)
+	}
+	c := NewColumnBinaryVectorFlat(name, dim, nil)
+	stride := c.stride()
+	c.data = make([]byte, 0, len(values)*stride)
+	for i, v := range values {
+		if len(v) != stride {
+			return nil, fmt.Errorf("vector %d of column %s has length %d, expected %d", i, name, len(v), stride)
+		}
+		c.data = append(c.data, v...)
+	}
+	return c, nil
+}
+
+// NewColumnBinaryVectorFlat creates column with vectors stored in data, which is used without copy
+func NewColumnBinaryVectorFlat(name string, dim int, data []byte) *ColumnBinaryVector {
 	return &ColumnBinaryVector{
-		name:   name,
-		dim:    dim,
-		values: values,
+		name: name,
+		dim:  dim,
+		data: data,
 	}
 }
 
 // ColumnFloatVector generated columns type for FloatVector
+// vectors are stored in a contiguous buffer with the vector length as stride
 type ColumnFloatVector struct {
 	ColumnBase
-	name   string
-	dim    int
-	values [][]float32
+	name string
+	dim  int
+	data []float32
+	err  error
 }
 
 // Name returns column name
@@ -116,7 +236,11 @@ func (c *ColumnFloatVector) Type() FieldType {
 
 // Len returns column data length
 func (c *ColumnFloatVector) Len() int {
-	return len(c.values)
+	stride := c.stride()
+	if stride == 0 {
+		return 0
+	}
+	return len(c.data) / stride
 }
 
 // Dim returns vector dimension
@@ -124,12 +248,30 @@ func (c *ColumnFloatVector) Dim() int {
 	return c.dim
 }
 
+// Err returns the error occurred when the column was constructed,
+// a column with error holds no data and cannot be appended, concatenated or inserted.
+func (c *ColumnFloatVector) Err() error {
+	return c.err
+}
+
+// stride returns the element count of one vector in data buffer
+func (c *ColumnFloatVector) stride() int {
+	return c.dim
+}
+
 // Get returns values at index as interface{}.
 func (c *ColumnFloatVector) Get(idx int) (interface{}, error) {
+	return c.ValueByIdx(idx)
+}
+
+// ValueByIdx returns vector at index, which is a view of the underlying buffer.
+// error occurs when index out of range
+func (c *ColumnFloatVector) ValueByIdx(idx int) ([]float32, error) {
 	if idx < 0 || idx >= c.Len() {
 		return nil, errors.New("index out of range")
 	}
-	return c.values[idx], nil
+	stride := c.stride()
+	return c.data[idx*stride : (idx+1)*stride : (idx+1)*stride], nil
 }
 
 // AppendValue append value into column
@@ -138,36 +280,95 @@ func (c *ColumnFloatVector) AppendValue(i interface{}) error {
 	if !ok {
 		return fmt.Errorf("invalid type, expected []float32, got %T", i)
 	}
-	c.values = append(c.values, v)
+	if c.err != nil {
+		return c.err
+	}
+	if len(v) != c.stride() {
+		return fmt.Errorf("invalid vector length %d, expected %d", len(v), c.stride())
+	}
+	c.data = append(c.data, v...)
 
 	return nil
 }
 
-// Data returns column data
+// Data returns column data as vectors, each vector is a view of the underlying buffer
 func (c *ColumnFloatVector) Data() [][]float32 {
-	return c.values
+	values := make([][]float32, 0, c.Len())
+	for i := 0; i < c.Len(); i++ {
+		v, _ := c.ValueByIdx(i)
+		values = append(values, v)
+	}
+	return values
+}
+
+// FlatData returns the underlying buffer of all vectors
+func (c *ColumnFloatVector) FlatData() []float32 {
+	return c.data[:c.Len()*c.stride()]
+}
+
+// Slice returns a column with vectors in range [start, end), which shares underlying data with the column.
+// end < 0 means to the end of the column.
+func (c *ColumnFloatVector) Slice(start, end int) Column {
+	start, end = sliceBounds(start, end, c.Len())
+	stride := c.stride()
+	return &ColumnFloatVector{
+		name: c.name,
+		dim:  c.dim,
+		data: c.data[start*stride : end*stride : end*stride],
+		err:  c.err,
+	}
+}
+
+// Concat returns a new column with vectors of the column followed by vectors of other column.
+func (c *ColumnFloatVector) Concat(other Column) (Column, error) {
+	o, ok := other.(*ColumnFloatVector)
+	if !ok {
+		return nil, fmt.Errorf("cannot concat FloatVector column with %T", other)
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	if o.err != nil {
+		return nil, o.err
+	}
+	if c.dim != o.dim {
+		return nil, fmt.Errorf("cannot concat FloatVector column with dim %d and %d", c.dim, o.dim)
+	}
+	data := make([]float32, 0, len(c.FlatData())+len(o.FlatData()))
+	data = append(data, c.FlatData()...)
+	data = append(data, o.FlatData()...)
+	return &ColumnFloatVector{
+		name: c.name,
+		dim:  c.dim,
+		data: data,
+	}, nil
+}
+
+// Clone returns a deep copy of the column.
+func (c *ColumnFloatVector) Clone() Column {
+	return &ColumnFloatVector{
+		name: c.name,
+		dim:  c.dim,
+		data: append([]float32(nil), c.FlatData()...),
+		err:  c.err,
+	}
 }
 
 // FieldData return column data mapped to schema.FieldData
+// the returned field data references the column buffer without copy
 func (c *ColumnFloatVector) FieldData() *schema.FieldData {
 	fd := &schema.FieldData{
 		Type:      schema.DataType_FloatVector,
 		FieldName: c.name,
 	}
 
-	data := make([]float32, 0, len(c.values)*c.dim)
-
-	for _, vector := range c.values {
-		data = append(data, vector...)
-	}
-
 	fd.Field = &schema.FieldData_Vectors{
 		Vectors: &schema.VectorField{
 			Dim: int64(c.dim),
 
 			Data: &schema.VectorField_FloatVector{
 				FloatVector: &schema.FloatArray{
-					Data: data,
+					Data: c.FlatData(),
 				},
 			},
 		},
@@ -176,10 +377,45 @@ func (c *ColumnFloatVector) FieldData() *schema.FieldData {
 }
 
 // NewColumnFloatVector auto generated constructor
+// vectors are copied into a contiguous buffer, all of them shall have the length of dim.
+// When dim is not positive or any vector has other length, the column holds no data
+// and reports the error from Err, AppendValue and Concat, inserting it fails.
+// Use NewColumnFloatVectorChecked to get the error on construction.
 func NewColumnFloatVector(name string, dim int, values [][]float32) *ColumnFloatVector {
+	c, err := NewColumnFloatVectorChecked(name, dim, values)
+	if err != nil {
+		return &ColumnFloatVector{
+			name: name,
+			dim:  dim,
+			err:  err,
+		}
+	}
+	return c
+}
+
+// NewColumnFloatVectorChecked creates column like NewColumnFloatVector,
+// error occurs when dim is not positive or any vector does not have the length of dim.
+func NewColumnFloatVectorChecked(name string, dim int, values [][]float32) (*ColumnFloatVector, error) {
+	if dim <= 0 {
+		return nil, fmt.Errorf("invalid dim %d for vector column %s", dim, name)
+	}
+	c := NewColumnFloatVectorFlat(name, dim, nil)
+	stride := c.stride()
+	c.data = make([]float32, 0, len(values)*stride)
+	for i, v := range values {
+		if len(v) != stride {
+			return nil, fmt.Errorf("vector %d of column %s has length %d, expected %d", i, name, len(v), stride)
+		}
+		c.data = append(c.data, v...)
+	}
+	return c, nil
+}
+
+// NewColumnFloatVectorFlat creates column with vectors stored in data, which is used without copy
+func NewColumnFloatVectorFlat(name string, dim int, data []float32) *ColumnFloatVector {
 	return &ColumnFloatVector{
-		name:   name,
-		dim:    dim,
-		values: values,
+		name: name,
+		dim:  dim,
+		data: data,
 	}
 }
@@ -45,7 +45,7 @@ func TestColumnBinaryVector(t *testing.T) {
 		assert.Equal(t, dim, column.Dim())
 		assert.Equal(t, v, column.Data())
 
-		var ev []byte
+		ev := make([]byte, dlen)
 		err := column.AppendValue(ev)
 		assert.Equal(t, columnLen+1, column.Len())
 		assert.Nil(t, err)
@@ -53,6 +53,30 @@ func TestColumnBinaryVector(t *testing.T) {
 		err = column.AppendValue(struct{}{})
 		assert.Equal(t, columnLen+1, column.Len())
 		assert.NotNil(t, err)
+
+		err = column.AppendValue(make([]byte, dlen+1))
+		assert.Equal(t, columnLen+1, column.Len())
+		assert.NotNil(t, err)
+	})
+
+	t.Run("test column flat data", func(t *testing.T) {
+		flat := make([]byte, dlen*3)
+		flat[dlen] = 1
+		c := NewColumnBinaryVectorFlat(columnName, dim, flat)
+		assert.Equal(t, 3, c.Len())
+		assert.Equal(t, flat, c.FlatData())
+
+		row, err := c.ValueByIdx(1)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, row[0])
+		row[0] = 2
+		assert.EqualValues(t, 2, flat[dlen])
+		_, err = c.ValueByIdx(3)
+		assert.Error(t, err)
+
+		fd := c.FieldData()
+		data := fd.GetVectors().GetBinaryVector()
+		assert.Equal(t, &flat[0], &data[0])
 	})
 
 	t.Run("test column field data", func(t *testing.T) {
@@ -74,6 +98,57 @@ func TestColumnBinaryVector(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	t.Run("test column slice concat clone", func(t *testing.T) {
+		sliced := column.Slice(2, 5)
+		assert.Equal(t, columnName, sliced.Name())
+		assert.Equal(t, 3, sliced.Len())
+		assert.Equal(t, dim, sliced.(*ColumnBinaryVector).Dim())
+		assert.Equal(t, v[2:5], sliced.(*ColumnBinaryVector).Data())
+
+		concated, err := column.Slice(0, 2).Concat(column.Slice(2, columnLen))
+		assert.NoError(t, err)
+		assert.Equal(t, v, concated.(*ColumnBinaryVector).Data())
+		_, err = column.Concat(NewColumnBinaryVector(columnName, dim*2, nil))
+		assert.Error(t, err)
+		_, err = column.Concat(&ColumnJSONBytes{})
+		assert.Error(t, err)
+
+		cloned := column.Clone().(*ColumnBinaryVector)
+		assert.Equal(t, column.Data(), cloned.Data())
+		cloned.Data()[0][0] = 1
+		assert.NotEqual(t, column.Data()[0][0], cloned.Data()[0][0])
+	})
+
+	t.Run("test column constructors", func(t *testing.T) {
+		// vectors with wrong length are rejected
+		c := NewColumnBinaryVector(columnName, dim, [][]byte{v[0], make([]byte, dlen+1)})
+		assert.Error(t, c.Err())
+		assert.Equal(t, 0, c.Len())
+		assert.Error(t, c.AppendValue(v[0]))
+		_, err := column.Concat(c)
+		assert.Error(t, err)
+		_, err = c.Concat(column)
+		assert.Error(t, err)
+
+		// dim shall be positive
+		c = NewColumnBinaryVector(columnName, 0, v)
+		assert.Error(t, c.Err())
+		assert.Equal(t, 0, c.Len())
+
+		c, err = NewColumnBinaryVectorChecked(columnName, dim, v)
+		assert.NoError(t, err)
+		assert.Equal(t, v, c.Data())
+		_, err = NewColumnBinaryVectorChecked(columnName, dim, [][]byte{v[0], make([]byte, dlen+1)})
+		assert.Error(t, err)
+		_, err = NewColumnBinaryVectorChecked(columnName, 0, v)
+		assert.Error(t, err)
+
+		// dim less than 8 still takes one byte per vector
+		c = NewColumnBinaryVector(columnName, 4, [][]byte{[]byte{1}, []byte{2}})
+		assert.Equal(t, 2, c.Len())
+		assert.Equal(t, []byte{2}, c.Data()[1])
+	})
+
 }
 
 func TestColumnFloatVector(t *testing.T) {
@@ -107,7 +182,7 @@ func TestColumnFloatVector(t *testing.T) {
 		assert.Equal(t, dim, column.Dim())
 		assert.Equal(t, v, column.Data())
 
-		var ev []float32
+		ev := make([]float32, dlen)
 		err := column.AppendValue(ev)
 		assert.Equal(t, columnLen+1, column.Len())
 		assert.Nil(t, err)
@@ -115,6 +190,30 @@ func TestColumnFloatVector(t *testing.T) {
 		err = column.AppendValue(struct{}{})
 		assert.Equal(t, columnLen+1, column.Len())
 		assert.NotNil(t, err)
+
+		err = column.AppendValue(make([]float32, dlen+1))
+		assert.Equal(t, columnLen+1, column.Len())
+		assert.NotNil(t, err)
+	})
+
+	t.Run("test column flat data", func(t *testing.T) {
+		flat := make([]float32, dlen*3)
+		flat[dlen] = 1
+		c := NewColumnFloatVectorFlat(columnName, dim, flat)
+		assert.Equal(t, 3, c.Len())
+		assert.Equal(t, flat, c.FlatData())
+
+		row, err := c.ValueByIdx(1)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, row[0])
+		row[0] = 2
+		assert.EqualValues(t, 2, flat[dlen])
+		_, err = c.ValueByIdx(3)
+		assert.Error(t, err)
+
+		fd := c.FieldData()
+		data := fd.GetVectors().GetFloatVector().GetData()
+		assert.Equal(t, &flat[0], &data[0])
 	})
 
 	t.Run("test column field data", func(t *testing.T) {
@@ -136,4 +235,51 @@ func TestColumnFloatVector(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	t.Run("test column slice concat clone", func(t *testing.T) {
+		sliced := column.Slice(2, 5)
+		assert.Equal(t, columnName, sliced.Name())
+		assert.Equal(t, 3, sliced.Len())
+		assert.Equal(t, dim, sliced.(*ColumnFloatVector).Dim())
+		assert.Equal(t, v[2:5], sliced.(*ColumnFloatVector).Data())
+
+		concated, err := column.Slice(0, 2).Concat(column.Slice(2, columnLen))
+		assert.NoError(t, err)
+		assert.Equal(t, v, concated.(*ColumnFloatVector).Data())
+		_, err = column.Concat(NewColumnFloatVector(columnName, dim*2, nil))
+		assert.Error(t, err)
+		_, err = column.Concat(&ColumnJSONBytes{})
+		assert.Error(t, err)
+
+		cloned := column.Clone().(*ColumnFloatVector)
+		assert.Equal(t, column.Data(), cloned.Data())
+		cloned.Data()[0][0] = 1
+		assert.NotEqual(t, column.Data()[0][0], cloned.Data()[0][0])
+	})
+
+	t.Run("test column constructors", func(t *testing.T) {
+		// vectors with wrong length are rejected
+		c := NewColumnFloatVector(columnName, dim, [][]float32{v[0], make([]float32, dlen+1)})
+		assert.Error(t, c.Err())
+		assert.Equal(t, 0, c.Len())
+		assert.Error(t, c.AppendValue(v[0]))
+		_, err := column.Concat(c)
+		assert.Error(t, err)
+		_, err = c.Concat(column)
+		assert.Error(t, err)
+
+		// dim shall be positive
+		c = NewColumnFloatVector(columnName, 0, v)
+		assert.Error(t, c.Err())
+		assert.Equal(t, 0, c.Len())
+
+		c, err = NewColumnFloatVectorChecked(columnName, dim, v)
+		assert.NoError(t, err)
+		assert.Equal(t, v, c.Data())
+		_, err = NewColumnFloatVectorChecked(columnName, dim, [][]float32{v[0], make([]float32, dlen+1)})
+		assert.Error(t, err)
+		_, err = NewColumnFloatVectorChecked(columnName, 0, v)
+		assert.Error(t, err)
+
+	})
+
 }
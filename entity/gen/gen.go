@@ -114,6 +114,41 @@ func (c *Column{{.TypeName}}) Data() []{{.TypeDef}} {
 	return c.values
 }
 
+// Slice returns a column with values in range [start, end), which shares underlying data with the column.
+// end < 0 means to the end of the column.
+func (c *Column{{.TypeName}}) Slice(start, end int) Column {
+	start, end = sliceBounds(start, end, c.Len())
+	return &Column{{.TypeName}}{
+		name:   c.name,
+		values: c.values[start:end:end],
+	}
+}
+
+// Concat returns a new column with values of the column followed by values of other column.
+func (c *Column{{.TypeName}}) Concat(other Column) (Column, error) {
+	o, ok := other.(*Column{{.TypeName}})
+	if !ok {
+		return nil, fmt.Errorf("cannot concat {{.TypeName}} column with %T", other)
+	}
+	values := make([]{{.TypeDef}}, 0, c.Len()+o.Len())
+	values = append(values, c.values...)
+	values = append(values, o.values...)
+	return &Column{{.TypeName}}{
+		name:   c.name,
+		values: values,
+	}, nil
+}
+
+// Clone returns a deep copy of the column.
+func (c *Column{{.TypeName}}) Clone() Column {
+	values := make([]{{.TypeDef}}, len(c.values))
+	copy(values, c.values)
+	return &Column{{.TypeName}}{
+		name:   c.name,
+		values: values,
+	}
+}
+
 // NewColumn{{.TypeName}} auto generated constructor
 func NewColumn{{.TypeName}}(name string, values []{{.TypeDef}}) *Column{{.TypeName}} {
 	return &Column{{.TypeName}} {
@@ -137,11 +172,13 @@ import (
 
 {{ range .Types }}{{with.}}
 // Column{{.TypeName}} generated columns type for {{.TypeName}}
+// vectors are stored in a contiguous buffer with the vector length as stride
 type Column{{.TypeName}} struct {
 	ColumnBase
-	name   string
-	dim    int
-	values []{{.TypeDef}}
+	name string
+	dim  int
+	data {{.TypeDef}}
+	err  error
 }
 
 // Name returns column name
@@ -156,7 +193,11 @@ func (c *Column{{.TypeName}}) Type() FieldType {
 
 // Len returns column data length
 func (c * Column{{.TypeName}}) Len() int {
-	return len(c.values)
+	stride := c.stride()
+	if stride == 0 {
+		return 0
+	}
+	return len(c.data) / stride
 }
 
 // Dim returns vector dimension
@@ -164,12 +205,30 @@ func (c *Column{{.TypeName}}) Dim() int {
 	return c.dim
 }
 
+// Err returns the error occurred when the column was constructed,
+// a column with error holds no data and cannot be appended, concatenated or inserted.
+func (c *Column{{.TypeName}}) Err() error {
+	return c.err
+}
+
+// stride returns the element count of one vector in data buffer
+func (c *Column{{.TypeName}}) stride() int {
+	{{if eq .TypeName "BinaryVector" }}return (c.dim + 7) / 8{{else}}return c.dim{{end}}
+}
+
 // Get returns values at index as interface{}.
 func (c *Column{{.TypeName}}) Get(idx int) (interface{}, error) {
+	return c.ValueByIdx(idx)
+}
+
+// ValueByIdx returns vector at index, which is a view of the underlying buffer.
+// error occurs when index out of range
+func (c *Column{{.TypeName}}) ValueByIdx(idx int) ({{.TypeDef}}, error) {
 	if idx < 0 || idx >= c.Len() {
 		return nil, errors.New("index out of range")
 	}
-	return c.values[idx], nil
+	stride := c.stride()
+	return c.data[idx*stride : (idx+1)*stride : (idx+1)*stride], nil
 }
 
 // AppendValue append value into column
@@ -178,40 +237,99 @@ func(c *Column{{.TypeName}}) AppendValue(i interface{}) error {
 	if !ok {
 		return fmt.Errorf("invalid type, expected {{.TypeDef}}, got %T", i)
 	}
-	c.values = append(c.values, v)
+	if c.err != nil {
+		return c.err
+	}
+	if len(v) != c.stride() {
+		return fmt.Errorf("invalid vector length %d, expected %d", len(v), c.stride())
+	}
+	c.data = append(c.data, v...)
 
 	return nil
 }
 
-// Data returns column data
+// Data returns column data as vectors, each vector is a view of the underlying buffer
 func (c *Column{{.TypeName}}) Data() []{{.TypeDef}} {
-	return c.values
+	values := make([]{{.TypeDef}}, 0, c.Len())
+	for i := 0; i < c.Len(); i++ {
+		v, _ := c.ValueByIdx(i)
+		values = append(values, v)
+	}
+	return values
+}
+
+// FlatData returns the underlying buffer of all vectors
+func (c *Column{{.TypeName}}) FlatData() {{.TypeDef}} {
+	return c.data[:c.Len()*c.stride()]
+}
+
+// Slice returns a column with vectors in range [start, end), which shares underlying data with the column.
+// end < 0 means to the end of the column.
+func (c *Column{{.TypeName}}) Slice(start, end int) Column {
+	start, end = sliceBounds(start, end, c.Len())
+	stride := c.stride()
+	return &Column{{.TypeName}}{
+		name: c.name,
+		dim:  c.dim,
+		data: c.data[start*stride : end*stride : end*stride],
+		err:  c.err,
+	}
+}
+
+// Concat returns a new column with vectors of the column followed by vectors of other column.
+func (c *Column{{.TypeName}}) Concat(other Column) (Column, error) {
+	o, ok := other.(*Column{{.TypeName}})
+	if !ok {
+		return nil, fmt.Errorf("cannot concat {{.TypeName}} column with %T", other)
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	if o.err != nil {
+		return nil, o.err
+	}
+	if c.dim != o.dim {
+		return nil, fmt.Errorf("cannot concat {{.TypeName}} column with dim %d and %d", c.dim, o.dim)
+	}
+	data := make({{.TypeDef}}, 0, len(c.FlatData())+len(o.FlatData()))
+	data = append(data, c.FlatData()...)
+	data = append(data, o.FlatData()...)
+	return &Column{{.TypeName}}{
+		name: c.name,
+		dim:  c.dim,
+		data: data,
+	}, nil
+}
+
+// Clone returns a deep copy of the column.
+func (c *Column{{.TypeName}}) Clone() Column {
+	return &Column{{.TypeName}}{
+		name: c.name,
+		dim:  c.dim,
+		data: append({{.TypeDef}}(nil), c.FlatData()...),
+		err:  c.err,
+	}
 }
 
 // FieldData return column data mapped to schema.FieldData
+// the returned field data references the column buffer without copy
 func (c *Column{{.TypeName}}) FieldData() *schema.FieldData {
 	fd := &schema.FieldData{
 		Type: schema.DataType_{{.TypeName}},
 		FieldName: c.name,
 	}
 
-	data := make({{.TypeDef}}, 0, len(c.values)* c.dim)
-
-	for _, vector := range c.values {
-		data = append(data, vector...)
-	}
-
 	fd.Field = &schema.FieldData_Vectors{
 		Vectors: &schema.VectorField{
 			Dim: int64(c.dim),
 			{{if eq .TypeName "BinaryVector" }}
 			Data: &schema.VectorField_BinaryVector{
-				BinaryVector: data,
+				BinaryVector: c.FlatData(),
 			},
 			{{else}}
 			Data: &schema.VectorField_FloatVector{
 				FloatVector: &schema.FloatArray{
-					Data: data,
+					Data: c.FlatData(),
 				},
 			},
 			{{end}}
@@ -221,11 +339,46 @@ func (c *Column{{.TypeName}}) FieldData() *schema.FieldData {
 }
 
 // NewColumn{{.TypeName}} auto generated constructor
+// vectors are copied into a contiguous buffer, all of them shall have the length of dim.
+// When dim is not positive or any vector has other length, the column holds no data
+// and reports the error from Err, AppendValue and Concat, inserting it fails.
+// Use NewColumn{{.TypeName}}Checked to get the error on construction.
 func NewColumn{{.TypeName}}(name string, dim int, values []{{.TypeDef}}) *Column{{.TypeName}} {
+	c, err := NewColumn{{.TypeName}}Checked(name, dim, values)
+	if err != nil {
+		return &Column{{.TypeName}}{
+			name: name,
+			dim:  dim,
+			err:  err,
+		}
+	}
+	return c
+}
+
+// NewColumn{{.TypeName}}Checked creates column like NewColumn{{.TypeName}},
+// error occurs when dim is not positive or any vector does not have the length of dim.
+func NewColumn{{.TypeName}}Checked(name string, dim int, values []{{.TypeDef}}) (*Column{{.TypeName}}, error) {
+	if dim <= 0 {
+		return nil, fmt.Errorf("invalid dim %d for vector column %s", dim, name)
+	}
+	c := NewColumn{{.TypeName}}Flat(name, dim, nil)
+	stride := c.stride()
+	c.data = make({{.TypeDef}}, 0, len(values)*stride)
+	for i, v := range values {
+		if len(v) != stride {
+			return nil, fmt.Errorf("vector %d of column %s has length %d, expected %d", i, name, len(v), stride)
+		}
+		c.data = append(c.data, v...)
+	}
+	return c, nil
+}
+
+// NewColumn{{.TypeName}}Flat creates column with vectors stored in data, which is used without copy
+func NewColumn{{.TypeName}}Flat(name string, dim int, data {{.TypeDef}}) *Column{{.TypeName}} {
 	return &Column{{.TypeName}} {
-		name:   name,
-		dim:    dim,
-		values: values,
+		name: name,
+		dim:  dim,
+		data: data,
 	}
 }
 {{end}}{{end}}
@@ -287,6 +440,26 @@ func TestColumn{{.TypeName}}(t *testing.T) {
 			assert.Equal(t, column.values[i], v)
 		}
 	})
+
+	t.Run("test column slice concat clone", func(t *testing.T) {
+		sliced := column.Slice(2, 5)
+		assert.Equal(t, columnName, sliced.Name())
+		assert.Equal(t, 3, sliced.Len())
+		assert.EqualValues(t, v[2:5], sliced.(*Column{{.TypeName}}).Data())
+		assert.Equal(t, columnLen-2, column.Slice(2, -1).Len())
+		assert.Equal(t, 0, column.Slice(5, 2).Len())
+		assert.Equal(t, columnLen, column.Slice(-1, columnLen+10).Len())
+
+		concated, err := column.Slice(0, 2).Concat(column.Slice(2, -1))
+		assert.NoError(t, err)
+		assert.EqualValues(t, v, concated.(*Column{{.TypeName}}).Data())
+		_, err = column.Concat(&ColumnJSONBytes{})
+		assert.Error(t, err)
+
+		cloned := column.Clone()
+		assert.Equal(t, columnName, cloned.Name())
+		assert.EqualValues(t, v, cloned.(*Column{{.TypeName}}).Data())
+	})
 }
 
 func TestFieldData{{.TypeName}}Column(t *testing.T) {
@@ -400,7 +573,7 @@ func TestColumn{{.TypeName}}(t *testing.T) {
 		assert.Equal(t, dim, column.Dim())
 		assert.Equal(t ,v, column.Data())
 		
-		var ev {{.TypeDef}}
+		ev := make({{.TypeDef}}, dlen)
 		err := column.AppendValue(ev)
 		assert.Equal(t, columnLen+1, column.Len())
 		assert.Nil(t, err)
@@ -408,6 +581,30 @@ func TestColumn{{.TypeName}}(t *testing.T) {
 		err = column.AppendValue(struct{}{})
 		assert.Equal(t, columnLen+1, column.Len())
 		assert.NotNil(t, err)
+
+		err = column.AppendValue(make({{.TypeDef}}, dlen+1))
+		assert.Equal(t, columnLen+1, column.Len())
+		assert.NotNil(t, err)
+	})
+
+	t.Run("test column flat data", func(t *testing.T) {
+		flat := make({{.TypeDef}}, dlen*3)
+		flat[dlen] = 1
+		c := NewColumn{{.TypeName}}Flat(columnName, dim, flat)
+		assert.Equal(t, 3, c.Len())
+		assert.Equal(t, flat, c.FlatData())
+
+		row, err := c.ValueByIdx(1)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, row[0])
+		row[0] = 2
+		assert.EqualValues(t, 2, flat[dlen])
+		_, err = c.ValueByIdx(3)
+		assert.Error(t, err)
+
+		fd := c.FieldData()
+		{{if eq .TypeName "BinaryVector" }}data := fd.GetVectors().GetBinaryVector(){{else}}data := fd.GetVectors().GetFloatVector().GetData(){{end}}
+		assert.Equal(t, &flat[0], &data[0])
 	})
 
 	t.Run("test column field data", func(t *testing.T) {
@@ -429,6 +626,57 @@ func TestColumn{{.TypeName}}(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	t.Run("test column slice concat clone", func(t *testing.T) {
+		sliced := column.Slice(2, 5)
+		assert.Equal(t, columnName, sliced.Name())
+		assert.Equal(t, 3, sliced.Len())
+		assert.Equal(t, dim, sliced.(*Column{{.TypeName}}).Dim())
+		assert.Equal(t, v[2:5], sliced.(*Column{{.TypeName}}).Data())
+
+		concated, err := column.Slice(0, 2).Concat(column.Slice(2, columnLen))
+		assert.NoError(t, err)
+		assert.Equal(t, v, concated.(*Column{{.TypeName}}).Data())
+		_, err = column.Concat(NewColumn{{.TypeName}}(columnName, dim*2, nil))
+		assert.Error(t, err)
+		_, err = column.Concat(&ColumnJSONBytes{})
+		assert.Error(t, err)
+
+		cloned := column.Clone().(*Column{{.TypeName}})
+		assert.Equal(t, column.Data(), cloned.Data())
+		cloned.Data()[0][0] = 1
+		assert.NotEqual(t, column.Data()[0][0], cloned.Data()[0][0])
+	})
+
+	t.Run("test column constructors", func(t *testing.T) {
+		// vectors with wrong length are rejected
+		c := NewColumn{{.TypeName}}(columnName, dim, []{{.TypeDef}}{v[0], make({{.TypeDef}}, dlen+1)})
+		assert.Error(t, c.Err())
+		assert.Equal(t, 0, c.Len())
+		assert.Error(t, c.AppendValue(v[0]))
+		_, err := column.Concat(c)
+		assert.Error(t, err)
+		_, err = c.Concat(column)
+		assert.Error(t, err)
+
+		// dim shall be positive
+		c = NewColumn{{.TypeName}}(columnName, 0, v)
+		assert.Error(t, c.Err())
+		assert.Equal(t, 0, c.Len())
+
+		c, err = NewColumn{{.TypeName}}Checked(columnName, dim, v)
+		assert.NoError(t, err)
+		assert.Equal(t, v, c.Data())
+		_, err = NewColumn{{.TypeName}}Checked(columnName, dim, []{{.TypeDef}}{v[0], make({{.TypeDef}}, dlen+1)})
+		assert.Error(t, err)
+		_, err = NewColumn{{.TypeName}}Checked(columnName, 0, v)
+		assert.Error(t, err)
+		{{if eq .TypeName "BinaryVector" }}
+		// dim less than 8 still takes one byte per vector
+		c = NewColumnBinaryVector(columnName, 4, [][]byte{[]byte{1}, []byte{2}})
+		assert.Equal(t, 2, c.Len())
+		assert.Equal(t, []byte{2}, c.Data()[1]){{end}}
+	})
+
 }
 {{end}}{{end}}
 `))
@@ -43,6 +43,12 @@ const (
 	// MilvusAutoID struct tag const for auto id indicator
 	MilvusAutoID = `AUTO_ID`
 
+	// MilvusDynamicField struct tag const for the map field holding all dynamic field values
+	MilvusDynamicField = `DYNAMIC`
+
+	// MilvusScore struct tag const for the field receiving search score, ignored when inserting
+	MilvusScore = `SCORE`
+
 	// DimMax dimension max value
 	DimMax = 65535
 )
@@ -135,6 +141,13 @@ func ParseSchemaAny(r interface{}) (*Schema, error) {
 			continue
 		}
 		tagSettings := ParseTagSetting(tag, MilvusTagSep)
+		// dynamic data and search score field are not part of schema
+		if _, has := tagSettings[MilvusDynamicField]; has {
+			continue
+		}
+		if _, has := tagSettings[MilvusScore]; has {
+			continue
+		}
 		if _, has := tagSettings[MilvusPrimaryKey]; has {
 			field.PrimaryKey = true
 		}
@@ -316,10 +329,14 @@ func AnyToColumns(rows []interface{}, schemas ...*Schema) ([]Column, error) {
 			data := make([]float64, 0, rowsLen)
 			col := NewColumnDouble(field.Name, data)
 			nameColumns[field.Name] = col
-		case FieldTypeString, FieldTypeVarChar:
+		case FieldTypeString:
 			data := make([]string, 0, rowsLen)
 			col := NewColumnString(field.Name, data)
 			nameColumns[field.Name] = col
+		case FieldTypeVarChar:
+			data := make([]string, 0, rowsLen)
+			col := NewColumnVarChar(field.Name, data)
+			nameColumns[field.Name] = col
 		case FieldTypeJSON:
 			data := make([][]byte, 0, rowsLen)
 			col := NewColumnJSONBytes(field.Name, data)
@@ -393,6 +410,19 @@ func AnyToColumns(rows []interface{}, schemas ...*Schema) ([]Column, error) {
 		if isDynamic {
 			m := make(map[string]interface{})
 			for name, candi := range set {
+				if _, ok := candi.options[MilvusScore]; ok {
+					continue
+				}
+				// entries of dynamic tagged map are flattened into dynamic field
+				if _, ok := candi.options[MilvusDynamicField]; ok {
+					if candi.v.Kind() == reflect.Map && candi.v.Type().Key().Kind() == reflect.String {
+						iter := candi.v.MapRange()
+						for iter.Next() {
+							m[iter.Key().String()] = iter.Value().Interface()
+						}
+					}
+					continue
+				}
 				m[name] = candi.v.Interface()
 			}
 			bs, err := json.Marshal(m)
@@ -473,7 +503,13 @@ func reflectValueCandi(v reflect.Value) (map[string]fieldCandi, error) {
 
 			v := v.Field(i)
 			if v.Kind() == reflect.Array {
-				v = v.Slice(0, v.Len()-1)
+				// array field of non-pointer struct is not addressable, copy it first
+				if !v.CanAddr() {
+					cp := reflect.New(v.Type()).Elem()
+					cp.Set(v)
+					v = cp
+				}
+				v = v.Slice(0, v.Len())
 			}
 
 			result[name] = fieldCandi{
@@ -219,7 +219,8 @@ type RowsSuite struct {
 func (s *RowsSuite) TestRowsToColumns() {
 	s.Run("valid_cases", func() {
 
-		columns, err := RowsToColumns([]Row{&ValidStruct{}})
+		// vectors shall have the length of dim
+		columns, err := RowsToColumns([]Row{&ValidStruct{Vector: make([]float32, 16), Vector2: make([]byte, 4)}})
 		s.Nil(err)
 		s.Equal(10, len(columns))
 
@@ -234,7 +235,7 @@ func (s *RowsSuite) TestRowsToColumns() {
 			ID     int64     `milvus:"primary_key;auto_id"`
 			Vector []float32 `milvus:"dim:32"`
 		}
-		columns, err := RowsToColumns([]Row{&AutoPK{}})
+		columns, err := RowsToColumns([]Row{&AutoPK{Vector: make([]float32, 32)}})
 		s.Nil(err)
 		s.Require().Equal(1, len(columns))
 		s.Equal("Vector", columns[0].Name())
@@ -262,6 +263,22 @@ func (s *RowsSuite) TestRowsToColumns() {
 	})
 }
 
+func (s *RowsSuite) TestRowsToColumnsVectorLength() {
+	s.Run("empty_vectors", func() {
+		_, err := RowsToColumns([]Row{&ValidStruct{Vector2: []byte{1, 2, 3, 4}}})
+		s.Error(err)
+		_, err = RowsToColumns([]Row{&ValidStruct{Vector: make([]float32, 16)}})
+		s.Error(err)
+	})
+
+	s.Run("wrong_length", func() {
+		_, err := RowsToColumns([]Row{&ValidStruct{Vector: make([]float32, 8)}})
+		s.Error(err)
+		_, err = RowsToColumns([]Row{&ValidStruct{Vector2: make([]byte, 5)}})
+		s.Error(err)
+	})
+}
+
 func (s *RowsSuite) TestDynamicSchema() {
 	s.Run("all_fallback_dynamic", func() {
 		columns, err := RowsToColumns([]Row{&ValidStruct{}},
@@ -271,6 +288,28 @@ func (s *RowsSuite) TestDynamicSchema() {
 		s.Equal(1, len(columns))
 	})
 
+	s.Run("dynamic_tagged_map", func() {
+		type DynamicRow struct {
+			ID    int64                  `milvus:"primary_key"`
+			Score float32                `milvus:"score"`
+			Extra map[string]interface{} `milvus:"dynamic"`
+		}
+		sch, err := ParseSchemaAny(&DynamicRow{})
+		s.Require().NoError(err)
+		s.Require().Equal(1, len(sch.Fields))
+
+		columns, err := AnyToColumns([]interface{}{&DynamicRow{ID: 1, Score: 0.5, Extra: map[string]interface{}{"a": 1}}},
+			sch.WithDynamicFieldEnabled(true))
+		s.Require().NoError(err)
+		s.Require().Equal(2, len(columns))
+		for _, column := range columns {
+			if column, ok := column.(*ColumnJSONBytes); ok {
+				s.True(column.IsDynamic())
+				s.JSONEq(`{"a": 1}`, string(column.Data()[0]))
+			}
+		}
+	})
+
 	s.Run("dynamic_not_found", func() {
 		_, err := RowsToColumns([]Row{&ValidStruct{}},
 			NewSchema().WithField(
@@ -325,6 +364,47 @@ func (s *RowsSuite) TestReflectValueCandi() {
 	}
 }
 
+func (s *RowsSuite) TestAnyToColumnsFieldTypes() {
+	type Row struct {
+		ID     int64
+		Str    string
+		VarStr string
+		Vector [4]float32
+	}
+	sch := NewSchema().
+		WithField(NewField().WithName("ID").WithDataType(FieldTypeInt64).WithIsPrimaryKey(true)).
+		WithField(NewField().WithName("Str").WithDataType(FieldTypeString)).
+		WithField(NewField().WithName("VarStr").WithDataType(FieldTypeVarChar).WithMaxLength(16)).
+		WithField(NewField().WithName("Vector").WithDataType(FieldTypeFloatVector).WithDim(4))
+
+	for _, row := range []interface{}{
+		&Row{ID: 1, Str: "a", VarStr: "b", Vector: [4]float32{1, 2, 3, 4}},
+		// array fields of non-pointer struct are not addressable
+		Row{ID: 1, Str: "a", VarStr: "b", Vector: [4]float32{1, 2, 3, 4}},
+	} {
+		columns, err := AnyToColumns([]interface{}{row}, sch)
+		s.Require().NoError(err)
+		s.Require().Len(columns, 4)
+		byName := make(map[string]Column)
+		for _, column := range columns {
+			byName[column.Name()] = column
+		}
+
+		// string fields keep using ColumnString
+		str, ok := byName["Str"].(*ColumnString)
+		s.Require().True(ok)
+		s.Equal([]string{"a"}, str.Data())
+		// varchar fields are converted into ColumnVarChar instead of ColumnString
+		varStr, ok := byName["VarStr"].(*ColumnVarChar)
+		s.Require().True(ok)
+		s.Equal([]string{"b"}, varStr.Data())
+		// all elements of array are kept, the last one was dropped before
+		vector, ok := byName["Vector"].(*ColumnFloatVector)
+		s.Require().True(ok)
+		s.Equal([][]float32{{1, 2, 3, 4}}, vector.Data())
+	}
+}
+
 func TestRows(t *testing.T) {
 	suite.Run(t, new(RowsSuite))
 }
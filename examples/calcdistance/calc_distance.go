@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/distance"
 	"github.com/milvus-io/milvus-sdk-go/v2/entity"
 )
 
@@ -146,6 +147,16 @@ func main() {
 		log.Println("distance", rcol.Data())
 	}
 
+	// distance could be computed without server as well
+	r, err = distance.Columns(entity.L2, entity.NewColumnFloatVector("Vector", 8, vectors[0:2]), entity.NewColumnFloatVector("Vector", 8, vectors[3:4]))
+	if err != nil {
+		log.Fatal("failed to calc distance locally:", err.Error())
+	}
+	rcol, ok = r.(*entity.ColumnFloat)
+	if ok {
+		log.Println("local distance", rcol.Data())
+	}
+
 	// clean up
 	_ = c.DropCollection(ctx, collectionName)
 }
@@ -0,0 +1,14 @@
+package main
+
+import "encoding/json"
+
+//go:generate go run github.com/milvus-io/milvus-sdk-go/v2/cmd/rowgen -type=Film -collection=gosdk_rowgen_example
+
+// Film is the row struct of example collection, FilmBatch is generated from it.
+type Film struct {
+	ID     int64           `milvus:"name:id;primary_key"`
+	Title  string          `milvus:"name:title;max_length:256"`
+	Year   int32           `milvus:"name:year"`
+	Vector [8]float32      `milvus:"name:vector"`
+	Extra  json.RawMessage `milvus:"name:extra"`
+}
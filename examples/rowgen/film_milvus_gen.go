@@ -0,0 +1,199 @@
+// Code generated by rowgen; DO NOT EDIT.
+// This file is generated by go generate
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+// FilmBatch is the column-based batch of Film rows.
+type FilmBatch struct {
+	colID     []int64
+	colTitle  []string
+	colYear   []int32
+	colVector [][]float32
+	colExtra  [][]byte
+}
+
+// NewFilmBatch creates a FilmBatch with provided capacity.
+func NewFilmBatch(capacity int) *FilmBatch {
+	return &FilmBatch{
+		colID:     make([]int64, 0, capacity),
+		colTitle:  make([]string, 0, capacity),
+		colYear:   make([]int32, 0, capacity),
+		colVector: make([][]float32, 0, capacity),
+		colExtra:  make([][]byte, 0, capacity),
+	}
+}
+
+// Len returns the row count of the batch.
+func (b *FilmBatch) Len() int {
+	return len(b.colID)
+}
+
+// Reset clears the batch data and keeps allocated memory.
+func (b *FilmBatch) Reset() {
+	b.truncate(0)
+}
+
+// Append appends rows into the batch.
+// Batch is not modified if any row is invalid.
+func (b *FilmBatch) Append(rows ...Film) error {
+	n := b.Len()
+	for i := range rows {
+		if err := b.appendRow(&rows[i]); err != nil {
+			b.truncate(n)
+			return fmt.Errorf("row %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (b *FilmBatch) appendRow(row *Film) error {
+	bsExtra, err := json.Marshal(row.Extra)
+	if err != nil {
+		return fmt.Errorf("field extra: %w", err)
+	}
+	b.colID = append(b.colID, row.ID)
+	b.colTitle = append(b.colTitle, row.Title)
+	b.colYear = append(b.colYear, row.Year)
+	b.colVector = append(b.colVector, row.Vector[:])
+	b.colExtra = append(b.colExtra, bsExtra)
+	return nil
+}
+
+func (b *FilmBatch) truncate(n int) {
+	b.colID = b.colID[:n]
+	b.colTitle = b.colTitle[:n]
+	b.colYear = b.colYear[:n]
+	b.colVector = b.colVector[:n]
+	b.colExtra = b.colExtra[:n]
+}
+
+// Columns returns batch data as columns, which could be passed to Insert/Upsert directly.
+// The auto id primary key field is omitted.
+func (b *FilmBatch) Columns() []entity.Column {
+	return []entity.Column{
+		entity.NewColumnInt64("id", b.colID),
+		entity.NewColumnVarChar("title", b.colTitle),
+		entity.NewColumnInt32("year", b.colYear),
+		entity.NewColumnFloatVector("vector", 8, b.colVector),
+		entity.NewColumnJSONBytes("extra", b.colExtra),
+	}
+}
+
+// Schema returns the collection schema of Film.
+func (b *FilmBatch) Schema() *entity.Schema {
+	return entity.NewSchema().WithName("gosdk_rowgen_example").WithAutoID(false).
+		WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true).WithIsAutoID(false)).
+		WithField(entity.NewField().WithName("title").WithDataType(entity.FieldTypeVarChar).WithMaxLength(256)).
+		WithField(entity.NewField().WithName("year").WithDataType(entity.FieldTypeInt32)).
+		WithField(entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithDim(8)).
+		WithField(entity.NewField().WithName("extra").WithDataType(entity.FieldTypeJSON))
+}
+
+// OutputFields returns the field names of Film, which could be used as output fields in Search/Query.
+func (b *FilmBatch) OutputFields() []string {
+	return []string{"id", "title", "year", "vector", "extra"}
+}
+
+// Rows returns batch data as Film rows.
+func (b *FilmBatch) Rows() ([]Film, error) {
+	rows := make([]Film, b.Len())
+	for i := range rows {
+		rows[i].ID = b.colID[i]
+		rows[i].Title = b.colTitle[i]
+		rows[i].Year = b.colYear[i]
+		copy(rows[i].Vector[:], b.colVector[i])
+		if err := json.Unmarshal(b.colExtra[i], &rows[i].Extra); err != nil {
+			return nil, fmt.Errorf("row %d field extra: %w", i, err)
+		}
+	}
+	return rows, nil
+}
+
+// FromResultSet resets the batch and fills it with columns from Query/Search result.
+// Fields not present in the result are filled with zero values.
+func (b *FilmBatch) FromResultSet(rs []entity.Column) error {
+	columns := make(map[string]entity.Column, len(rs))
+	rowCount := -1
+	for _, column := range rs {
+		columns[column.Name()] = column
+		if rowCount >= 0 && column.Len() != rowCount {
+			return fmt.Errorf("column %s length %d not match %d", column.Name(), column.Len(), rowCount)
+		}
+		rowCount = column.Len()
+	}
+	if rowCount < 0 {
+		rowCount = 0
+	}
+	b.Reset()
+	if column, ok := columns["id"]; ok {
+		switch column := column.(type) {
+		case *entity.ColumnInt64:
+			b.colID = append(b.colID, column.Data()...)
+		default:
+			return fmt.Errorf("field id expects Int64 column, got %T", column)
+		}
+	} else {
+		b.colID = append(b.colID, make([]int64, rowCount)...)
+	}
+	if column, ok := columns["title"]; ok {
+		switch column := column.(type) {
+		case *entity.ColumnVarChar:
+			b.colTitle = append(b.colTitle, column.Data()...)
+		case *entity.ColumnString:
+			b.colTitle = append(b.colTitle, column.Data()...)
+		default:
+			return fmt.Errorf("field title expects VarChar column, got %T", column)
+		}
+	} else {
+		b.colTitle = append(b.colTitle, make([]string, rowCount)...)
+	}
+	if column, ok := columns["year"]; ok {
+		switch column := column.(type) {
+		case *entity.ColumnInt32:
+			b.colYear = append(b.colYear, column.Data()...)
+		default:
+			return fmt.Errorf("field year expects Int32 column, got %T", column)
+		}
+	} else {
+		b.colYear = append(b.colYear, make([]int32, rowCount)...)
+	}
+	if column, ok := columns["vector"]; ok {
+		switch column := column.(type) {
+		case *entity.ColumnFloatVector:
+			if column.Dim() != 8 {
+				return fmt.Errorf("field vector has dim %d, expected 8", column.Dim())
+			}
+			b.colVector = append(b.colVector, column.Data()...)
+		default:
+			return fmt.Errorf("field vector expects FloatVector column, got %T", column)
+		}
+	} else {
+		b.colVector = append(b.colVector, make([][]float32, rowCount)...)
+	}
+	if column, ok := columns["extra"]; ok {
+		switch column := column.(type) {
+		case *entity.ColumnJSONBytes:
+			b.colExtra = append(b.colExtra, column.Data()...)
+		case *entity.ColumnDynamic:
+			for i := 0; i < column.Len(); i++ {
+				raw, err := column.Get(i)
+				if err != nil {
+					raw = "null"
+				}
+				b.colExtra = append(b.colExtra, []byte(fmt.Sprint(raw)))
+			}
+		default:
+			return fmt.Errorf("field extra expects JSON column, got %T", column)
+		}
+	} else {
+		b.colExtra = append(b.colExtra, make([][]byte, rowCount)...)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+const (
+	milvusAddr = `localhost:19530`
+	nEntities  = 1000
+
+	msgFmt = "==== %s ====\n"
+)
+
+func main() {
+	ctx := context.Background()
+
+	log.Printf(msgFmt, "start connecting to Milvus")
+	c, err := client.NewClient(ctx, client.Config{
+		Address: milvusAddr,
+	})
+	if err != nil {
+		log.Fatalf("failed to connect to milvus, err: %v", err)
+	}
+	defer c.Close()
+
+	// schema is generated from Film struct tags, no reflection needed
+	batch := NewFilmBatch(nEntities)
+	schema := batch.Schema()
+	collectionName := schema.CollectionName
+
+	has, err := c.HasCollection(ctx, collectionName)
+	if err != nil {
+		log.Fatalf("failed to check collection exists, err: %v", err)
+	}
+	if has {
+		_ = c.DropCollection(ctx, collectionName)
+	}
+
+	log.Printf(msgFmt, fmt.Sprintf("create collection, `%s`", collectionName))
+	if err := c.CreateCollection(ctx, schema, entity.DefaultShardNumber); err != nil {
+		log.Fatalf("create collection failed, err: %v", err)
+	}
+
+	log.Printf(msgFmt, "start inserting rows")
+	for i := 0; i < nEntities; i++ {
+		film := Film{
+			ID:    int64(i),
+			Title: fmt.Sprintf("film_%d", i),
+			Year:  int32(1900 + rand.Intn(120)),
+			Extra: json.RawMessage(fmt.Sprintf(`{"rating": %d}`, rand.Intn(10))),
+		}
+		for j := range film.Vector {
+			film.Vector[j] = rand.Float32()
+		}
+		// type checked at compile time
+		if err := batch.Append(film); err != nil {
+			log.Fatalf("failed to append row, err: %v", err)
+		}
+	}
+	if _, err := c.Insert(ctx, collectionName, "", batch.Columns()...); err != nil {
+		log.Fatalf("failed to insert rows, err: %v", err)
+	}
+	if err := c.Flush(ctx, collectionName, false); err != nil {
+		log.Fatalf("failed to flush collection, err: %v", err)
+	}
+
+	idx, err := entity.NewIndexFlat(entity.L2)
+	if err != nil {
+		log.Fatalf("failed to create flat index, err: %v", err)
+	}
+	if err := c.CreateIndex(ctx, collectionName, "vector", idx, false); err != nil {
+		log.Fatalf("failed to create index, err: %v", err)
+	}
+	if err := c.LoadCollection(ctx, collectionName, false); err != nil {
+		log.Fatalf("failed to load collection, err: %v", err)
+	}
+
+	log.Printf(msgFmt, "query rows back")
+	rs, err := c.Query(ctx, collectionName, nil, "id < 10", batch.OutputFields())
+	if err != nil {
+		log.Fatalf("failed to query, err: %v", err)
+	}
+	if err := batch.FromResultSet(rs); err != nil {
+		log.Fatalf("failed to read result set, err: %v", err)
+	}
+	films, err := batch.Rows()
+	if err != nil {
+		log.Fatalf("failed to convert rows, err: %v", err)
+	}
+	for _, film := range films {
+		log.Printf("id: %d, title: %s, year: %d, extra: %s\n", film.ID, film.Title, film.Year, string(film.Extra))
+	}
+
+	_ = c.DropCollection(ctx, collectionName)
+}
@@ -0,0 +1,323 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package expr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+// nodeType is the inferred type of expression node.
+type nodeType int
+
+const (
+	typeAny nodeType = iota // json value or dynamic field, whose type is unknown before evaluation
+	typeBool
+	typeInt
+	typeFloat
+	typeString
+	typeJSON // json field without path
+	typeVector
+	typeList
+)
+
+func (t nodeType) String() string {
+	switch t {
+	case typeBool:
+		return "bool"
+	case typeInt:
+		return "int"
+	case typeFloat:
+		return "float"
+	case typeString:
+		return "string"
+	case typeJSON:
+		return "json"
+	case typeVector:
+		return "vector"
+	case typeList:
+		return "list"
+	}
+	return "any"
+}
+
+func (t nodeType) numeric() bool {
+	return t == typeInt || t == typeFloat || t == typeAny
+}
+
+// scalar reports whether value of the type could be compared.
+func (t nodeType) scalar() bool {
+	return t != typeJSON && t != typeVector && t != typeList
+}
+
+// ParseFor parses the expression and checks it against schema.
+func ParseFor(input string, sch *entity.Schema) (Node, error) {
+	node, err := Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := Check(node, sch); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// Check checks field names and operand types of the expression tree against schema.
+// Fields not in schema are treated as keys of dynamic field when dynamic field is enabled,
+// whose types are checked during evaluation only.
+func Check(node Node, sch *entity.Schema) error {
+	if sch == nil {
+		return errors.New("schema cannot be nil")
+	}
+	c := &checker{fields: make(map[string]*entity.Field, len(sch.Fields)), dynamic: sch.EnableDynamicField}
+	for _, field := range sch.Fields {
+		c.fields[field.Name] = field
+	}
+	t, err := c.typeOf(node)
+	if err != nil {
+		return err
+	}
+	if t != typeBool && t != typeAny {
+		return fmt.Errorf("expression %s is %s, not bool", node, t)
+	}
+	return nil
+}
+
+type checker struct {
+	fields  map[string]*entity.Field
+	dynamic bool
+}
+
+func (c *checker) typeOf(node Node) (nodeType, error) {
+	switch n := node.(type) {
+	case *Literal:
+		switch n.Value.(type) {
+		case bool:
+			return typeBool, nil
+		case int64:
+			return typeInt, nil
+		case float64:
+			return typeFloat, nil
+		case string:
+			return typeString, nil
+		}
+		return typeAny, fmt.Errorf("unsupported literal %v", n.Value)
+	case *List:
+		for _, v := range n.Values {
+			if _, err := c.typeOf(v); err != nil {
+				return typeList, err
+			}
+		}
+		return typeList, nil
+	case *Ident:
+		return c.identType(n)
+	case *Logical:
+		if err := c.expectBool(n.Left); err != nil {
+			return typeBool, err
+		}
+		return typeBool, c.expectBool(n.Right)
+	case *NotNode:
+		return typeBool, c.expectBool(n.Expr)
+	case *Arith:
+		return c.arithType(n)
+	case *Compare:
+		return typeBool, c.checkCompare(n)
+	case *InNode:
+		return typeBool, c.checkIn(n)
+	case *LikeNode:
+		t, err := c.typeOf(n.Target)
+		if err != nil {
+			return typeBool, err
+		}
+		if t != typeString && t != typeAny {
+			return typeBool, fmt.Errorf("like is not supported on %s of type %s", n.Target, t)
+		}
+		return typeBool, nil
+	case *ExistsNode:
+		t, err := c.typeOf(n.Target)
+		if err != nil {
+			return typeBool, err
+		}
+		if t != typeAny && t != typeJSON {
+			return typeBool, fmt.Errorf("exists is not supported on %s of type %s", n.Target, t)
+		}
+		return typeBool, nil
+	case *Call:
+		return c.callType(n)
+	}
+	return typeAny, fmt.Errorf("unsupported expression %s", node)
+}
+
+func (c *checker) expectBool(node Node) error {
+	t, err := c.typeOf(node)
+	if err != nil {
+		return err
+	}
+	if t != typeBool && t != typeAny {
+		return fmt.Errorf("expression %s is %s, not bool", node, t)
+	}
+	return nil
+}
+
+func (c *checker) identType(n *Ident) (nodeType, error) {
+	field, ok := c.fields[n.Name]
+	if !ok || field.IsDynamic {
+		if n.Name == "$meta" || c.dynamic {
+			return typeAny, nil
+		}
+		return typeAny, fmt.Errorf("field %s not found in schema", n.Name)
+	}
+
+	var t nodeType
+	switch field.DataType {
+	case entity.FieldTypeJSON:
+		if len(n.Path) > 0 {
+			return typeAny, nil
+		}
+		return typeJSON, nil
+	case entity.FieldTypeBool:
+		t = typeBool
+	case entity.FieldTypeInt8, entity.FieldTypeInt16, entity.FieldTypeInt32, entity.FieldTypeInt64:
+		t = typeInt
+	case entity.FieldTypeFloat, entity.FieldTypeDouble:
+		t = typeFloat
+	case entity.FieldTypeVarChar, entity.FieldTypeString:
+		t = typeString
+	case entity.FieldTypeFloatVector, entity.FieldTypeBinaryVector:
+		return typeVector, fmt.Errorf("vector field %s cannot be used in filter expression", field.Name)
+	default:
+		return typeAny, fmt.Errorf("field %s of type %s is not supported in filter expression", field.Name, field.DataType.Name())
+	}
+	if len(n.Path) > 0 {
+		return t, fmt.Errorf("field %s of type %s does not support json path", field.Name, field.DataType.Name())
+	}
+	return t, nil
+}
+
+func (c *checker) arithType(n *Arith) (nodeType, error) {
+	left, err := c.typeOf(n.Left)
+	if err != nil {
+		return typeAny, err
+	}
+	right, err := c.typeOf(n.Right)
+	if err != nil {
+		return typeAny, err
+	}
+	if !left.numeric() || !right.numeric() {
+		return typeAny, fmt.Errorf("arithmetic operation %s requires numbers, got %s and %s", n, left, right)
+	}
+	switch {
+	case n.Op == "%" && (left == typeFloat || right == typeFloat):
+		return typeAny, fmt.Errorf("modulo operation %s requires integers", n)
+	case left == typeAny || right == typeAny:
+		return typeAny, nil
+	case left == typeFloat || right == typeFloat || n.Op == "/":
+		return typeFloat, nil
+	}
+	return typeInt, nil
+}
+
+func (c *checker) checkCompare(n *Compare) error {
+	left, err := c.typeOf(n.Left)
+	if err != nil {
+		return err
+	}
+	right, err := c.typeOf(n.Right)
+	if err != nil {
+		return err
+	}
+	if err := comparable(left, right); err != nil {
+		return fmt.Errorf("cannot compare %s: %w", n, err)
+	}
+	if floatToIntField(n.Left, left, n.Right) || floatToIntField(n.Right, right, n.Left) {
+		return fmt.Errorf("cannot compare %s: integer field with float value", n)
+	}
+	if (left == typeBool || right == typeBool) && n.Op != "==" && n.Op != "!=" {
+		return fmt.Errorf("bool values only support == and !=, got %s", n)
+	}
+	return nil
+}
+
+func (c *checker) checkIn(n *InNode) error {
+	target, err := c.typeOf(n.Target)
+	if err != nil {
+		return err
+	}
+	for _, v := range n.Values.Values {
+		t, err := c.typeOf(v)
+		if err != nil {
+			return err
+		}
+		if err := comparable(target, t); err != nil {
+			return fmt.Errorf("invalid in expression %s: %w", n, err)
+		}
+		if floatToIntField(n.Target, target, v) {
+			return fmt.Errorf("invalid in expression %s: integer field with float value", n)
+		}
+	}
+	return nil
+}
+
+func (c *checker) callType(n *Call) (nodeType, error) {
+	target, err := c.typeOf(n.Args[0])
+	if err != nil {
+		return typeAny, err
+	}
+	if target != typeAny && target != typeJSON {
+		return typeAny, fmt.Errorf("%s is not supported on %s of type %s", n.Func, n.Args[0], target)
+	}
+	if n.Func == "array_length" {
+		return typeInt, nil
+	}
+	value, err := c.typeOf(n.Args[1])
+	if err != nil {
+		return typeBool, err
+	}
+	all := strings.HasSuffix(n.Func, "_all") || strings.HasSuffix(n.Func, "_any")
+	if all && value != typeList {
+		return typeBool, fmt.Errorf("%s requires a list, got %s", n.Func, n.Args[1])
+	}
+	if !all && value != typeList && !value.scalar() {
+		return typeBool, fmt.Errorf("%s requires a value, got %s", n.Func, n.Args[1])
+	}
+	return typeBool, nil
+}
+
+func comparable(left, right nodeType) error {
+	if !left.scalar() || !right.scalar() {
+		return fmt.Errorf("%s and %s are not comparable", left, right)
+	}
+	if left == typeAny || right == typeAny || left == right {
+		return nil
+	}
+	if left.numeric() && right.numeric() {
+		return nil
+	}
+	return fmt.Errorf("%s and %s are not comparable", left, right)
+}
+
+// floatToIntField reports whether float literal is compared with integer field, which is rejected by server.
+func floatToIntField(field Node, t nodeType, value Node) bool {
+	if _, ok := field.(*Ident); !ok || t != typeInt {
+		return false
+	}
+	lit, ok := value.(*Literal)
+	if !ok {
+		return false
+	}
+	_, isFloat := lit.Value.(float64)
+	return isFloat
+}
@@ -0,0 +1,407 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package expr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// Evaluate evaluates the expression tree against a row, which maps field name to value.
+// Keys of dynamic field shall be flattened into the row, json fields could be either []byte or decoded value.
+//
+// Like the server, comparisons with missing json keys or values of mismatched types are false.
+func Evaluate(node Node, row map[string]interface{}) (bool, error) {
+	e := &evaluator{row: row, decoded: make(map[string]interface{})}
+	return e.eval(node)
+}
+
+type evaluator struct {
+	row     map[string]interface{}
+	decoded map[string]interface{} // cache of decoded json fields
+}
+
+func (e *evaluator) eval(node Node) (bool, error) {
+	switch n := node.(type) {
+	case *Logical:
+		left, err := e.eval(n.Left)
+		if err != nil {
+			return false, err
+		}
+		if n.Op == "and" && !left || n.Op == "or" && left {
+			return left, nil
+		}
+		return e.eval(n.Right)
+	case *NotNode:
+		v, err := e.eval(n.Expr)
+		return !v, err
+	case *Compare:
+		left, lok, err := e.value(n.Left)
+		if err != nil || !lok {
+			return false, err
+		}
+		right, rok, err := e.value(n.Right)
+		if err != nil || !rok {
+			return false, err
+		}
+		return compareOp(n.Op, left, right), nil
+	case *InNode:
+		target, ok, err := e.value(n.Target)
+		if err != nil || !ok {
+			return false, err
+		}
+		found := false
+		for _, v := range n.Values.Values {
+			value, _, _ := e.value(v)
+			if equal(target, value) {
+				found = true
+				break
+			}
+		}
+		return found != n.Not, nil
+	case *LikeNode:
+		target, ok, err := e.value(n.Target)
+		if err != nil || !ok {
+			return false, err
+		}
+		s, ok := target.(string)
+		if !ok {
+			return false, nil
+		}
+		return likePattern(n.Pattern).MatchString(s), nil
+	case *ExistsNode:
+		_, ok, err := e.value(n.Target)
+		return ok, err
+	case *Call:
+		return e.call(n)
+	default:
+		v, ok, err := e.value(node)
+		if err != nil || !ok {
+			return false, err
+		}
+		b, isBool := v.(bool)
+		if !isBool {
+			return false, fmt.Errorf("expression %s is not bool", node)
+		}
+		return b, nil
+	}
+}
+
+// value evaluates the node as value, ok is false when the value is missing or null.
+func (e *evaluator) value(node Node) (interface{}, bool, error) {
+	switch n := node.(type) {
+	case *Literal:
+		return n.Value, true, nil
+	case *List:
+		values := make([]interface{}, 0, len(n.Values))
+		for _, v := range n.Values {
+			value, _, err := e.value(v)
+			if err != nil {
+				return nil, false, err
+			}
+			values = append(values, value)
+		}
+		return values, true, nil
+	case *Ident:
+		return e.lookup(n)
+	case *Arith:
+		return e.arith(n)
+	case *Call:
+		if n.Func == "array_length" {
+			v, ok, err := e.value(n.Args[0])
+			if err != nil || !ok {
+				return nil, false, err
+			}
+			arr, ok := v.([]interface{})
+			if !ok {
+				return nil, false, nil
+			}
+			return int64(len(arr)), true, nil
+		}
+	}
+	v, err := e.eval(node)
+	return v, err == nil, err
+}
+
+func (e *evaluator) lookup(n *Ident) (interface{}, bool, error) {
+	var v interface{}
+	if n.Name == "$meta" {
+		// keys of dynamic field are flattened into row
+		if len(n.Path) == 0 {
+			return nil, false, nil
+		}
+		key, ok := n.Path[0].(string)
+		if !ok {
+			return nil, false, nil
+		}
+		return e.lookup(&Ident{Name: key, Path: n.Path[1:]})
+	}
+	v, ok := e.row[n.Name]
+	if !ok {
+		return nil, false, nil
+	}
+	if bs, isBytes := v.([]byte); isBytes {
+		decoded, cached := e.decoded[n.Name]
+		if !cached {
+			dec := json.NewDecoder(bytes.NewReader(bs))
+			dec.UseNumber()
+			if err := dec.Decode(&decoded); err != nil {
+				return nil, false, fmt.Errorf("failed to decode json field %s: %w", n.Name, err)
+			}
+			e.decoded[n.Name] = decoded
+		}
+		v = decoded
+	}
+	for _, p := range n.Path {
+		switch p := p.(type) {
+		case string:
+			m, isMap := v.(map[string]interface{})
+			if !isMap {
+				return nil, false, nil
+			}
+			if v, ok = m[p]; !ok {
+				return nil, false, nil
+			}
+		case int:
+			arr, isArr := v.([]interface{})
+			if !isArr || p < 0 || p >= len(arr) {
+				return nil, false, nil
+			}
+			v = arr[p]
+		}
+	}
+	v = normalize(v)
+	return v, v != nil, nil
+}
+
+func (e *evaluator) arith(n *Arith) (interface{}, bool, error) {
+	left, ok, err := e.value(n.Left)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	right, ok, err := e.value(n.Right)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	li, lint := left.(int64)
+	ri, rint := right.(int64)
+	if lint && rint && n.Op != "/" {
+		switch n.Op {
+		case "+":
+			return li + ri, true, nil
+		case "-":
+			return li - ri, true, nil
+		case "*":
+			return li * ri, true, nil
+		case "%":
+			if ri == 0 {
+				return nil, false, fmt.Errorf("modulo by zero in %s", n)
+			}
+			return li % ri, true, nil
+		}
+	}
+	lf, lok := toFloat(left)
+	rf, rok := toFloat(right)
+	if !lok || !rok {
+		return nil, false, nil
+	}
+	switch n.Op {
+	case "+":
+		return lf + rf, true, nil
+	case "-":
+		return lf - rf, true, nil
+	case "*":
+		return lf * rf, true, nil
+	case "/":
+		if rf == 0 {
+			return nil, false, fmt.Errorf("division by zero in %s", n)
+		}
+		return lf / rf, true, nil
+	case "%":
+		return math.Mod(lf, rf), true, nil
+	}
+	return nil, false, fmt.Errorf("unsupported operator %s", n.Op)
+}
+
+func (e *evaluator) call(n *Call) (bool, error) {
+	target, ok, err := e.value(n.Args[0])
+	if err != nil || !ok {
+		return false, err
+	}
+	arr, ok := target.([]interface{})
+	if !ok {
+		return false, nil
+	}
+	value, _, err := e.value(n.Args[1])
+	if err != nil {
+		return false, err
+	}
+	contains := func(v interface{}) bool {
+		for _, elem := range arr {
+			if equal(normalize(elem), v) {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case strings.HasSuffix(n.Func, "_all"), strings.HasSuffix(n.Func, "_any"):
+		values, _ := value.([]interface{})
+		all := strings.HasSuffix(n.Func, "_all")
+		for _, v := range values {
+			if contains(v) != all {
+				return !all, nil
+			}
+		}
+		return all, nil
+	case strings.HasSuffix(n.Func, "_contains"):
+		return contains(value), nil
+	}
+	return false, fmt.Errorf("function %s does not return bool", n.Func)
+}
+
+// normalize converts integers into int64, floats into float64 and json numbers into either.
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case int:
+		return int64(v)
+	case int8:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint8:
+		return int64(v)
+	case uint16:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case float32:
+		return float64(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return v
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// compare returns the order of a and b, ok is false if they are not comparable.
+func compare(a, b interface{}) (int, bool) {
+	a, b = normalize(a), normalize(b)
+	ai, aint := a.(int64)
+	bi, bint := b.(int64)
+	if aint && bint {
+		return compareOrdered(ai, bi), true
+	}
+	af, aok := toFloat(a)
+	bf, bok := toFloat(b)
+	if aok && bok {
+		return compareOrdered(af, bf), true
+	}
+	as, aok := a.(string)
+	bs, bok := b.(string)
+	if aok && bok {
+		return strings.Compare(as, bs), true
+	}
+	ab, aok := a.(bool)
+	bb, bok := b.(bool)
+	if aok && bok && ab == bb {
+		return 0, true
+	}
+	return 0, false
+}
+
+func compareOrdered[T int64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func equal(a, b interface{}) bool {
+	c, ok := compare(a, b)
+	return ok && c == 0
+}
+
+func compareOp(op string, a, b interface{}) bool {
+	if op == "!=" {
+		// bools of different values are not ordered but still comparable for inequality
+		ab, aok := a.(bool)
+		bb, bok := b.(bool)
+		if aok && bok {
+			return ab != bb
+		}
+	}
+	c, ok := compare(a, b)
+	if !ok {
+		return false
+	}
+	switch op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+// likePattern converts like pattern into regular expression, `%` matches any characters
+// and `_` matches single character, both could be escaped by `\`.
+func likePattern(pattern string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("^(?s:")
+	for i := 0; i < len(pattern); i++ {
+		ch := pattern[i]
+		switch {
+		case ch == '\\' && i+1 < len(pattern):
+			i++
+			sb.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		case ch == '%':
+			sb.WriteString(".*")
+		case ch == '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	sb.WriteString(")$")
+	return regexp.MustCompile(sb.String())
+}
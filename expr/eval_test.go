@@ -0,0 +1,107 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package expr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	row := map[string]interface{}{
+		"id":    int64(10),
+		"age":   int32(30),
+		"score": float32(1.5),
+		"title": "hello world",
+		"ok":    true,
+		"meta":  []byte(`{"a": {"b": [1, 2, 3]}, "tags": ["x", "y"], "n": null, "big": 9007199254740993}`),
+		"color": "red", // flattened dynamic key
+	}
+
+	cases := []struct {
+		input    string
+		expected bool
+	}{
+		{`id == 10`, true},
+		{`id != 10`, false},
+		{`age > 18 and age < 60`, true},
+		{`18 < age <= 29`, false},
+		{`score >= 1.5`, true},
+		{`score == 1`, false},
+		{`id in [1, 10]`, true},
+		{`id not in [1, 10]`, false},
+		{`id in [10.0]`, true},
+		{`title like "hello%"`, true},
+		{`title like "%world"`, true},
+		{`title like "%lo w%"`, true},
+		{`title like "hell_ world"`, true},
+		{`title like "hello"`, false},
+		{`ok`, true},
+		{`!ok || id == 10`, true},
+		{`ok == false`, false},
+		{`ok != false`, true},
+		{`meta["a"]["b"][1] == 2`, true},
+		{`meta["a"]["b"][5] == 2`, false},
+		{`meta["a"]["b"][5] != 2`, false},
+		{`not meta["a"]["b"][5] == 2`, true},
+		{`meta["tags"][0] == 1`, false},
+		{`meta["big"] == 9007199254740993`, true},
+		{`exists meta["a"]["b"]`, true},
+		{`exists meta["c"]`, false},
+		{`exists meta["n"]`, false},
+		{`array_contains(meta["tags"], "x")`, true},
+		{`json_contains(meta["a"]["b"], 4)`, false},
+		{`array_contains_all(meta["a"]["b"], [1, 3])`, true},
+		{`array_contains_all(meta["a"]["b"], [1, 4])`, false},
+		{`array_contains_any(meta["a"]["b"], [4, 3])`, true},
+		{`array_contains_any(meta["a"]["b"], [4, 5])`, false},
+		{`array_contains(title, "h")`, false},
+		{`array_length(meta["tags"]) == 2`, true},
+		{`id + age * 2 == 70`, true},
+		{`id / 4 == 2.5`, true},
+		{`id % 3 == 1`, true},
+		{`score * 2 == 3`, true},
+		{`color == "red"`, true},
+		{`$meta["color"] in ["red", "blue"]`, true},
+		{`missing == 1`, false},
+		{`missing in [1]`, false},
+		{`missing not in [1]`, false},
+		{`title > "apple"`, true},
+		{`title == 1`, false},
+	}
+	for _, c := range cases {
+		node, err := Parse(c.input)
+		require.NoError(t, err, c.input)
+		result, err := Evaluate(node, row)
+		require.NoError(t, err, c.input)
+		assert.Equal(t, c.expected, result, c.input)
+	}
+
+	errCases := []string{
+		`title`,
+		`id % 0 == 1`,
+		`id / 0 == 1`,
+	}
+	for _, input := range errCases {
+		node, err := Parse(input)
+		require.NoError(t, err, input)
+		_, err = Evaluate(node, row)
+		assert.Error(t, err, input)
+	}
+
+	node, err := Parse(`meta["a"] == 1`)
+	require.NoError(t, err)
+	_, err = Evaluate(node, map[string]interface{}{"meta": []byte(`{`)})
+	assert.Error(t, err)
+}
@@ -0,0 +1,383 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+// Package expr builds boolean filter expressions used by Query, Search and Delete.
+//
+//	expr.Field("age").Gt(18).And(expr.Field("tag").In("a", "b"))
+//
+// renders `age > 18 and tag in ["a","b"]`. String values are quoted and escaped,
+// JSON paths are rendered as `field["key"][0]`.
+//
+// Expression strings could also be parsed by Parse, checked against schema by Check
+// and evaluated against fetched rows by Evaluate.
+package expr
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+var identifierPattern = regexp.MustCompile(`^(\$meta|[A-Za-z_][A-Za-z0-9_]*)$`)
+
+// valueKind is the kind of literal value used in expression.
+type valueKind int
+
+const (
+	kindBool valueKind = iota + 1
+	kindInt
+	kindFloat
+	kindString
+)
+
+func (k valueKind) String() string {
+	switch k {
+	case kindBool:
+		return "bool"
+	case kindInt:
+		return "int"
+	case kindFloat:
+		return "float"
+	case kindString:
+		return "string"
+	}
+	return "unknown"
+}
+
+// operator is the kind of field operation, used in schema validation.
+type operator int
+
+const (
+	opCompare operator = iota + 1
+	opIn
+	opLike
+	opArrayContains
+	opExists
+)
+
+// reference records a field operation of expression for schema validation.
+type reference struct {
+	field   string
+	hasPath bool
+	op      operator
+	kinds   []valueKind
+}
+
+// Expr is a boolean filter expression.
+type Expr struct {
+	text     string
+	compound bool // whether expression is a logical combination, which shall be parenthesized
+	refs     []reference
+	err      error
+}
+
+// Raw wraps expression string as Expr, which is not validated against schema.
+func Raw(text string) Expr {
+	return Expr{text: text, compound: true}
+}
+
+// String returns the expression string.
+func (e Expr) String() string {
+	return e.text
+}
+
+// Build returns the expression string, or the error occurred while building it.
+func (e Expr) Build() (string, error) {
+	if e.err != nil {
+		return "", e.err
+	}
+	return e.text, nil
+}
+
+// And returns the conjunction of e and others.
+func (e Expr) And(others ...Expr) Expr {
+	return And(append([]Expr{e}, others...)...)
+}
+
+// Or returns the disjunction of e and others.
+func (e Expr) Or(others ...Expr) Expr {
+	return Or(append([]Expr{e}, others...)...)
+}
+
+// Not returns the negation of e.
+func (e Expr) Not() Expr {
+	return Not(e)
+}
+
+// And returns the conjunction of exprs.
+func And(exprs ...Expr) Expr {
+	return combine("and", exprs)
+}
+
+// Or returns the disjunction of exprs.
+func Or(exprs ...Expr) Expr {
+	return combine("or", exprs)
+}
+
+// Not returns the negation of e.
+func Not(e Expr) Expr {
+	return Expr{
+		text:     "not " + e.parenthesized(),
+		compound: true,
+		refs:     e.refs,
+		err:      e.err,
+	}
+}
+
+func combine(op string, exprs []Expr) Expr {
+	if len(exprs) == 0 {
+		return Expr{err: fmt.Errorf("%s requires at least one expression", op)}
+	}
+	if len(exprs) == 1 {
+		return exprs[0]
+	}
+	result := Expr{compound: true}
+	parts := make([]string, 0, len(exprs))
+	for _, e := range exprs {
+		if e.err != nil && result.err == nil {
+			result.err = e.err
+		}
+		result.refs = append(result.refs, e.refs...)
+		parts = append(parts, e.parenthesized())
+	}
+	result.text = strings.Join(parts, " "+op+" ")
+	return result
+}
+
+func (e Expr) parenthesized() string {
+	if e.compound {
+		return "(" + e.text + ")"
+	}
+	return e.text
+}
+
+// FieldRef refers to a field, or a path inside JSON field.
+type FieldRef struct {
+	name string
+	path []string // rendered path elements
+	err  error
+}
+
+// Field returns reference to field with provided name.
+// Keys of dynamic field could be referred by name directly when dynamic field is enabled.
+func Field(name string) FieldRef {
+	f := FieldRef{name: name}
+	if !identifierPattern.MatchString(name) {
+		f.err = fmt.Errorf("invalid field name %q", name)
+	}
+	return f
+}
+
+// Key returns reference to key of JSON object.
+func (f FieldRef) Key(key string) FieldRef {
+	f.path = append(append([]string(nil), f.path...), "["+quote(key)+"]")
+	return f
+}
+
+// Index returns reference to element of JSON array.
+func (f FieldRef) Index(idx int) FieldRef {
+	if idx < 0 && f.err == nil {
+		f.err = fmt.Errorf("invalid array index %d", idx)
+	}
+	f.path = append(append([]string(nil), f.path...), "["+strconv.Itoa(idx)+"]")
+	return f
+}
+
+// Path returns reference to the JSON path like `a.b[0]` relative to f.
+func (f FieldRef) Path(path string) FieldRef {
+	for _, segment := range strings.Split(path, ".") {
+		key := segment
+		rest := ""
+		if i := strings.IndexByte(segment, '['); i >= 0 {
+			key, rest = segment[:i], segment[i:]
+		}
+		if key != "" {
+			f = f.Key(key)
+		}
+		for rest != "" {
+			end := strings.IndexByte(rest, ']')
+			if rest[0] != '[' || end < 0 {
+				f.err = fmt.Errorf("invalid json path %q", path)
+				return f
+			}
+			idx, err := strconv.Atoi(rest[1:end])
+			if err != nil {
+				f.err = fmt.Errorf("invalid json path %q", path)
+				return f
+			}
+			f = f.Index(idx)
+			rest = rest[end+1:]
+		}
+	}
+	return f
+}
+
+func (f FieldRef) String() string {
+	return f.name + strings.Join(f.path, "")
+}
+
+func (f FieldRef) expr(text string, op operator, kinds []valueKind, err error) Expr {
+	if err == nil {
+		err = f.err
+	}
+	return Expr{
+		text: text,
+		refs: []reference{{field: f.name, hasPath: len(f.path) > 0, op: op, kinds: kinds}},
+		err:  err,
+	}
+}
+
+func (f FieldRef) compare(op string, value interface{}) Expr {
+	literal, kind, err := formatValue(value)
+	return f.expr(fmt.Sprintf("%s %s %s", f, op, literal), opCompare, []valueKind{kind}, err)
+}
+
+// Eq returns expression `f == value`.
+func (f FieldRef) Eq(value interface{}) Expr { return f.compare("==", value) }
+
+// Ne returns expression `f != value`.
+func (f FieldRef) Ne(value interface{}) Expr { return f.compare("!=", value) }
+
+// Gt returns expression `f > value`.
+func (f FieldRef) Gt(value interface{}) Expr { return f.compare(">", value) }
+
+// Ge returns expression `f >= value`.
+func (f FieldRef) Ge(value interface{}) Expr { return f.compare(">=", value) }
+
+// Lt returns expression `f < value`.
+func (f FieldRef) Lt(value interface{}) Expr { return f.compare("<", value) }
+
+// Le returns expression `f <= value`.
+func (f FieldRef) Le(value interface{}) Expr { return f.compare("<=", value) }
+
+// In returns expression `f in [values]`, a single slice argument is expanded as values.
+func (f FieldRef) In(values ...interface{}) Expr {
+	list, kinds, err := formatList(values)
+	return f.expr(fmt.Sprintf("%s in %s", f, list), opIn, kinds, err)
+}
+
+// NotIn returns expression `f not in [values]`, a single slice argument is expanded as values.
+func (f FieldRef) NotIn(values ...interface{}) Expr {
+	list, kinds, err := formatList(values)
+	return f.expr(fmt.Sprintf("%s not in %s", f, list), opIn, kinds, err)
+}
+
+// Like returns expression `f like pattern`, `%` in pattern matches any characters.
+func (f FieldRef) Like(pattern string) Expr {
+	return f.expr(fmt.Sprintf("%s like %s", f, quote(pattern)), opLike, []valueKind{kindString}, nil)
+}
+
+// ArrayContains returns expression `array_contains(f, value)`.
+func (f FieldRef) ArrayContains(value interface{}) Expr {
+	literal, kind, err := formatValue(value)
+	return f.expr(fmt.Sprintf("array_contains(%s, %s)", f, literal), opArrayContains, []valueKind{kind}, err)
+}
+
+// ArrayContainsAll returns expression `array_contains_all(f, [values])`.
+func (f FieldRef) ArrayContainsAll(values ...interface{}) Expr {
+	list, kinds, err := formatList(values)
+	return f.expr(fmt.Sprintf("array_contains_all(%s, %s)", f, list), opArrayContains, kinds, err)
+}
+
+// ArrayContainsAny returns expression `array_contains_any(f, [values])`.
+func (f FieldRef) ArrayContainsAny(values ...interface{}) Expr {
+	list, kinds, err := formatList(values)
+	return f.expr(fmt.Sprintf("array_contains_any(%s, %s)", f, list), opArrayContains, kinds, err)
+}
+
+// Exists returns expression `exists f`, which is true when the JSON path exists.
+func (f FieldRef) Exists() Expr {
+	return f.expr(fmt.Sprintf("exists %s", f), opExists, nil, nil)
+}
+
+// formatValue returns the literal of value.
+func formatValue(value interface{}) (string, valueKind, error) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), kindBool, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), kindInt, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), kindInt, nil
+	case reflect.Float32, reflect.Float64:
+		v := rv.Float()
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", kindFloat, fmt.Errorf("invalid float value %v", v)
+		}
+		literal := strconv.FormatFloat(v, 'f', -1, rv.Type().Bits())
+		if !strings.Contains(literal, ".") {
+			literal += ".0"
+		}
+		return literal, kindFloat, nil
+	case reflect.String:
+		return quote(rv.String()), kindString, nil
+	default:
+		return "", 0, fmt.Errorf("unsupported value type %T", value)
+	}
+}
+
+// quote returns the double quoted string literal of s.
+// Only backslash, double quote and line breaks, which are not allowed in string literal by the grammar,
+// are escaped with the escape sequences of the grammar, other characters are kept as is.
+func quote(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s) + 2)
+	sb.WriteByte('"')
+	// escaped characters are all ASCII, iterating bytes keeps other bytes unchanged
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// formatList returns the list literal of values.
+func formatList(values []interface{}) (string, []valueKind, error) {
+	if len(values) == 1 {
+		rv := reflect.ValueOf(values[0])
+		if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+			values = make([]interface{}, 0, rv.Len())
+			for i := 0; i < rv.Len(); i++ {
+				values = append(values, rv.Index(i).Interface())
+			}
+		}
+	}
+	if len(values) == 0 {
+		return "[]", nil, errors.New("empty value list")
+	}
+	literals := make([]string, 0, len(values))
+	kinds := make([]valueKind, 0, len(values))
+	for _, value := range values {
+		literal, kind, err := formatValue(value)
+		if err != nil {
+			return "", nil, err
+		}
+		literals = append(literals, literal)
+		kinds = append(kinds, kind)
+	}
+	return "[" + strings.Join(literals, ",") + "]", kinds, nil
+}
@@ -0,0 +1,134 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package expr
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+func TestBuild(t *testing.T) {
+	cases := []struct {
+		expr     Expr
+		expected string
+	}{
+		{Field("age").Gt(18), `age > 18`},
+		{Field("age").Ge(18).And(Field("age").Lt(60)), `age >= 18 and age < 60`},
+		{Field("age").Gt(18).And(Field("tag").In("a", "b")), `age > 18 and tag in ["a","b"]`},
+		{Field("id").In([]int64{1, 2, 3}), `id in [1,2,3]`},
+		{Field("id").NotIn(uint8(1)), `id not in [1]`},
+		{Field("score").Le(float32(1.5)).Or(Field("score").Eq(2.0)), `score <= 1.5 or score == 2.0`},
+		{Field("ok").Ne(true), `ok != true`},
+		{Field("title").Eq(`say "hi" \ bye`), `title == "say \"hi\" \\ bye"`},
+		{Field("title").Like("ab%"), `title like "ab%"`},
+		// only quote, backslash and line breaks are escaped, other characters are kept as is
+		{Field("title").Eq("a\nb\r\tc é\u00a0\x01"), "title == \"a\\nb\\r\tc é\u00a0\x01\""},
+		{Field("meta").Key("a").Index(0).Eq(1), `meta["a"][0] == 1`},
+		{Field("meta").Path("a.b[1][2]").Eq("x"), `meta["a"]["b"][1][2] == "x"`},
+		{Field("meta").Key(`k"ey`).Exists(), `exists meta["k\"ey"]`},
+		{Field("tags").ArrayContains("x"), `array_contains(tags, "x")`},
+		{Field("tags").ArrayContainsAll("x", "y"), `array_contains_all(tags, ["x","y"])`},
+		{Field("tags").ArrayContainsAny([]string{"x"}), `array_contains_any(tags, ["x"])`},
+		{Field("a").Eq(1).And(Field("b").Eq(2)).Or(Field("c").Eq(3)), `(a == 1 and b == 2) or c == 3`},
+		{Not(Field("a").Eq(1)), `not a == 1`},
+		{Field("a").Eq(1).Or(Field("b").Eq(2)).Not(), `not (a == 1 or b == 2)`},
+		{And(Raw("a > 1"), Field("b").Eq(2)), `(a > 1) and b == 2`},
+		{And(Field("a").Eq(1)), `a == 1`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.expected, func(t *testing.T) {
+			result, err := c.expr.Build()
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, result)
+			assert.Equal(t, c.expected, c.expr.String())
+		})
+	}
+}
+
+func TestBuildError(t *testing.T) {
+	cases := map[string]Expr{
+		"invalid_name":   Field("a b").Eq(1),
+		"invalid_value":  Field("a").Eq(struct{}{}),
+		"nan":            Field("a").Eq(math.NaN()),
+		"empty_list":     Field("a").In(),
+		"invalid_path":   Field("a").Path("b[x]").Eq(1),
+		"negative_index": Field("a").Index(-1).Eq(1),
+		"nested_error":   Field("a").Eq(1).And(Field("b").In()),
+		"not_error":      Not(Field("b").In()),
+		"empty_and":      And(),
+	}
+	for name, e := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := e.Build()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	sch := entity.NewSchema().WithName("test").
+		WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true)).
+		WithField(entity.NewField().WithName("title").WithDataType(entity.FieldTypeVarChar)).
+		WithField(entity.NewField().WithName("score").WithDataType(entity.FieldTypeDouble)).
+		WithField(entity.NewField().WithName("ok").WithDataType(entity.FieldTypeBool)).
+		WithField(entity.NewField().WithName("meta").WithDataType(entity.FieldTypeJSON)).
+		WithField(entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithDim(2))
+
+	valid := []Expr{
+		Field("id").In(1, 2),
+		Field("title").Like("a%").And(Field("score").Gt(1)),
+		Field("score").Lt(1.5),
+		Field("ok").Eq(false),
+		Field("meta").Key("a").Eq("x"),
+		Field("meta").Key("tags").ArrayContains(1),
+		Field("meta").Key("a").Exists(),
+		Raw("anything"),
+	}
+	for _, e := range valid {
+		assert.NoError(t, e.Validate(sch), e.String())
+	}
+
+	invalid := []Expr{
+		Field("not_exist").Eq(1),
+		Field("id").Eq("1"),
+		Field("id").Gt(1.5),
+		Field("title").In(1),
+		Field("ok").Eq(1),
+		Field("id").Like("1%"),
+		Field("vector").Eq(1),
+		Field("meta").Eq(1),
+		Field("id").Key("a").Eq(1),
+		Field("title").ArrayContains("a"),
+		Field("title").Exists(),
+		Field("id").Eq(1).And(Field("title").Eq(1)),
+		Field("a b").Eq(1),
+	}
+	for _, e := range invalid {
+		assert.Error(t, e.Validate(sch), e.String())
+	}
+
+	_, err := Field("id").Eq(1).BuildFor(nil)
+	assert.Error(t, err)
+	result, err := Field("id").Eq(1).BuildFor(sch)
+	assert.NoError(t, err)
+	assert.Equal(t, "id == 1", result)
+
+	// dynamic keys are not checked
+	sch.WithDynamicFieldEnabled(true)
+	assert.NoError(t, Field("not_exist").Key("a").Eq(1).Validate(sch))
+}
@@ -0,0 +1,682 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Node is a node of parsed expression tree.
+type Node interface {
+	// String returns the expression text of the node.
+	String() string
+}
+
+// Logical is `Left and Right` or `Left or Right`.
+type Logical struct {
+	Op    string // "and" or "or"
+	Left  Node
+	Right Node
+}
+
+func (n *Logical) String() string {
+	return fmt.Sprintf("(%s %s %s)", n.Left, n.Op, n.Right)
+}
+
+// NotNode is the negation `not Expr`.
+type NotNode struct {
+	Expr Node
+}
+
+func (n *NotNode) String() string {
+	return fmt.Sprintf("not %s", n.Expr)
+}
+
+// Compare is binary comparison, Op is one of ==, !=, <, <=, >, >=.
+type Compare struct {
+	Op    string
+	Left  Node
+	Right Node
+}
+
+func (n *Compare) String() string {
+	return fmt.Sprintf("%s %s %s", n.Left, n.Op, n.Right)
+}
+
+// Arith is binary arithmetic operation, Op is one of +, -, *, /, %.
+type Arith struct {
+	Op    string
+	Left  Node
+	Right Node
+}
+
+func (n *Arith) String() string {
+	return fmt.Sprintf("(%s %s %s)", n.Left, n.Op, n.Right)
+}
+
+// InNode is `Target in Values` or `Target not in Values`.
+type InNode struct {
+	Target Node
+	Values *List
+	Not    bool
+}
+
+func (n *InNode) String() string {
+	if n.Not {
+		return fmt.Sprintf("%s not in %s", n.Target, n.Values)
+	}
+	return fmt.Sprintf("%s in %s", n.Target, n.Values)
+}
+
+// LikeNode is `Target like Pattern`.
+type LikeNode struct {
+	Target  Node
+	Pattern string
+}
+
+func (n *LikeNode) String() string {
+	return fmt.Sprintf("%s like %s", n.Target, quote(n.Pattern))
+}
+
+// ExistsNode is `exists Target`.
+type ExistsNode struct {
+	Target *Ident
+}
+
+func (n *ExistsNode) String() string {
+	return fmt.Sprintf("exists %s", n.Target)
+}
+
+// Call is function call like `array_contains(a, 1)`, Func is in lower case.
+type Call struct {
+	Func string
+	Args []Node
+}
+
+func (n *Call) String() string {
+	args := make([]string, 0, len(n.Args))
+	for _, arg := range n.Args {
+		args = append(args, arg.String())
+	}
+	return fmt.Sprintf("%s(%s)", n.Func, strings.Join(args, ", "))
+}
+
+// Ident refers to field Name, with optional json Path of string keys and int indices.
+type Ident struct {
+	Name string
+	Path []interface{}
+}
+
+func (n *Ident) String() string {
+	var sb strings.Builder
+	sb.WriteString(n.Name)
+	for _, p := range n.Path {
+		switch p := p.(type) {
+		case string:
+			sb.WriteString("[" + quote(p) + "]")
+		case int:
+			sb.WriteString("[" + strconv.Itoa(p) + "]")
+		}
+	}
+	return sb.String()
+}
+
+// Literal is a constant value of bool, int64, float64 or string.
+type Literal struct {
+	Value interface{}
+}
+
+func (n *Literal) String() string {
+	switch v := n.Value.(type) {
+	case string:
+		return quote(v)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// List is list of literals like `[1, 2]`.
+type List struct {
+	Values []Node
+}
+
+func (n *List) String() string {
+	values := make([]string, 0, len(n.Values))
+	for _, v := range n.Values {
+		values = append(values, v.String())
+	}
+	return "[" + strings.Join(values, ",") + "]"
+}
+
+// functions lists supported functions and argument count.
+var functions = map[string]int{
+	"array_contains":     2,
+	"array_contains_all": 2,
+	"array_contains_any": 2,
+	"json_contains":      2,
+	"json_contains_all":  2,
+	"json_contains_any":  2,
+	"array_length":       1,
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokInt
+	tokFloat
+	tokString
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string // raw text, or unquoted value for string
+	pos  int
+}
+
+func lex(input string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(input) {
+		ch := input[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case isIdentStart(ch):
+			start := i
+			for i < len(input) && isIdentPart(input[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: input[start:i], pos: start})
+		case ch >= '0' && ch <= '9' || (ch == '.' && i+1 < len(input) && input[i+1] >= '0' && input[i+1] <= '9'):
+			start := i
+			kind := tokInt
+			for i < len(input) && input[i] >= '0' && input[i] <= '9' {
+				i++
+			}
+			if i < len(input) && input[i] == '.' {
+				kind = tokFloat
+				i++
+				for i < len(input) && input[i] >= '0' && input[i] <= '9' {
+					i++
+				}
+			}
+			if i < len(input) && (input[i] == 'e' || input[i] == 'E') {
+				kind = tokFloat
+				i++
+				if i < len(input) && (input[i] == '+' || input[i] == '-') {
+					i++
+				}
+				for i < len(input) && input[i] >= '0' && input[i] <= '9' {
+					i++
+				}
+			}
+			tokens = append(tokens, token{kind: kind, text: input[start:i], pos: start})
+		case ch == '"' || ch == '\'':
+			start := i
+			i++
+			for i < len(input) && input[i] != ch {
+				if input[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(input) {
+				return nil, fmt.Errorf("unterminated string at %d", start)
+			}
+			i++
+			value, err := unquote(input[start:i])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at %d: %w", start, err)
+			}
+			tokens = append(tokens, token{kind: tokString, text: value, pos: start})
+		default:
+			op := ""
+			if i+1 < len(input) {
+				switch two := input[i : i+2]; two {
+				case "==", "!=", "<=", ">=", "&&", "||":
+					op = two
+				}
+			}
+			if op == "" && strings.IndexByte("<>!+-*/%()[],", ch) >= 0 {
+				op = string(ch)
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at %d", ch, i)
+			}
+			tokens = append(tokens, token{kind: tokOp, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(input)}), nil
+}
+
+func isIdentStart(ch byte) bool {
+	return ch == '_' || ch == '$' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+func isIdentPart(ch byte) bool {
+	return isIdentStart(ch) || (ch >= '0' && ch <= '9')
+}
+
+// unquote decodes double or single quoted string literal.
+func unquote(s string) (string, error) {
+	if s[0] == '"' {
+		return strconv.Unquote(s)
+	}
+	// convert single quoted literal into double quoted one
+	var sb strings.Builder
+	sb.WriteByte('"')
+	body := s[1 : len(s)-1]
+	for i := 0; i < len(body); i++ {
+		switch {
+		case body[i] == '\\' && i+1 < len(body) && body[i+1] == '\'':
+			sb.WriteByte('\'')
+			i++
+		case body[i] == '\\' && i+1 < len(body):
+			sb.WriteByte('\\')
+			sb.WriteByte(body[i+1])
+			i++
+		case body[i] == '"':
+			sb.WriteString(`\"`)
+		default:
+			sb.WriteByte(body[i])
+		}
+	}
+	sb.WriteByte('"')
+	return strconv.Unquote(sb.String())
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// Parse parses boolean expression into expression tree.
+//
+// Supported syntax includes logical operators `and`, `or`, `not` (or `&&`, `||`, `!`),
+// comparisons and range comparisons like `1 < a <= 5`, arithmetic operators, `in`, `not in`, `like`,
+// `exists`, json paths like `meta["a"][0]` and functions array_contains, array_contains_all,
+// array_contains_any (json_contains variants as aliases) and array_length.
+func Parse(input string) (Node, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorf(tok, "unexpected %q", tok.text)
+	}
+	return node, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) errorf(tok token, format string, args ...interface{}) error {
+	return fmt.Errorf("parse error at %d: %s", tok.pos, fmt.Sprintf(format, args...))
+}
+
+// isKeyword checks whether token is the case-insensitive keyword.
+func (tok token) isKeyword(keyword string) bool {
+	return tok.kind == tokIdent && strings.EqualFold(tok.text, keyword)
+}
+
+func (tok token) isOp(ops ...string) bool {
+	if tok.kind != tokOp {
+		return false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) expectOp(op string) error {
+	tok := p.next()
+	if !tok.isOp(op) {
+		return p.errorf(tok, "expect %q, got %q", op, tok.text)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for tok := p.peek(); tok.isOp("||") || tok.isKeyword("or"); tok = p.peek() {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: "or", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for tok := p.peek(); tok.isOp("&&") || tok.isKeyword("and"); tok = p.peek() {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: "and", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (Node, error) {
+	if tok := p.peek(); tok.isOp("!") || tok.isKeyword("not") {
+		p.next()
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &NotNode{Expr: expr}, nil
+	}
+	return p.parsePredicate()
+}
+
+func (p *parser) parsePredicate() (Node, error) {
+	if tok := p.peek(); tok.isKeyword("exists") {
+		p.next()
+		operand, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		ident, ok := operand.(*Ident)
+		if !ok {
+			return nil, p.errorf(tok, "exists requires a field")
+		}
+		return &ExistsNode{Target: ident}, nil
+	}
+
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.peek()
+	switch {
+	case tok.isKeyword("in"):
+		p.next()
+		return p.parseIn(left, false)
+	case tok.isKeyword("not") && p.tokens[p.pos+1].isKeyword("in"):
+		p.pos += 2
+		return p.parseIn(left, true)
+	case tok.isKeyword("like"):
+		p.next()
+		pattern := p.next()
+		if pattern.kind != tokString {
+			return nil, p.errorf(pattern, "like requires a string pattern")
+		}
+		return &LikeNode{Target: left, Pattern: pattern.text}, nil
+	case tok.isOp("==", "!=", "<", "<=", ">", ">="):
+		var node Node
+		for tok.isOp("==", "!=", "<", "<=", ">", ">=") {
+			p.next()
+			right, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			// range comparison `a < b < c` is `a < b and b < c`
+			cmp := &Compare{Op: tok.text, Left: left, Right: right}
+			if node == nil {
+				node = cmp
+			} else {
+				node = &Logical{Op: "and", Left: node, Right: cmp}
+			}
+			left = right
+			tok = p.peek()
+		}
+		return node, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseIn(target Node, not bool) (Node, error) {
+	tok := p.peek()
+	values, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	list, ok := values.(*List)
+	if !ok {
+		return nil, p.errorf(tok, "in requires a list")
+	}
+	return &InNode{Target: target, Values: list, Not: not}, nil
+}
+
+func (p *parser) parseAdditive() (Node, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for tok := p.peek(); tok.isOp("+", "-"); tok = p.peek() {
+		p.next()
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &Arith{Op: tok.text, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseMultiplicative() (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for tok := p.peek(); tok.isOp("*", "/", "%"); tok = p.peek() {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &Arith{Op: tok.text, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	tok := p.peek()
+	if !tok.isOp("-", "+") {
+		return p.parsePrimary()
+	}
+	p.next()
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	lit, ok := operand.(*Literal)
+	if !ok {
+		return nil, p.errorf(tok, "unary %s only applies to number literal", tok.text)
+	}
+	switch v := lit.Value.(type) {
+	case int64:
+		if tok.text == "-" {
+			v = -v
+		}
+		return &Literal{Value: v}, nil
+	case float64:
+		if tok.text == "-" {
+			v = -v
+		}
+		return &Literal{Value: v}, nil
+	}
+	return nil, p.errorf(tok, "unary %s only applies to number literal", tok.text)
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokInt:
+		v, err := strconv.ParseInt(tok.text, 10, 64)
+		if err != nil {
+			return nil, p.errorf(tok, "invalid integer %s", tok.text)
+		}
+		return &Literal{Value: v}, nil
+	case tokFloat:
+		v, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, p.errorf(tok, "invalid float %s", tok.text)
+		}
+		return &Literal{Value: v}, nil
+	case tokString:
+		return &Literal{Value: tok.text}, nil
+	case tokIdent:
+		switch {
+		case tok.isKeyword("true"):
+			return &Literal{Value: true}, nil
+		case tok.isKeyword("false"):
+			return &Literal{Value: false}, nil
+		}
+		for _, keyword := range []string{"and", "or", "not", "in", "like", "exists"} {
+			if tok.isKeyword(keyword) {
+				return nil, p.errorf(tok, "unexpected keyword %q", tok.text)
+			}
+		}
+		if p.peek().isOp("(") {
+			return p.parseCall(tok)
+		}
+		return p.parseIdent(tok)
+	case tokOp:
+		switch tok.text {
+		case "(":
+			node, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectOp(")"); err != nil {
+				return nil, err
+			}
+			return node, nil
+		case "[":
+			return p.parseList()
+		}
+	}
+	if tok.kind == tokEOF {
+		return nil, p.errorf(tok, "unexpected end of expression")
+	}
+	return nil, p.errorf(tok, "unexpected %q", tok.text)
+}
+
+func (p *parser) parseIdent(tok token) (Node, error) {
+	ident := &Ident{Name: tok.text}
+	for p.peek().isOp("[") {
+		p.next()
+		key := p.next()
+		switch key.kind {
+		case tokString:
+			ident.Path = append(ident.Path, key.text)
+		case tokInt:
+			idx, err := strconv.Atoi(key.text)
+			if err != nil {
+				return nil, p.errorf(key, "invalid index %s", key.text)
+			}
+			ident.Path = append(ident.Path, idx)
+		default:
+			return nil, p.errorf(key, "json path element shall be string or integer, got %q", key.text)
+		}
+		if err := p.expectOp("]"); err != nil {
+			return nil, err
+		}
+	}
+	return ident, nil
+}
+
+func (p *parser) parseCall(tok token) (Node, error) {
+	name := strings.ToLower(tok.text)
+	argc, ok := functions[name]
+	if !ok {
+		return nil, p.errorf(tok, "unknown function %s", tok.text)
+	}
+	p.next() // (
+	call := &Call{Func: name}
+	if !p.peek().isOp(")") {
+		for {
+			arg, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			call.Args = append(call.Args, arg)
+			if !p.peek().isOp(",") {
+				break
+			}
+			p.next()
+		}
+	}
+	if err := p.expectOp(")"); err != nil {
+		return nil, err
+	}
+	if len(call.Args) != argc {
+		return nil, p.errorf(tok, "function %s requires %d arguments, got %d", name, argc, len(call.Args))
+	}
+	return call, nil
+}
+
+func (p *parser) parseList() (Node, error) {
+	list := &List{}
+	if p.peek().isOp("]") {
+		p.next()
+		return list, nil
+	}
+	for {
+		tok := p.peek()
+		value, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		switch value.(type) {
+		case *Literal, *List:
+		default:
+			return nil, p.errorf(tok, "list element shall be literal")
+		}
+		list.Values = append(list.Values, value)
+		sep := p.next()
+		if sep.isOp("]") {
+			return list, nil
+		}
+		if !sep.isOp(",") {
+			return nil, p.errorf(sep, "expect \",\" or \"]\", got %q", sep.text)
+		}
+	}
+}
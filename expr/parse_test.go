@@ -0,0 +1,144 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package expr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+	}{
+		{`age > 18`, `age > 18`},
+		{`age >= 18 AND age < 60 || ok`, `((age >= 18 and age < 60) or ok)`},
+		{`a or b and c`, `(a or (b and c))`},
+		{`(a or b) && !c`, `((a or b) and not c)`},
+		{`1 < age <= 5`, `(1 < age and age <= 5)`},
+		{`id in [1, 2, -3]`, `id in [1,2,-3]`},
+		{`id not in ["a", 'b\'c']`, `id not in ["a","b'c"]`},
+		{`not id in [1]`, `not id in [1]`},
+		{`title like "ab%"`, `title like "ab%"`},
+		{`t == "\u00e9\n\"\\"`, `t == "é\n\"\\"`},
+		{`meta["a"][0] == 1.5`, `meta["a"][0] == 1.5`},
+		{`$meta["k"] != "x"`, `$meta["k"] != "x"`},
+		{`exists meta["a"]`, `exists meta["a"]`},
+		{`ARRAY_CONTAINS(tags, 1)`, `array_contains(tags, 1)`},
+		{`json_contains_any(tags, [1, 2])`, `json_contains_any(tags, [1,2])`},
+		{`array_length(tags) == 2`, `array_length(tags) == 2`},
+		{`a + b * 2 - 1 > 0`, `((a + (b * 2)) - 1) > 0`},
+		{`a % 2 == 1e2`, `(a % 2) == 100`},
+		{`ok == True`, `ok == true`},
+	}
+	for _, c := range cases {
+		node, err := Parse(c.input)
+		require.NoError(t, err, c.input)
+		assert.Equal(t, c.expected, node.String(), c.input)
+	}
+
+	node, err := Parse(`meta["a"][1] in [1]`)
+	require.NoError(t, err)
+	in := node.(*InNode)
+	assert.Equal(t, &Ident{Name: "meta", Path: []interface{}{"a", 1}}, in.Target)
+	assert.Equal(t, &Literal{Value: int64(1)}, in.Values.Values[0])
+
+	invalid := []string{
+		``,
+		`age >`,
+		`(age > 1`,
+		`age > 1)`,
+		`age in 1`,
+		`age in [a]`,
+		`title like 1`,
+		`unknown_func(a)`,
+		`array_contains(a)`,
+		`exists 1`,
+		`a["b"`,
+		`a[b] == 1`,
+		`"unterminated`,
+		`a # 1`,
+		`-a > 1`,
+		`a and or b`,
+	}
+	for _, input := range invalid {
+		_, err := Parse(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	sch := entity.NewSchema().WithName("test").
+		WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true)).
+		WithField(entity.NewField().WithName("title").WithDataType(entity.FieldTypeVarChar)).
+		WithField(entity.NewField().WithName("score").WithDataType(entity.FieldTypeDouble)).
+		WithField(entity.NewField().WithName("ok").WithDataType(entity.FieldTypeBool)).
+		WithField(entity.NewField().WithName("meta").WithDataType(entity.FieldTypeJSON)).
+		WithField(entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithDim(2))
+
+	valid := []string{
+		`id in [1, 2] and title like "a%"`,
+		`score < 1`,
+		`id + 1 > score * 2`,
+		`ok`,
+		`not ok == false`,
+		`meta["a"] == "x" or meta["b"][0] > 1`,
+		`array_contains(meta["tags"], 1)`,
+		`array_contains_all(meta, [1, 2])`,
+		`array_length(meta["tags"]) >= 1`,
+		`exists meta["a"]`,
+		`meta["flag"]`,
+		`$meta["a"] == 1`,
+	}
+	for _, input := range valid {
+		_, err := ParseFor(input, sch)
+		assert.NoError(t, err, input)
+	}
+
+	invalid := []string{
+		`not_exist == 1`,
+		`id == "1"`,
+		`id > 1.5`,
+		`id in [1.5]`,
+		`title in [1]`,
+		`ok == 1`,
+		`ok > false`,
+		`id like "1%"`,
+		`vector == 1`,
+		`meta == 1`,
+		`id["a"] == 1`,
+		`array_contains(title, "a")`,
+		`array_contains_any(meta, 1)`,
+		`exists title`,
+		`id`,
+		`id + "a" > 1`,
+		`score % 2 == 1`,
+		`id == 1 and title`,
+	}
+	for _, input := range invalid {
+		_, err := ParseFor(input, sch)
+		assert.Error(t, err, input)
+	}
+
+	_, err := ParseFor(`id == 1`, nil)
+	assert.Error(t, err)
+
+	// keys of dynamic field are not checked
+	sch.WithDynamicFieldEnabled(true)
+	_, err = ParseFor(`not_exist["a"] == 1 and other like "x%"`, sch)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package expr
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+// Validate checks field names and value types used in the expression against schema.
+// Fields not in schema are allowed only when dynamic field is enabled, in which case their types are not checked.
+// Expressions created by Raw are not validated.
+func (e Expr) Validate(sch *entity.Schema) error {
+	if e.err != nil {
+		return e.err
+	}
+	if sch == nil {
+		return errors.New("schema cannot be nil")
+	}
+	fields := make(map[string]*entity.Field, len(sch.Fields))
+	for _, field := range sch.Fields {
+		fields[field.Name] = field
+	}
+	for _, ref := range e.refs {
+		if err := validateReference(ref, fields, sch.EnableDynamicField); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BuildFor validates the expression against schema and returns the expression string.
+func (e Expr) BuildFor(sch *entity.Schema) (string, error) {
+	if err := e.Validate(sch); err != nil {
+		return "", err
+	}
+	return e.text, nil
+}
+
+func validateReference(ref reference, fields map[string]*entity.Field, dynamicEnabled bool) error {
+	field, ok := fields[ref.field]
+	if !ok || field.IsDynamic {
+		if ref.field == "$meta" || dynamicEnabled {
+			return nil
+		}
+		return fmt.Errorf("field %s not found in schema", ref.field)
+	}
+
+	switch field.DataType {
+	case entity.FieldTypeFloatVector, entity.FieldTypeBinaryVector:
+		return fmt.Errorf("vector field %s cannot be used in filter expression", field.Name)
+	case entity.FieldTypeJSON:
+		if !ref.hasPath && ref.op != opExists {
+			return fmt.Errorf("json field %s shall be used with a path", field.Name)
+		}
+		return nil
+	}
+
+	if ref.hasPath {
+		return fmt.Errorf("field %s of type %s does not support json path", field.Name, field.DataType.Name())
+	}
+	switch ref.op {
+	case opExists:
+		return fmt.Errorf("exists is not supported on field %s of type %s", field.Name, field.DataType.Name())
+	case opArrayContains:
+		return fmt.Errorf("array_contains is not supported on field %s of type %s", field.Name, field.DataType.Name())
+	case opLike:
+		if field.DataType != entity.FieldTypeVarChar && field.DataType != entity.FieldTypeString {
+			return fmt.Errorf("like is not supported on field %s of type %s", field.Name, field.DataType.Name())
+		}
+	}
+	for _, kind := range ref.kinds {
+		if !kindMatches(field.DataType, kind) {
+			return fmt.Errorf("field %s of type %s cannot be compared with %s value", field.Name, field.DataType.Name(), kind)
+		}
+	}
+	return nil
+}
+
+func kindMatches(dataType entity.FieldType, kind valueKind) bool {
+	switch dataType {
+	case entity.FieldTypeBool:
+		return kind == kindBool
+	case entity.FieldTypeInt8, entity.FieldTypeInt16, entity.FieldTypeInt32, entity.FieldTypeInt64:
+		return kind == kindInt
+	case entity.FieldTypeFloat, entity.FieldTypeDouble:
+		return kind == kindInt || kind == kindFloat
+	case entity.FieldTypeVarChar, entity.FieldTypeString:
+		return kind == kindString
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+// Package objectstore provides the in-memory implementation of client.ObjectStore,
+// which uploads bulk insert files to the object storage Milvus reads from.
+// The S3 compatible implementation is in the standalone objectstore/s3 module.
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Memory keeps objects in memory, used as fake store in tests.
+type Memory struct {
+	mut     sync.RWMutex
+	objects map[string][]byte
+}
+
+// NewMemory creates an empty Memory store.
+func NewMemory() *Memory {
+	return &Memory{objects: make(map[string][]byte)}
+}
+
+// Put stores object content, the returned path is the key.
+func (m *Memory) Put(ctx context.Context, key string, r io.Reader, size int64) (string, error) {
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, r); err != nil {
+		return "", err
+	}
+	if size >= 0 && int64(buf.Len()) != size {
+		return "", fmt.Errorf("object %s size %d does not match expected %d", key, buf.Len(), size)
+	}
+	m.mut.Lock()
+	defer m.mut.Unlock()
+	m.objects[key] = buf.Bytes()
+	return key, nil
+}
+
+// Delete removes object of the path, deleting non-existing object is not an error.
+func (m *Memory) Delete(ctx context.Context, objectPath string) error {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+	delete(m.objects, objectPath)
+	return nil
+}
+
+// Get returns the content of object.
+func (m *Memory) Get(objectPath string) ([]byte, bool) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	bs, ok := m.objects[objectPath]
+	return bs, ok
+}
+
+// Paths returns the sorted paths of all objects.
+func (m *Memory) Paths() []string {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	paths := make([]string, 0, len(m.objects))
+	for p := range m.objects {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
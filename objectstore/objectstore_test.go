@@ -0,0 +1,44 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package objectstore
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+
+	p, err := m.Put(ctx, "a/b.json", strings.NewReader("data"), 4)
+	require.NoError(t, err)
+	assert.Equal(t, "a/b.json", p)
+	bs, ok := m.Get(p)
+	assert.True(t, ok)
+	assert.Equal(t, "data", string(bs))
+
+	_, err = m.Put(ctx, "a/c.json", strings.NewReader("data"), 5)
+	assert.Error(t, err)
+	_, err = m.Put(ctx, "a/a.json", strings.NewReader(""), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a/a.json", "a/b.json"}, m.Paths())
+
+	assert.NoError(t, m.Delete(ctx, p))
+	assert.NoError(t, m.Delete(ctx, "not_exist"))
+	_, ok = m.Get(p)
+	assert.False(t, ok)
+}
@@ -0,0 +1,80 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+// Package s3 provides the S3 compatible client.ObjectStore, like MinIO deployed with Milvus.
+//
+// s3 is a standalone module, so the minio dependency is only pulled in by its users.
+package s3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+)
+
+var _ client.ObjectStore = (*Store)(nil)
+
+// Config is the config of S3 compatible storage, like MinIO deployed with Milvus.
+type Config struct {
+	Endpoint        string // host:port without scheme
+	AccessKeyID     string
+	SecretAccessKey string
+	UseSSL          bool
+	Region          string
+	Bucket          string // bucket configured as `minio.bucketName` of Milvus
+	RootPath        string // optional key prefix of uploaded objects
+}
+
+// Store uploads objects to S3 compatible storage.
+type Store struct {
+	client   *minio.Client
+	bucket   string
+	rootPath string
+}
+
+// New creates Store with provided config.
+func New(cfg Config) (*Store, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("bucket cannot be empty")
+	}
+	mc, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Store{client: mc, bucket: cfg.Bucket, rootPath: cfg.RootPath}, nil
+}
+
+// Put uploads object, the returned path is the object key in bucket.
+func (s *Store) Put(ctx context.Context, key string, r io.Reader, size int64) (string, error) {
+	objectPath := path.Join(s.rootPath, key)
+	_, err := s.client.PutObject(ctx, s.bucket, objectPath, r, size, minio.PutObjectOptions{
+		ContentType: "application/octet-stream",
+	})
+	if err != nil {
+		return "", err
+	}
+	return objectPath, nil
+}
+
+// Delete removes object of the path.
+func (s *Store) Delete(ctx context.Context, objectPath string) error {
+	return s.client.RemoveObject(ctx, s.bucket, objectPath, minio.RemoveObjectOptions{})
+}
@@ -0,0 +1,71 @@
+// Copyright (C) 2019-2021 Zilliz. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the License
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+// or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package s3
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	var mut sync.Mutex
+	objects := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mut.Lock()
+		defer mut.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			bs, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = string(bs)
+			w.Header().Set("ETag", `"etag"`)
+			w.WriteHeader(http.StatusOK)
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+
+	_, err := New(Config{Endpoint: strings.TrimPrefix(server.URL, "http://")})
+	assert.Error(t, err)
+
+	store, err := New(Config{
+		Endpoint:        strings.TrimPrefix(server.URL, "http://"),
+		AccessKeyID:     "minioadmin",
+		SecretAccessKey: "minioadmin",
+		Region:          "us-east-1",
+		Bucket:          "a-bucket",
+		RootPath:        "files",
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	p, err := store.Put(ctx, "bulk/1.json", strings.NewReader(`{"rows":[]}`), 11)
+	require.NoError(t, err)
+	assert.Equal(t, "files/bulk/1.json", p)
+	// payload is chunk signed over plain http
+	require.Contains(t, objects, "/a-bucket/files/bulk/1.json")
+	assert.Contains(t, objects["/a-bucket/files/bulk/1.json"], `{"rows":[]}`)
+
+	require.NoError(t, store.Delete(ctx, p))
+	assert.Empty(t, objects)
+}
@@ -20,3 +20,13 @@ for d in $(go list ./... | grep -v vendor/examples/tests); do
     fi
 done
 
+
+# standalone modules keeping heavy dependencies out of the sdk module
+for m in arrowconv objectstore/s3; do
+    echo $m
+    (cd "$m" && go test -race -coverprofile=coverage.out -covermode=atomic ./... -v)
+    if [[ -f "$m/coverage.out" ]]; then
+        tail -n +2 "$m/coverage.out" >> coverage.project.out
+        rm "$m/coverage.out"
+    fi
+done